@@ -79,6 +79,49 @@ func getContext(gid ...int64) *context {
 	return ctx
 }
 
+// checkContext 检查当前 goroutine 的上下文及模型的注册信息，write 表示是否为写操作。
+// 返回 goroutine ID、上下文、模型信息，检查失败时返回 ErrNoContext、ErrNotRegistered 或 ErrNotWritable。
+func checkContext(model IModel, write bool) (int64, *context, *modelMeta, error) {
+	gid := goid.Get()
+	ctx := getContext(gid)
+	if ctx == nil {
+		return gid, nil, nil, ErrNoContext
+	}
+	meta := getModelMeta(model)
+	if meta == nil {
+		return gid, ctx, nil, fmt.Errorf("%w: %v", ErrNotRegistered, model.ModelUnique())
+	}
+	if write {
+		if !ctx.writable {
+			return gid, ctx, meta, fmt.Errorf("%w: context is read-only", ErrNotWritable)
+		}
+		if !meta.writable {
+			return gid, ctx, meta, fmt.Errorf("%w: model of %v is read-only", ErrNotWritable, model.ModelUnique())
+		}
+	}
+	return gid, ctx, meta, nil
+}
+
+// parseWritableAndCond 解析 Read 及 List 的可变参数，返回是否可写及查询条件，writable 为默认的可写标记。
+// source 为调用的函数名，strict 为 true 时参数类型错误将返回错误，否则记录严重错误并忽略该参数。
+func parseWritableAndCond(source string, writable, strict bool, writableAndCond []any) (bool, *Condition, error) {
+	var cond *Condition
+	for _, v := range writableAndCond {
+		switch nv := v.(type) {
+		case bool:
+			writable = nv
+		case *Condition:
+			cond = nv
+		default:
+			if strict {
+				return writable, cond, fmt.Errorf("writableAndCond of %v type is error", v)
+			}
+			XLog.Critical("%v: writableAndCond of %v type is error: %v", source, v, XLog.Caller(3, false))
+		}
+	}
+	return writable, cond, nil
+}
+
 // Watch 开始 CRUD 操作监控。
 // writable 是可选的，默认为 true（读写模式），设置为 false 则为只读模式。
 // 函数获取当前 goroutine ID，生成新的会话 ID，从对象池获取上下文实例并初始化（记录开始时间和设置读写模式）。
@@ -7,9 +7,7 @@ package XOrm
 import (
 	"sync"
 
-	"github.com/eframework-org/GO.UTIL/XLog"
 	"github.com/eframework-org/GO.UTIL/XTime"
-	"github.com/petermattis/goid"
 )
 
 // Clear 根据条件批量标记数据模型为清除状态。
@@ -24,26 +22,19 @@ import (
 // 需要注意的是，清除操作是软删除，不会立即从内存中移除数据，被标记清除的数据在读取时会被忽略。
 // 该函数是线程不安全的，操作相同的数据模型时，需要控制并发或使用适当的同步方式（如：Mutex）以确保操作的正确性。
 func Clear[T IModel](model T, cond ...*Condition) {
+	if err := ClearE(model, cond...); err != nil {
+		logContextError("XOrm.Clear", err)
+	}
+}
+
+// ClearE 根据条件批量标记数据模型为清除状态，规则与 Clear 相同。
+// 返回发生的错误，如 ErrNoContext、ErrNotRegistered 及 ErrNotWritable。
+func ClearE[T IModel](model T, cond ...*Condition) error {
 	cacheDumpWait.Wait()
 
-	gid := goid.Get()
-	ctx := getContext(gid)
-	if ctx == nil {
-		XLog.Critical("XOrm.Clear: context was not found: %v", XLog.Caller(1, false))
-		return
-	}
-	meta := getModelMeta(model)
-	if meta == nil {
-		XLog.Critical("XOrm.Clear: model of %v was not registered: %v", model.ModelUnique(), XLog.Caller(1, false))
-		return
-	}
-	if !ctx.writable {
-		XLog.Error("XOrm.Clear: context was not writable.")
-		return
-	}
-	if !meta.writable {
-		XLog.Error("XOrm.Clear: model of %v was not writable.", model.ModelUnique())
-		return
+	gid, ctx, meta, err := checkContext(model, true)
+	if err != nil {
+		return err
 	}
 
 	time := XTime.GetMicrosecond()
@@ -92,4 +83,5 @@ func Clear[T IModel](model T, cond ...*Condition) {
 	}
 	sobj.create = false
 	sobj.delete = false
+	return nil
 }
@@ -316,17 +316,20 @@ func (cb *commitBatch) handle(sobj *sessionObject, queueID int) {
 	}
 
 	action := ""
+	meta := getModelMeta(obj)
 	if sobj.create {
-		obj.Write()
+		syncPairsCache(meta, obj, obj.Write() < 0) // 同步至二级缓存，写入失败时移除
 		action = "create"
 	} else if sobj.delete {
 		obj.Delete()
+		syncPairsCache(meta, obj, true)
 		action = "delete"
 	} else if sobj.clear != nil {
 		obj.Clear(sobj.clear)
+		clearPairsCache(meta, obj)
 		action = "clear"
 	} else {
-		obj.Write()
+		syncPairsCache(meta, obj, obj.Write() < 0) // 同步至二级缓存，写入失败时移除
 		action = "update"
 	}
 
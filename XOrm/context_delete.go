@@ -5,9 +5,7 @@
 package XOrm
 
 import (
-	"github.com/eframework-org/GO.UTIL/XLog"
 	"github.com/eframework-org/GO.UTIL/XTime"
-	"github.com/petermattis/goid"
 )
 
 // Delete 标记数据模型为删除状态。
@@ -20,26 +18,19 @@ import (
 // 需要注意的是，删除操作是软删除，不会立即从内存中移除数据，被标记删除的数据在读取时会被忽略。
 // 该函数是线程不安全的，操作相同的数据模型时，需要控制并发或使用适当的同步方式（如：Mutex）以确保操作的正确性。
 func Delete[T IModel](model T) {
+	if err := DeleteE(model); err != nil {
+		logContextError("XOrm.Delete", err)
+	}
+}
+
+// DeleteE 标记数据模型为删除状态，规则与 Delete 相同。
+// 返回发生的错误，如 ErrNoContext、ErrNotRegistered 及 ErrNotWritable。
+func DeleteE[T IModel](model T) error {
 	cacheDumpWait.Wait()
 
-	gid := goid.Get()
-	ctx := getContext(gid)
-	if ctx == nil {
-		XLog.Critical("XOrm.Delete: context was not found: %v", XLog.Caller(1, false))
-		return
-	}
-	meta := getModelMeta(model)
-	if meta == nil {
-		XLog.Critical("XOrm.Delete: model of %v was not registered: %v", model.ModelUnique(), XLog.Caller(1, false))
-		return
-	}
-	if !ctx.writable {
-		XLog.Error("XOrm.Delete: context was not writable.")
-		return
-	}
-	if !meta.writable {
-		XLog.Error("XOrm.Delete: model of %v was not writable.", model.ModelUnique())
-		return
+	gid, ctx, meta, err := checkContext(model, true)
+	if err != nil {
+		return err
 	}
 
 	time := XTime.GetMicrosecond()
@@ -64,4 +55,5 @@ func Delete[T IModel](model T) {
 	sobj.delete = true
 	sobj.create = false
 	sobj.clear = nil
+	return nil
 }
@@ -6,44 +6,84 @@ package XOrm
 
 import (
 	"fmt"
+	"sync"
 	"sync/atomic"
 
 	"github.com/eframework-org/GO.UTIL/XLog"
 	"github.com/eframework-org/GO.UTIL/XTime"
-	"github.com/petermattis/goid"
 )
 
+// IAllocator 定义了自增值的分配器，通过 SetAllocator 为模型的指定列选择，未选择时使用进程内的默认分配器。
+// 实现需要是线程安全的。
+type IAllocator interface {
+	// Allocate 为模型的指定列分配 delta 个值，返回分配的最大值（即自增后的新值），delta 不小于 1。
+	Allocate(model IModel, column string, delta int) (int64, error)
+}
+
+// globalAllocatorMap 存储模型的列选择的分配器，键为模型标识 + 列名。
+var globalAllocatorMap sync.Map
+
+// SetAllocator 为模型的指定列选择自增值的分配器。
+// column 为可选的列名，若不指定则使用主键列；allocator 为 nil 时恢复使用默认分配器。
+// 如果模型未注册或未指定列名且模型无主键，将触发 panic。
+//
+// 使用示例：
+//
+//	XOrm.SetAllocator(NewUser(), XOrm.NewHiLoAllocator("xorm_sequence", 100)) // 主键使用号段分配
+//	XOrm.SetAllocator(NewMail(), XOrm.NewPairsAllocator("Main"), "seq")      // seq 列使用 XPairs 计数器
+func SetAllocator(model IModel, allocator IAllocator, column ...string) {
+	meta := getModelMeta(model)
+	if meta == nil {
+		XLog.Panic("XOrm.SetAllocator: model was not registered.")
+		return
+	}
+	cname := ""
+	if len(column) > 0 {
+		cname = column[0]
+	} else if meta.fields.pk != nil {
+		cname = meta.fields.pk.column
+	}
+	if cname == "" {
+		XLog.Panic("XOrm.SetAllocator: column of %v was empty.", model.ModelUnique())
+		return
+	}
+	key := increKey(model, cname)
+	if allocator == nil {
+		globalAllocatorMap.Delete(key)
+	} else {
+		globalAllocatorMap.Store(key, allocator)
+	}
+}
+
 // Incre 获取并自增指定列的最大值。model 参数为要操作的数据模型，必须实现 IModel 接口。
 // columnAndDelta 为可变参数，支持多种组合：无参数时自增主键且增量为 1；一个参数时，若为字符串则
 // 指定列名且增量为 1，若为整数则使用主键并指定增量；两个参数时，第一个为列名（字符串），第二个为增量（整数）。
 //
-// 函数首先获取或创建模型的最大值缓存，然后解析参数确定目标列名和增量值。如果未指定列名，会尝试使用主键列，若无主键则报错。
-// 获取当前最大值时优先使用缓存的值，如果缓存不存在，则从远端数据获取，最后计算并缓存新值。
-// 函数返回自增后的新值，如果列名为空，则返回 0。
+// 函数解析参数确定目标列名和增量值，如果未指定列名，会尝试使用主键列，若无主键则报错。
+// 自增值由 SetAllocator 为该列选择的分配器分配，未选择时使用默认分配器：
+// 首次获取时从远端数据获取最大值，之后在内存中原子地自增。
+// 函数返回自增后的新值，如果列名为空，则返回 0，如果发生错误则返回 -1。
 //
-// 需要注意的是，缓存的最大值在程序重启后会重置。
-// 该函数是线程安全的，可以确保单实例内的数据唯一性。
+// 需要注意的是，默认分配器缓存的最大值在程序重启后会重置，仅可以确保单实例内的数据唯一性，
+// 多个实例写入同一数据表时请选择 HiLoAllocator、PairsAllocator 或 SnowflakeAllocator。
+// 该函数是线程安全的。
 func Incre(model IModel, columnAndDelta ...any) int {
+	index, err := IncreE(model, columnAndDelta...)
+	if err != nil {
+		logContextError("XOrm.Incre", err)
+	}
+	return int(index)
+}
+
+// IncreE 获取并自增指定列的最大值，规则与 Incre 相同。
+// 返回 int64 类型的自增后的新值（分配器的值如雪花算法可能超出 32 位整型的范围），
+// 及发生的错误，如 ErrNoContext、ErrNotRegistered、ErrNotWritable、远端查询及分配器的错误。
+func IncreE(model IModel, columnAndDelta ...any) (int64, error) {
 	cacheDumpWait.Wait()
 
-	gid := goid.Get()
-	ctx := getContext(gid)
-	if ctx == nil {
-		XLog.Critical("XOrm.Incre: context was not found: %v", XLog.Caller(1, false))
-		return -1
-	}
-	meta := getModelMeta(model)
-	if meta == nil {
-		XLog.Critical("XOrm.Incre: model of %v was not registered: %v", model.ModelUnique(), XLog.Caller(1, false))
-		return -1
-	}
-	if !ctx.writable {
-		XLog.Error("XOrm.Incre: context was not writable.")
-		return -1
-	}
-	if !meta.writable {
-		XLog.Error("XOrm.Incre: model of %v was not writable.", model.ModelUnique())
-		return -1
+	_, ctx, meta, err := checkContext(model, true)
+	if err != nil {
+		return -1, err
 	}
 
 	time := XTime.GetMicrosecond()
@@ -77,24 +117,52 @@ func Incre(model IModel, columnAndDelta ...any) int {
 		}
 	}
 	if cname == "" {
-		XLog.Error("XOrm.Incre: column was empty: %v", model.ModelUnique())
-		return 0
+		return 0, fmt.Errorf("column of %v was empty", model.ModelUnique())
 	}
 
-	increKey := fmt.Sprintf("%v_%v", model.ModelUnique(), cname)
-	if val, ok := globalIncreMap.Load(increKey); !ok {
-		defer globalIncreMutex.Unlock()
-		globalIncreMutex.Lock()
-		if nval, ok := globalIncreMap.Load(increKey); ok {
-			return int(atomic.AddInt64(nval.(*int64), int64(delta)))
-		} else {
-			index := model.Max([]string{cname}...)
-			index += delta
-			lindex := int64(index)
-			globalIncreMap.Store(increKey, &lindex)
-			return index
+	var allocator IAllocator = localAllocator{}
+	if val, ok := globalAllocatorMap.Load(increKey(model, cname)); ok {
+		allocator = val.(IAllocator)
+		if delta < 1 { // 分布式的分配器仅支持分配新的值，否则将返回已分配的值
+			return -1, fmt.Errorf("delta %v of %v must be positive for %T", delta, model.ModelUnique(), allocator)
 		}
-	} else {
-		return int(atomic.AddInt64(val.(*int64), int64(delta)))
 	}
+	return allocator.Allocate(model, cname, delta)
+}
+
+// localAllocator 是进程内的默认分配器，首次分配时通过模型的 Max 获取最大值（故模型重写的 Max 同样生效），之后在 globalIncreMap 中原子地自增。
+type localAllocator struct{}
+
+// Allocate 为模型的指定列分配 delta 个值，返回自增后的新值。
+func (localAllocator) Allocate(model IModel, column string, delta int) (int64, error) {
+	key := increKey(model, column)
+	if val, ok := globalIncreMap.Load(key); ok {
+		return atomic.AddInt64(val.(*int64), int64(delta)), nil
+	}
+	defer globalIncreMutex.Unlock()
+	globalIncreMutex.Lock()
+	if val, ok := globalIncreMap.Load(key); ok {
+		return atomic.AddInt64(val.(*int64), int64(delta)), nil
+	}
+	mval, err := increMax(model, column)
+	if err != nil {
+		return -1, err
+	}
+	index := mval + int64(delta)
+	globalIncreMap.Store(key, &index)
+	return index, nil
+}
+
+// increKey 返回自增值的键，为模型标识 + 列名。
+func increKey(model IModel, column string) string {
+	return fmt.Sprintf("%v_%v", model.ModelUnique(), column)
+}
+
+// increMax 通过模型的 Max 获取指定列的最大值，空表返回 0，Max 返回 -1 时视为查询失败。
+func increMax(model IModel, column string) (int64, error) {
+	mval := model.Max(column)
+	if mval < 0 {
+		return -1, fmt.Errorf("max of %v.%v failed", model.ModelUnique(), column)
+	}
+	return int64(mval), nil
 }
@@ -0,0 +1,139 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// HiLoAllocator 是基于序列表的号段分配器。
+// 每次从模型所在数据库的序列表中原子地预留 size 个值（号段），号段内的值在进程内分配，号段耗尽时再预留下一个号段。
+// 序列表包含 name（模型标识 + 列名）及 value（已预留的最大值）两列，分配时不会自动创建，
+// 需在部署时执行 Migrate 或等价的 DDL 创建，序列不存在时以远端数据中该列的最大值为起始值。
+//
+// 多个实例共享序列表时分配的值全局唯一，但实例间的值交错，实例重启时未使用的号段将被跳过，故值是唯一的但不连续。
+type HiLoAllocator struct {
+	table  string
+	size   int64
+	blocks sync.Map // 进程内的号段，键为模型标识 + 列名，值为 *hiloBlock
+}
+
+// hiloBlock 定义了进程内的号段，(next, max] 为尚未分配的值。
+type hiloBlock struct {
+	next  int64
+	max   int64
+	mutex sync.Mutex
+}
+
+// NewHiLoAllocator 创建号段分配器。
+// table 为序列表的名称，size 为每次预留的号段大小，小于 1 时视为 1。
+// size 越大访问数据库的次数越少，但实例重启时跳过的值越多。
+func NewHiLoAllocator(table string, size int) *HiLoAllocator {
+	return &HiLoAllocator{table: table, size: int64(max(size, 1))}
+}
+
+// Migrate 在指定别名的数据库中创建序列表，序列表已存在时不做任何操作。
+// 该函数应当在部署或启动时显式调用（与 orm.RunSyncdb 类似），对应的 DDL（MySQL）为：
+//
+//	CREATE TABLE IF NOT EXISTS `<table>` (`name` VARCHAR(255) NOT NULL PRIMARY KEY, `value` BIGINT NOT NULL)
+func (a *HiLoAllocator) Migrate(alias string) error {
+	ormer := orm.NewOrmUsingDB(alias)
+	if ormer == nil {
+		return fmt.Errorf("failed to create orm instance of %v", alias)
+	}
+	quote := tableQuote(ormer.Driver().Type())
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s VARCHAR(255) NOT NULL PRIMARY KEY, %s BIGINT NOT NULL)",
+		quote+a.table+quote, quote+"name"+quote, quote+"value"+quote)
+	if _, err := ormer.Raw(query).Exec(); err != nil {
+		return fmt.Errorf("create sequence table %v failed: %w", a.table, err)
+	}
+	return nil
+}
+
+// Allocate 为模型的指定列分配 delta 个值，返回自增后的新值，当前号段不足时预留新的号段。
+func (a *HiLoAllocator) Allocate(model IModel, column string, delta int) (int64, error) {
+	key := increKey(model, column)
+	val, _ := a.blocks.LoadOrStore(key, &hiloBlock{})
+	return val.(*hiloBlock).take(int64(delta), a.size, func(n int64) (int64, error) {
+		return a.reserve(model, column, key, n)
+	})
+}
+
+// take 从号段中分配 delta 个值，号段不足时调用 reserve 预留至少 size 个值，剩余的值将被丢弃以保证分配的值连续。
+func (block *hiloBlock) take(delta, size int64, reserve func(n int64) (int64, error)) (int64, error) {
+	block.mutex.Lock()
+	defer block.mutex.Unlock()
+	if block.next+delta > block.max {
+		n := max(size, delta)
+		hi, err := reserve(n)
+		if err != nil {
+			return -1, err
+		}
+		block.next, block.max = hi-n, hi
+	}
+	block.next += delta
+	return block.next, nil
+}
+
+// reserve 在事务中将序列增加 n 并返回增加后的值，序列不存在时以远端数据中该列的最大值为起始值插入，序列表不存在时返回数据库的错误。
+// 多个实例同时插入同一序列时，主键冲突的实例将重试一次更新。
+func (a *HiLoAllocator) reserve(model IModel, column, name string, n int64) (int64, error) {
+	meta := getModelMeta(model)
+	if meta == nil {
+		return -1, fmt.Errorf("%w: %v", ErrNotRegistered, model.ModelUnique())
+	}
+	ormer := orm.NewOrmUsingDB(model.AliasName())
+	if ormer == nil {
+		return -1, fmt.Errorf("failed to create orm instance of %v", model.AliasName())
+	}
+	quote := tableQuote(ormer.Driver().Type())
+	table := quote + a.table + quote
+	cname, cvalue := quote+"name"+quote, quote+"value"+quote
+
+	var err error
+	for range 2 {
+		var tx orm.TxOrmer
+		if tx, err = ormer.Begin(); err != nil {
+			return -1, err
+		}
+		var value int64
+		if value, err = a.increase(tx, model, column, name, n, table, cname, cvalue); err != nil {
+			tx.Rollback()
+			continue
+		}
+		if err = tx.Commit(); err != nil {
+			return -1, err
+		}
+		return value, nil
+	}
+	return -1, fmt.Errorf("reserve sequence %v failed: %w", name, err)
+}
+
+// increase 在事务 tx 中将序列增加 n 并读取增加后的值，序列不存在时插入。
+func (a *HiLoAllocator) increase(tx orm.TxOrmer, model IModel, column, name string, n int64, table, cname, cvalue string) (int64, error) {
+	ret, err := tx.Raw(fmt.Sprintf("UPDATE %s SET %s = %s + ? WHERE %s = ?", table, cvalue, cvalue, cname), n, name).Exec()
+	if err != nil {
+		return -1, err
+	}
+	if affected, err := ret.RowsAffected(); err != nil {
+		return -1, err
+	} else if affected == 0 {
+		mval, err := increMax(model, column)
+		if err != nil {
+			return -1, err
+		}
+		if _, err := tx.Raw(fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (?, ?)", table, cname, cvalue), name, mval+n).Exec(); err != nil {
+			return -1, err
+		}
+	}
+	var value int64
+	if err := tx.Raw(fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", cvalue, table, cname), name).QueryRow(&value); err != nil {
+		return -1, err
+	}
+	return value, nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/petermattis/goid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHiLoBlock 测试号段的分配及预留。
+func TestHiLoBlock(t *testing.T) {
+	// 模拟多个实例共享的序列表
+	var sequence int64
+	var reserves int
+	var mutex sync.Mutex
+	reserve := func(n int64) (int64, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		reserves++
+		sequence += n
+		return sequence, nil
+	}
+
+	block := &hiloBlock{}
+	for i := int64(1); i <= 10; i++ {
+		value, err := block.take(1, 10, reserve)
+		assert.NoError(t, err, "take 不应当返回错误。")
+		assert.Equal(t, i, value, "号段内的值应当连续分配。")
+	}
+	assert.Equal(t, 1, reserves, "号段未耗尽时不应当预留新的号段。")
+
+	value, _ := block.take(25, 10, reserve)
+	assert.Equal(t, int64(35), value, "增量大于号段大小时应当预留增量大小的号段。")
+	value, _ = block.take(1, 10, reserve)
+	assert.Equal(t, int64(36), value, "号段耗尽后应当预留新的号段。")
+	assert.Equal(t, 3, reserves, "预留的次数应当和预期相等。")
+
+	// 多个实例并发分配
+	var wg sync.WaitGroup
+	var vmutex sync.Mutex
+	values := make(map[int64]bool)
+	for range 4 {
+		instance := &hiloBlock{}
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 1000 {
+					value, err := instance.take(1, 100, reserve)
+					assert.NoError(t, err, "take 不应当返回错误。")
+					vmutex.Lock()
+					values[value] = true
+					vmutex.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	assert.Len(t, values, 16000, "多个实例分配的值应当全局唯一。")
+
+	cause := errors.New("connection refused")
+	failed := &hiloBlock{}
+	_, err := failed.take(1, 10, func(n int64) (int64, error) { return 0, cause })
+	assert.ErrorIs(t, err, cause, "预留失败时应当返回原始的错误。")
+	value, _ = failed.take(1, 10, reserve)
+	assert.Equal(t, sequence-9, value, "预留失败后应当重新预留。")
+}
+
+// TestHiLoAllocator 测试基于序列表的号段分配器。
+func TestHiLoAllocator(t *testing.T) {
+	defer ResetContext()
+	defer ResetBaseTest()
+
+	ResetContext()
+	ResetBaseTest()
+	SetupBaseTest(true, true)
+	WriteBaseTest(1000)
+
+	model := NewTestBaseModel()
+	ormer := orm.NewOrmUsingDB(model.AliasName())
+	ormer.Raw("DROP TABLE IF EXISTS `xorm_sequence_test`").Exec()
+	defer ormer.Raw("DROP TABLE IF EXISTS `xorm_sequence_test`").Exec()
+
+	// 模拟两个实例
+	a1 := NewHiLoAllocator("xorm_sequence_test", 10)
+	a2 := NewHiLoAllocator("xorm_sequence_test", 10)
+	_, err := a1.Allocate(model, "id", 1)
+	assert.Error(t, err, "序列表不存在时 Allocate 应当返回错误，而非自动创建。")
+	assert.NoError(t, a1.Migrate(model.AliasName()), "Migrate 不应当返回错误。")
+	assert.NoError(t, a2.Migrate(model.AliasName()), "序列表已存在时 Migrate 不应当返回错误。")
+	SetAllocator(model, a1)
+	defer SetAllocator(model, nil)
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	values := make(map[int]bool)
+	for i := range 100 {
+		wg.Add(1)
+		go func(j int) {
+			defer wg.Done()
+
+			gid := goid.Get()
+			ctx := &context{writable: true}
+			contextMap.Store(gid, ctx)
+			defer contextMap.Delete(gid)
+
+			var value int
+			if j%2 == 0 {
+				value = Incre(model)
+			} else {
+				nvalue, err := a2.Allocate(model, "id", 1)
+				assert.NoError(t, err, "Allocate 不应当返回错误。")
+				value = int(nvalue)
+			}
+			mutex.Lock()
+			values[value] = true
+			mutex.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, values, 100, "多个实例分配的值应当全局唯一。")
+	for value := range values {
+		assert.Greater(t, value, 1000, "分配的值应当大于已存在的最大值。")
+	}
+	var reserved int64
+	ormer.Raw("SELECT `value` FROM `xorm_sequence_test` WHERE `name` = ?", increKey(model, "id")).QueryRow(&reserved)
+	assert.GreaterOrEqual(t, reserved, int64(1100), "序列表应当记录已预留的最大值。")
+	_, ok := globalIncreMap.Load(increKey(model, "id"))
+	assert.False(t, ok, "使用号段分配器时不应当使用默认分配器。")
+}
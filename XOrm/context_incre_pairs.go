@@ -0,0 +1,58 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"sync"
+
+	"github.com/eframework-org/GO.CRUD/XPairs"
+)
+
+// pairsIncreKey 定义了 XPairs 计数器的哈希表键名，字段为模型标识 + 列名。
+const pairsIncreKey = pairsCachePrefix + "Incre"
+
+// PairsAllocator 是基于 XPairs 存储的计数器分配器，使用哈希表的 HIncrBy 原子地自增，计数器存储于 XOrm/Incre 哈希表中。
+// 存储需要实现 XPairs.IHash 接口（如 Redis 及内存存储），否则分配时返回 XPairs.ErrNotSupported。
+//
+// 进程内首次分配时将计数器与远端数据中该列的最大值对比，计数器落后时（如首次使用或存储被清空）跳过已存在的值，
+// 故多个实例共享存储时分配的值全局唯一且连续递增（跳过的值除外）。
+type PairsAllocator struct {
+	alias  string
+	seeded sync.Map // 已对比最大值的计数器，键为模型标识 + 列名
+}
+
+// NewPairsAllocator 创建 XPairs 计数器分配器，alias 为 XPairs 存储的别名，存储在分配时按别名获取。
+func NewPairsAllocator(alias string) *PairsAllocator {
+	return &PairsAllocator{alias: alias}
+}
+
+// Allocate 为模型的指定列分配 delta 个值，返回自增后的新值。
+// 存储未注册时返回 XPairs.ErrNotRegistered，存储未实现 XPairs.IHash 时返回 XPairs.ErrNotSupported。
+func (a *PairsAllocator) Allocate(model IModel, column string, delta int) (int64, error) {
+	hash, err := XPairs.Hash(a.alias)
+	if err != nil {
+		return -1, err
+	}
+	field := increKey(model, column)
+	value, err := hash.HIncrBy(pairsIncreKey, field, int64(delta))
+	if err != nil {
+		return -1, err
+	}
+	if _, ok := a.seeded.Load(field); ok {
+		return value, nil
+	}
+	mval, err := increMax(model, column)
+	if err != nil {
+		return -1, err
+	}
+	if value-int64(delta) < mval {
+		// 计数器增加 max(mval, delta) 后，本次分配的值均大于 mval，且与其他实例分配的值不重叠
+		if value, err = hash.HIncrBy(pairsIncreKey, field, max(mval, int64(delta))); err != nil {
+			return -1, err
+		}
+	}
+	a.seeded.Store(field, true)
+	return value, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/eframework-org/GO.CRUD/XPairs"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPairsAllocatorError 测试 XPairs 计数器分配器的存储错误。
+func TestPairsAllocatorError(t *testing.T) {
+	model := NewTestBaseModel()
+	_, err := NewPairsAllocator("orm_incre_missing").Allocate(model, "id", 1)
+	assert.ErrorIs(t, err, XPairs.ErrNotRegistered, "存储未注册时应当返回 ErrNotRegistered。")
+
+	// 仅实现 IPairs 的存储，用于模拟 Consul 的行为
+	XPairs.Register("orm_incre_nohash", struct{ XPairs.IPairs }{XPairs.NewMemory()})
+	defer XPairs.Of("orm_incre_nohash").Close()
+	_, err = NewPairsAllocator("orm_incre_nohash").Allocate(model, "id", 1)
+	assert.ErrorIs(t, err, XPairs.ErrNotSupported, "存储未实现 IHash 时应当返回 ErrNotSupported。")
+}
+
+// TestPairsAllocator 测试基于 XPairs 存储的计数器分配器。
+func TestPairsAllocator(t *testing.T) {
+	defer ResetContext()
+	defer ResetBaseTest()
+
+	ResetContext()
+	ResetBaseTest()
+	SetupBaseTest(true, true)
+	WriteBaseTest(1000)
+
+	XPairs.Register("orm_incre_test", XPairs.NewMemory())
+	defer XPairs.Of("orm_incre_test").Close()
+	hash, _ := XPairs.Hash("orm_incre_test")
+	model := NewTestBaseModel()
+
+	// 计数器落后于远端数据时跳过已存在的值
+	hash.HIncrBy(pairsIncreKey, increKey(model, "int_val"), 10)
+	a1 := NewPairsAllocator("orm_incre_test")
+	value, err := a1.Allocate(model, "int_val", 2)
+	assert.NoError(t, err, "Allocate 不应当返回错误。")
+	assert.Greater(t, value-2, int64(1000), "分配的值应当大于已存在的最大值。")
+	next, _ := a1.Allocate(model, "int_val", 2)
+	assert.Equal(t, value+2, next, "对比最大值后应当直接自增。")
+
+	// 模拟多个实例
+	a2 := NewPairsAllocator("orm_incre_test")
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	values := make(map[int64]bool)
+	for i := range 100 {
+		wg.Add(1)
+		go func(j int) {
+			defer wg.Done()
+			a := a1
+			if j%2 == 0 {
+				a = a2
+			}
+			value, err := a.Allocate(model, "id", 1)
+			assert.NoError(t, err, "Allocate 不应当返回错误。")
+			mutex.Lock()
+			values[value] = true
+			mutex.Unlock()
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, values, 100, "多个实例分配的值应当全局唯一。")
+	for value := range values {
+		assert.Greater(t, value, int64(1000), "分配的值应当大于已存在的最大值。")
+	}
+	fields, _ := hash.HGetAll(pairsIncreKey)
+	assert.Contains(t, fields, increKey(model, "id"), "计数器应当存储于 XOrm/Incre 哈希表中。")
+}
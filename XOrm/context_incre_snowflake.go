@@ -0,0 +1,64 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// SnowflakeNodeBits 是雪花算法中节点编号的位数，节点编号的范围为 [0, 1023]。
+	SnowflakeNodeBits = 10
+
+	// SnowflakeSequenceBits 是雪花算法中毫秒内序号的位数，每个节点每毫秒最多分配 4096 个值。
+	SnowflakeSequenceBits = 12
+)
+
+// snowflakeEpoch 是雪花算法的起始时间（2025-01-01 00:00:00 UTC）的毫秒时间戳，41 位的时间部分可以使用约 69 年。
+var snowflakeEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+// SnowflakeAllocator 是雪花算法的分配器，值由 41 位的毫秒时间、10 位的节点编号及 12 位的毫秒内序号组成。
+// 各实例使用不同的节点编号时分配的值全局唯一，且同一实例内严格递增，无需访问数据库或存储。
+//
+// 值不连续且与远端数据中已存在的值无关，适用于新建的数据表或值域远大于已有数据的列。
+// delta 个值在同一毫秒内连续分配，故 delta 的范围为 [1, 4096]。
+// 时钟回拨或同一毫秒内的序号耗尽时继续使用（借用）最近一次分配的时间，以保证实例内的值不重复。
+type SnowflakeAllocator struct {
+	node  int64
+	last  int64 // 最近一次分配的毫秒时间，相对于起始时间
+	seq   int64 // 最近一次分配的毫秒内序号
+	mutex sync.Mutex
+}
+
+// NewSnowflakeAllocator 创建雪花算法的分配器，node 为实例的节点编号，多个实例之间不能重复。
+// 返回创建的分配器，节点编号超出 [0, 1023] 时返回错误。
+func NewSnowflakeAllocator(node int) (*SnowflakeAllocator, error) {
+	if node < 0 || node >= 1<<SnowflakeNodeBits {
+		return nil, fmt.Errorf("snowflake node %v was out of range [0, %v]", node, 1<<SnowflakeNodeBits-1)
+	}
+	return &SnowflakeAllocator{node: int64(node), last: -1}, nil
+}
+
+// Allocate 在同一毫秒内分配 delta 个连续的值，返回其中的最大值，model 及 column 将被忽略。
+// delta 超出 [1, 4096] 时返回错误。
+func (a *SnowflakeAllocator) Allocate(model IModel, column string, delta int) (int64, error) {
+	if delta < 1 || delta > 1<<SnowflakeSequenceBits {
+		return -1, fmt.Errorf("snowflake delta %v was out of range [1, %v]", delta, 1<<SnowflakeSequenceBits)
+	}
+	n := int64(delta)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	now := time.Now().UnixMilli() - snowflakeEpoch
+	if now > a.last {
+		a.last, a.seq = now, n-1
+	} else if a.seq+n < 1<<SnowflakeSequenceBits {
+		a.seq += n
+	} else { // 序号不足时借用下一毫秒
+		a.last, a.seq = a.last+1, n-1
+	}
+	return a.last<<(SnowflakeNodeBits+SnowflakeSequenceBits) | a.node<<SnowflakeSequenceBits | a.seq, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.UTIL/XObject"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSnowflakeAllocator 测试雪花算法的分配器。
+func TestSnowflakeAllocator(t *testing.T) {
+	for _, node := range []int{-1, 1024} {
+		_, err := NewSnowflakeAllocator(node)
+		assert.Error(t, err, "超出范围的节点编号应当返回错误：%v", node)
+	}
+
+	t.Run("Unique", func(t *testing.T) {
+		a1, _ := NewSnowflakeAllocator(1)
+		a2, _ := NewSnowflakeAllocator(2)
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		values := make(map[int64]bool)
+		for _, a := range []*SnowflakeAllocator{a1, a2} {
+			for range 4 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					last := int64(-1)
+					for range 5000 {
+						value, err := a.Allocate(nil, "", 1)
+						assert.NoError(t, err, "Allocate 不应当返回错误。")
+						assert.Greater(t, value, last, "同一分配器分配的值应当递增。")
+						last = value
+						mutex.Lock()
+						values[value] = true
+						mutex.Unlock()
+					}
+				}()
+			}
+		}
+		wg.Wait()
+		assert.Len(t, values, 40000, "不同节点分配的值应当全局唯一。")
+	})
+
+	t.Run("Layout", func(t *testing.T) {
+		a, _ := NewSnowflakeAllocator(5)
+		before := time.Now().UnixMilli() - snowflakeEpoch
+		value, _ := a.Allocate(nil, "", 1)
+		ms := value >> (SnowflakeNodeBits + SnowflakeSequenceBits)
+		assert.GreaterOrEqual(t, ms, before, "时间部分应当不早于分配前的时间。")
+		assert.LessOrEqual(t, ms, time.Now().UnixMilli()-snowflakeEpoch, "时间部分应当不晚于分配后的时间。")
+		assert.Equal(t, int64(5), value>>SnowflakeSequenceBits&(1<<SnowflakeNodeBits-1), "节点部分应当和节点编号相等。")
+	})
+
+	t.Run("Clock", func(t *testing.T) {
+		a, _ := NewSnowflakeAllocator(0)
+		future := time.Now().UnixMilli() - snowflakeEpoch + time.Hour.Milliseconds()
+		a.last, a.seq = future, 0
+
+		// 时钟回拨时继续使用最近一次分配的时间
+		value, _ := a.Allocate(nil, "", 1)
+		assert.Equal(t, future<<(SnowflakeNodeBits+SnowflakeSequenceBits)|1, value, "时钟回拨时应当递增序号。")
+
+		// 序号耗尽时借用下一毫秒
+		a.seq = 1<<SnowflakeSequenceBits - 1
+		value, _ = a.Allocate(nil, "", 1)
+		assert.Equal(t, (future+1)<<(SnowflakeNodeBits+SnowflakeSequenceBits), value, "序号耗尽时应当使用下一毫秒。")
+	})
+
+	t.Run("Delta", func(t *testing.T) {
+		a, _ := NewSnowflakeAllocator(0)
+		for _, delta := range []int{-1, 0, 1<<SnowflakeSequenceBits + 1} {
+			_, err := a.Allocate(nil, "", delta)
+			assert.Error(t, err, "超出范围的增量应当返回错误：%v", delta)
+		}
+
+		future := time.Now().UnixMilli() - snowflakeEpoch + time.Hour.Milliseconds()
+		a.last, a.seq = future, 0
+		value, err := a.Allocate(nil, "", 3)
+		assert.NoError(t, err, "范围内的增量不应当返回错误。")
+		assert.Equal(t, future<<(SnowflakeNodeBits+SnowflakeSequenceBits)|3, value, "应当预留增量个序号并返回其中的最大值。")
+
+		a.seq = 1<<SnowflakeSequenceBits - 2
+		value, _ = a.Allocate(nil, "", 2)
+		assert.Equal(t, (future+1)<<(SnowflakeNodeBits+SnowflakeSequenceBits)|1, value, "序号不足时应当在下一毫秒内预留。")
+	})
+
+	t.Run("IncreE", func(t *testing.T) {
+		defer orm.ResetModelCache()
+		orm.ResetModelCache()
+		model := XObject.New[TestSQLModel]()
+		Meta(model, false, true)
+		a, _ := NewSnowflakeAllocator(0)
+		SetAllocator(model, a)
+		defer SetAllocator(model, nil)
+
+		Watch()
+		defer Defer()
+		for _, delta := range []int{0, -1} {
+			index, err := IncreE(model, delta)
+			assert.Error(t, err, "选择分配器时增量小于 1 应当返回错误：%v", delta)
+			assert.Equal(t, int64(-1), index, "发生错误时 IncreE 应当返回 -1。")
+		}
+		index, err := IncreE(model)
+		assert.NoError(t, err, "增量为 1 时不应当返回错误。")
+		assert.Greater(t, index, int64(0), "分配的值应当大于 0。")
+	})
+}
@@ -9,6 +9,8 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.UTIL/XObject"
 	"github.com/petermattis/goid"
 	"github.com/stretchr/testify/assert"
 )
@@ -72,3 +74,87 @@ func TestContextIncre(t *testing.T) {
 		})
 	}
 }
+
+// TestAllocator 是记录调用参数的测试分配器。
+type TestAllocator struct {
+	column string
+	delta  int
+	err    error
+}
+
+func (a *TestAllocator) Allocate(model IModel, column string, delta int) (int64, error) {
+	a.column, a.delta = column, delta
+	return 100, a.err
+}
+
+// TestContextIncreAllocator 测试按模型及列选择分配器。
+func TestContextIncreAllocator(t *testing.T) {
+	defer ResetContext()
+	defer ResetBaseTest()
+
+	ResetContext()
+	ResetBaseTest()
+	SetupBaseTest(true, true)
+
+	model := NewTestBaseModel()
+	allocator := &TestAllocator{}
+	SetAllocator(model, allocator)
+	SetAllocator(model, allocator, "int_val")
+	defer SetAllocator(model, nil)
+	defer SetAllocator(model, nil, "int_val")
+
+	Watch()
+	defer Defer()
+
+	assert.Equal(t, 100, Incre(model), "主键应当使用选择的分配器。")
+	assert.Equal(t, "id", allocator.column, "未指定列名时应当使用主键列。")
+	assert.Equal(t, 100, Incre(model, "int_val", 2), "int_val 应当使用选择的分配器。")
+	assert.Equal(t, 2, allocator.delta, "增量应当传递至分配器。")
+
+	allocator.err = fmt.Errorf("allocate failed")
+	_, err := IncreE(model)
+	assert.ErrorIs(t, err, allocator.err, "分配器的错误应当被返回。")
+	assert.Equal(t, -1, Incre(model), "分配失败时应当返回 -1。")
+
+	SetAllocator(model, nil)
+	assert.Equal(t, 1, Incre(model), "恢复默认分配器后应当从远端数据的最大值自增。")
+	assert.Panics(t, func() { SetAllocator(&TestCacheModel1{}, allocator) }, "模型未注册时应当触发 panic。")
+}
+
+// TestIncreModel 是重写了 Max 的测试模型，用于验证默认分配器通过模型的 Max 获取起始值。
+type TestIncreModel struct {
+	Model[TestIncreModel] `orm:"-" json:"-"`
+	ID                    int `orm:"column(id);pk"`
+}
+
+// testIncreMax 是 TestIncreModel 的 Max 返回的最大值。
+var testIncreMax int
+
+func (m *TestIncreModel) AliasName() string { return "incre_alias" }
+
+func (m *TestIncreModel) TableName() string { return "incre_table" }
+
+func (m *TestIncreModel) Max(column ...string) int { return testIncreMax }
+
+// TestContextIncreMax 测试默认分配器使用模型重写的 Max 作为起始值。
+func TestContextIncreMax(t *testing.T) {
+	defer orm.ResetModelCache()
+	orm.ResetModelCache()
+	model := XObject.New[TestIncreModel]()
+	Meta(model, false, true)
+	defer globalIncreMap.Delete(increKey(model, "id"))
+
+	Watch()
+	defer Defer()
+
+	testIncreMax = -1
+	index, err := IncreE(model)
+	assert.Error(t, err, "Max 返回 -1 时应当返回错误。")
+	assert.Equal(t, int64(-1), index, "发生错误时 IncreE 应当返回 -1。")
+
+	testIncreMax = 41
+	index, err = IncreE(model)
+	assert.NoError(t, err, "IncreE 不应当返回错误。")
+	assert.Equal(t, int64(42), index, "默认分配器应当从模型的 Max 返回的最大值自增。")
+	assert.Equal(t, 44, Incre(model, 2), "之后应当在内存中自增。")
+}
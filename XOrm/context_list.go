@@ -5,11 +5,11 @@
 package XOrm
 
 import (
+	"slices"
 	"sync"
 
 	"github.com/eframework-org/GO.UTIL/XLog"
 	"github.com/eframework-org/GO.UTIL/XTime"
-	"github.com/petermattis/goid"
 )
 
 // List 获取数据模型的列表。model 参数为要查询的数据模型，必须实现 IModel 接口。
@@ -18,26 +18,35 @@ import (
 // 函数首先验证模型是否已注册，然后解析参数获取可写标记和查询条件。查询数据时按照优先级依次从会话内存、全局内存和远端数据获取。
 // 会话内存查询会过滤掉已标记删除的数据并应用查询条件；
 // 全局内存查询会克隆数据到会话内存并处理覆盖数据；
-// 远端数据查询会将数据同步到全局内存（如果启用缓存）和会话内存，并处理删除标记以确保数据一致性。
+// 远端数据查询会将数据同步到全局内存（如果启用缓存）、二级缓存（如果配置）和会话内存，并处理删除标记以确保数据一致性。
 // 对于远端查询结果，函数会检查并使用会话内存和全局内存中的最新数据，移除被标记删除的数据，并添加仅在会话内存或全局内存中的匹配数据作为补充同步。
-// 函数返回满足条件的数据模型切片，已被标记删除的数据将被过滤。
+// 函数返回满足条件的数据模型切片，已被标记删除的数据将被过滤；若条件指定了排序规则（orderby）或分页参数（limit/offset），
+// 结果将按照该规则排序（分页时默认按主键排序）后分页，内存与远端读取的结果保持一致。
 //
 // 需要注意的是，返回的数据是原始数据的克隆，非条件列举可能导致数据不同步，建议避免在异步操作期间进行条件列举。
 // 该函数是线程安全的，可以确保单实例内的数据一致性。
 func List[T IModel](model T, writableAndCond ...any) []T {
+	frets, err := list(model, false, writableAndCond...)
+	if err != nil {
+		logContextError("XOrm.List", err)
+	}
+	return frets
+}
+
+// ListE 获取数据模型的列表，查询规则与 List 相同。
+// 返回满足条件的数据模型切片及发生的错误，如 ErrNoContext、ErrNotRegistered、参数类型及远端查询的错误，发生错误时返回空切片。
+func ListE[T IModel](model T, writableAndCond ...any) ([]T, error) {
+	return list(model, true, writableAndCond...)
+}
+
+// list 获取数据模型的列表，strict 为 true 时参数类型错误将返回错误，否则记录严重错误并忽略该参数。
+func list[T IModel](model T, strict bool, writableAndCond ...any) ([]T, error) {
 	cacheDumpWait.Wait()
 
 	frets := make([]T, 0)
-	gid := goid.Get()
-	ctx := getContext(gid)
-	if ctx == nil {
-		XLog.Critical("XOrm.List: context was not found: %v", XLog.Caller(1, false))
-		return frets
-	}
-	meta := getModelMeta(model)
-	if meta == nil {
-		XLog.Critical("XOrm.List: model of %v was not registered: %v", model.ModelUnique(), XLog.Caller(1, false))
-		return frets
+	gid, ctx, meta, err := checkContext(model, false)
+	if err != nil {
+		return frets, err
 	}
 
 	time := XTime.GetMicrosecond()
@@ -46,17 +55,12 @@ func List[T IModel](model T, writableAndCond ...any) []T {
 		ctx.listCount++
 	}()
 
-	writable := meta.writable
-	var cond *Condition
-	for _, v := range writableAndCond {
-		switch nv := v.(type) {
-		case bool:
-			writable = nv
-		case *Condition:
-			cond = nv
-		default:
-			XLog.Critical("XOrm.List: writableAndCond of %v type is error: %v", v, XLog.Caller(1, false))
-		}
+	writable, cond, err := parseWritableAndCond("XOrm.List", meta.writable, strict, writableAndCond)
+	if err != nil {
+		return frets, err
+	}
+	if err := cond.checkOrders(meta); err != nil {
+		return frets, err
 	}
 	var slisted = isSessionListed(gid, model)
 	var glisted = isGlobalListed(model)
@@ -112,7 +116,10 @@ func List[T IModel](model T, writableAndCond ...any) []T {
 		}
 	} else { // 远端读取
 		globalWait("XOrm.List", model)
-		model.List(&frets, cond)
+		stamp := stampPairsCache(meta, model)
+		if _, err := modelListE(model, &frets, cond.unpaged()); err != nil { // 分页在合并内存数据后进行
+			return make([]T, 0), err
+		}
 		if len(frets) > 0 {
 			gcache := getGlobalCache(model)
 			scache := getSessionCache(gid, model)
@@ -122,6 +129,7 @@ func List[T IModel](model T, writableAndCond ...any) []T {
 				removed := false
 				obj := frets[i]
 				name := obj.DataUnique()
+				fillPairsCache(stamp, meta, obj) // 保存至二级缓存中
 				var gobj IModel
 				var sobj *sessionObject
 				if meta.cache {
@@ -242,6 +250,11 @@ func List[T IModel](model T, writableAndCond ...any) []T {
 		}
 	}
 
+	if orders := cond.orderBy(meta); len(orders) > 0 && len(frets) > 1 { // 按照排序规则排序，与远端查询的结果保持一致
+		slices.SortStableFunc(frets, func(a, b T) int { return compareOrders(meta, a, b, orders) })
+	}
+	frets = paginate(cond, frets) // 按照分页参数截取，与远端查询的结果保持一致
+
 	if cond == nil {
 		if !slisted {
 			isSessionListed(gid, model, true)
@@ -251,5 +264,5 @@ func List[T IModel](model T, writableAndCond ...any) []T {
 		}
 	}
 
-	return frets
+	return frets, nil
 }
@@ -126,3 +126,55 @@ func TestContextList(t *testing.T) {
 		})
 	}
 }
+
+// TestContextListPaging 测试分页列举时内存与远端读取的结果一致。
+func TestContextListPaging(t *testing.T) {
+	defer ResetContext()
+	defer ResetBaseTest()
+
+	ResetContext()
+	ResetBaseTest()
+	SetupBaseTest(true, true)
+	WriteBaseTest(20)
+
+	gid := goid.Get()
+	contextMap.Store(gid, &context{})
+	defer contextMap.Delete(gid)
+
+	model := NewTestBaseModel()
+	ids := func(models []*TestBaseModel) []int {
+		rets := make([]int, 0, len(models))
+		for _, model := range models {
+			rets = append(rets, model.ID)
+		}
+		return rets
+	}
+	tests := []struct {
+		cond   *Condition
+		expect []int
+	}{
+		{Cond("int_val > {0} && limit = {1} && offset = {2}", 5, 4, 3), []int{9, 10, 11, 12}},
+		{Cond("bool_val == {0} && orderby = -int_val && limit = {1} && offset = {2}", true, 3, 2), []int{16, 14, 12}},
+		{Cond("offset = {0}", 17), []int{18, 19, 20}},
+		{Cond("limit = {0}", 2), []int{1, 2}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expect, ids(List(model, test.cond)), "远端分页列举的结果应当和预期相等。")
+	}
+
+	// 全量列举后从会话内存读取
+	List(model)
+	assert.True(t, isSessionListed(gid, model), "全量列举后会话的列举状态标识应当为 true。")
+	for _, test := range tests {
+		assert.Equal(t, test.expect, ids(List(model, test.cond)), "会话内存分页列举的结果应当与远端读取相等。")
+	}
+
+	// 新的会话从全局内存读取
+	sessionListMap.Delete(gid)
+	sessionCacheMap.Delete(gid)
+	assert.True(t, isGlobalListed(model), "全量列举后全局的列举状态标识应当为 true。")
+	for _, test := range tests {
+		assert.Equal(t, test.expect, ids(List(model, test.cond)), "全局内存分页列举的结果应当与远端读取相等。")
+	}
+}
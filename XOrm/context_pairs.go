@@ -0,0 +1,236 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.CRUD/XPairs"
+	"github.com/eframework-org/GO.UTIL/XLog"
+	"github.com/eframework-org/GO.UTIL/XPrefs"
+)
+
+const (
+	// pairsCacheTTLPrefs 定义了二级缓存有效期（秒）的偏好设置键。
+	pairsCacheTTLPrefs = "Orm/Pairs/TTL"
+
+	// pairsCachePrefix 定义了二级缓存的键名前缀。
+	// 模型的缓存键名为 XOrm/{<ModelUnique>}/<代数>/<DataUnique>，代数存储于 XOrm/{<ModelUnique>}/Gen 键中，
+	// 花括号为 Redis 集群的哈希标签，使模型的所有键位于同一槽位，且模型标识不会匹配其他以该标识开头的模型。
+	pairsCachePrefix = "XOrm/"
+
+	// pairsCacheGen 定义了模型缓存代数的键名后缀。
+	pairsCacheGen = "Gen"
+)
+
+var (
+	// pairsCacheTTL 定义了二级缓存的有效期，默认为 1 小时，小于等于 0 时不使用二级缓存。
+	pairsCacheTTL time.Duration = time.Hour
+
+	// pairsCacheTTLAble 存储 XPairs 存储是否支持过期时间，键为 XPairs 别名。
+	pairsCacheTTLAble sync.Map
+)
+
+func init() { setupPairs(XPrefs.Asset()) }
+
+// setupPairs 初始化二级缓存。
+// 该函数会从 prefs 中获取二级缓存的有效期。
+func setupPairs(prefs XPrefs.IBase) {
+	pairsCacheTTL = time.Duration(prefs.GetInt(pairsCacheTTLPrefs, int(time.Hour/time.Second))) * time.Second
+}
+
+// getPairsCache 获取模型的二级缓存存储。
+// meta 为模型的描述信息。
+// 返回 XPairs 存储，如果模型未配置二级缓存、存储未注册、有效期小于等于 0 或存储不支持过期时间（如 Consul）则返回 nil，
+// 以避免写入不过期的缓存数据。
+func getPairsCache(meta *modelMeta) XPairs.IPairs {
+	if meta == nil || meta.pairs == "" || pairsCacheTTL <= 0 {
+		return nil
+	}
+	pairs := XPairs.Of(meta.pairs)
+	if pairs == nil {
+		XLog.Error("XOrm.Pairs: pairs of %v was not registered.", meta.pairs)
+		return nil
+	}
+	if able, ok := pairsCacheTTLAble.Load(meta.pairs); ok {
+		if able.(bool) {
+			return pairs
+		}
+		return nil
+	}
+	_, err := pairs.Expire(pairsCachePrefix+pairsCacheGen, pairsCacheTTL) // 探测存储是否支持过期时间
+	if err != nil && !errors.Is(err, XPairs.ErrNotSupported) {
+		return pairs // 其他错误（如网络错误）不记录探测的结果
+	}
+	able := err == nil
+	pairsCacheTTLAble.Store(meta.pairs, able)
+	if !able {
+		XLog.Warn("XOrm.Pairs: pairs of %v does not support ttl, second-level cache was disabled.", meta.pairs)
+		return nil
+	}
+	return pairs
+}
+
+// pairsCacheScope 返回模型的二级缓存键名前缀，unique 为模型标识。
+func pairsCacheScope(unique string) string {
+	return pairsCachePrefix + "{" + unique + "}/"
+}
+
+// pairsStamp 记录了读取远端数据前的二级缓存存储及当前代数的键名前缀。
+// 远端读取的数据仅填充至读取前的代数中，清除操作更新代数后填充的数据不会被读取。
+type pairsStamp struct {
+	pairs  XPairs.IPairs
+	prefix string
+}
+
+// stampPairsCache 读取模型二级缓存的当前代数。
+// 返回读取戳，如果模型未配置二级缓存或读取失败则返回 nil。
+func stampPairsCache(meta *modelMeta, model IModel) *pairsStamp {
+	pairs := getPairsCache(meta)
+	if pairs == nil {
+		return nil
+	}
+	scope := pairsCacheScope(model.ModelUnique())
+	gen, err := pairs.Get(scope + pairsCacheGen)
+	if err != nil {
+		if !errors.Is(err, XPairs.ErrNotFound) {
+			XLog.Warn("XOrm.Pairs: read generation of %v failed: %v", model.ModelUnique(), err)
+			return nil
+		}
+		gen = "0"
+	}
+	return &pairsStamp{pairs: pairs, prefix: scope + gen + "/"}
+}
+
+// encodePairsData 将数据模型的数据库字段编码为二级缓存的数据。
+// 数据为以列名为键的 JSON 对象，各字段的值单独编码，故不受模型的 json 标签（如 `json:"-"` 或重命名）影响。
+func encodePairsData(meta *modelMeta, model IModel) (string, error) {
+	addr := reflect.ValueOf(model).Elem()
+	data := make(map[string]json.RawMessage, len(meta.fields.fieldsDB))
+	for _, field := range meta.fields.fieldsDB {
+		value, err := json.Marshal(addr.FieldByIndex(field.fieldIndex).Interface())
+		if err != nil {
+			return "", fmt.Errorf("encode field %v failed: %w", field.name, err)
+		}
+		data[field.column] = value
+	}
+	bytes, err := json.Marshal(data)
+	return string(bytes), err
+}
+
+// decodePairsData 将二级缓存的数据解码至数据模型的数据库字段中，数据中不存在的列保持原值。
+func decodePairsData(meta *modelMeta, model IModel, data string) error {
+	values := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return err
+	}
+	addr := reflect.ValueOf(model).Elem()
+	for _, field := range meta.fields.fieldsDB {
+		value, ok := values[field.column]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, addr.FieldByIndex(field.fieldIndex).Addr().Interface()); err != nil {
+			return fmt.Errorf("decode field %v failed: %w", field.name, err)
+		}
+	}
+	return nil
+}
+
+// readPairsCache 从二级缓存中读取数据模型。
+// meta 为模型的描述信息，model 为要读取的数据模型（需设置主键）。
+// 读取成功后会调用 OnDecode 进行解码处理，解码失败的数据将被移除。
+// 与远端读取一致，缓存的数据需匹配 OnQuery("Read", cond) 返回的条件（如租户或分区的过滤条件），不匹配的数据视为未命中并由远端读取，
+// 无法在内存中求值的条件同样视为不匹配。
+// 返回读取戳（用于未命中时填充远端读取的数据）及是否命中缓存，已删除的数据（墓碑）视为未命中。
+func readPairsCache(meta *modelMeta, model IModel) (*pairsStamp, bool) {
+	stamp := stampPairsCache(meta, model)
+	if stamp == nil || meta.fields.pk == nil {
+		return stamp, false
+	}
+	key := stamp.prefix + model.DataUnique()
+	data, err := stamp.pairs.Get(key)
+	if err != nil {
+		if !errors.Is(err, XPairs.ErrNotFound) {
+			XLog.Warn("XOrm.Pairs: read %v failed: %v", key, err)
+		}
+		return stamp, false
+	}
+	if data == "" {
+		return stamp, false
+	}
+	if err := decodePairsData(meta, model, data); err != nil {
+		XLog.Warn("XOrm.Pairs: decode %v failed: %v", key, err)
+		stamp.pairs.Delete(key)
+		return stamp, false
+	}
+	query := orm.NewCondition().And(meta.fields.pk.column, model.DataValue(meta.fields.pk.name))
+	if query = model.OnQuery("Read", query); query != nil && !model.Matchs(&Condition{Base: query}) {
+		return stamp, false
+	}
+	model.IsValid(true)
+	model.OnDecode()
+	return stamp, true
+}
+
+// fillPairsCache 将远端读取的数据模型填充至读取戳所在代数的二级缓存中，stamp 需在读取远端数据前获取。
+// 仅在缓存不存在时写入，避免覆盖提交队列已同步的较新数据或已删除数据的墓碑。
+func fillPairsCache(stamp *pairsStamp, meta *modelMeta, model IModel) {
+	if stamp == nil {
+		return
+	}
+	key := stamp.prefix + model.DataUnique()
+	value, err := encodePairsData(meta, model)
+	if err != nil {
+		XLog.Warn("XOrm.Pairs: encode %v failed: %v", key, err)
+		return
+	}
+	if _, err := stamp.pairs.SetNX(key, value, pairsCacheTTL); err != nil {
+		XLog.Warn("XOrm.Pairs: fill %v failed: %v", key, err)
+	}
+}
+
+// syncPairsCache 将提交的数据模型同步至二级缓存中。
+// deleted 表示数据是否被删除或写入失败，此时写入空值作为墓碑而非移除缓存，
+// 以避免其他实例在删除前读取的远端数据被重新填充，墓碑与数据具有相同的有效期。
+func syncPairsCache(meta *modelMeta, model IModel, deleted bool) {
+	stamp := stampPairsCache(meta, model)
+	if stamp == nil {
+		return
+	}
+	key := stamp.prefix + model.DataUnique()
+	value := ""
+	if !deleted {
+		var err error
+		if value, err = encodePairsData(meta, model); err != nil {
+			XLog.Warn("XOrm.Pairs: encode %v failed: %v", key, err) // 写入墓碑，避免读取旧的数据
+		}
+	}
+	if err := stamp.pairs.Set(key, value, pairsCacheTTL); err != nil {
+		stamp.pairs.Delete(key)
+		XLog.Warn("XOrm.Pairs: sync %v failed: %v", key, err)
+	}
+}
+
+// clearPairsCache 使模型在二级缓存中的所有数据失效。
+// 清除操作的条件无法在缓存中求值，故更新模型的缓存代数，由后续的远端读取重新填充，旧代数的数据在有效期后自然过期。
+// 代数的键不设置有效期，以确保旧代数的数据不会被再次读取。
+func clearPairsCache(meta *modelMeta, model IModel) {
+	pairs := getPairsCache(meta)
+	if pairs == nil {
+		return
+	}
+	key := pairsCacheScope(model.ModelUnique()) + pairsCacheGen
+	if err := pairs.Set(key, strconv.FormatInt(time.Now().UnixNano(), 36), 0); err != nil {
+		XLog.Warn("XOrm.Pairs: clear %v failed: %v", model.ModelUnique(), err)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.CRUD/XPairs"
+	"github.com/eframework-org/GO.UTIL/XObject"
+	"github.com/eframework-org/GO.UTIL/XPrefs"
+	"github.com/petermattis/goid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPairsNoTTL 是不支持过期时间的测试存储，用于模拟 Consul 的行为。
+type TestPairsNoTTL struct {
+	*XPairs.MemoryClient
+}
+
+func (p TestPairsNoTTL) Expire(key string, ttl time.Duration) (bool, error) {
+	if ttl > 0 {
+		return false, XPairs.ErrNotSupported
+	}
+	return p.MemoryClient.Expire(key, ttl)
+}
+
+// TestPairsModel 是二级缓存编码的测试模型，包含忽略及重命名 JSON 的字段，并重写了 OnQuery 以过滤租户。
+type TestPairsModel struct {
+	Model[TestPairsModel] `orm:"-" json:"-"`
+	ID                    int       `orm:"column(id);pk"`
+	Name                  string    `orm:"column(name)" json:"-"`
+	Tenant                int       `orm:"column(tenant)" json:"tenant_id"`
+	Birth                 time.Time `orm:"column(birth)"`
+}
+
+// testPairsTenant 是 TestPairsModel 查询时过滤的租户，为 0 时不过滤。
+var testPairsTenant int
+
+func (m *TestPairsModel) AliasName() string { return "pairs_alias" }
+
+func (m *TestPairsModel) TableName() string { return "pairs_table" }
+
+func (m *TestPairsModel) OnQuery(action string, cond *orm.Condition) *orm.Condition {
+	if testPairsTenant == 0 {
+		return cond
+	}
+	return cond.And("tenant", testPairsTenant)
+}
+
+// TestContextPairsSetup 测试二级缓存的初始化。
+func TestContextPairsSetup(t *testing.T) {
+	defer setupPairs(XPrefs.Asset())
+
+	setupPairs(XPrefs.New())
+	assert.Equal(t, time.Hour, pairsCacheTTL, "默认的二级缓存有效期应当为 1 小时。")
+	setupPairs(XPrefs.New().Set(pairsCacheTTLPrefs, 60))
+	assert.Equal(t, time.Minute, pairsCacheTTL, "二级缓存有效期应当和偏好设置一致。")
+
+	assert.Nil(t, getPairsCache(&modelMeta{}), "未配置二级缓存时应当返回 nil。")
+	assert.Nil(t, getPairsCache(&modelMeta{pairs: "missing"}), "存储未注册时应当返回 nil。")
+
+	XPairs.Register("orm_pairs_ttl", XPairs.NewMemory())
+	defer XPairs.Of("orm_pairs_ttl").Close()
+	XPairs.Register("orm_pairs_nottl", TestPairsNoTTL{XPairs.NewMemory()})
+	defer XPairs.Of("orm_pairs_nottl").Close()
+	assert.NotNil(t, getPairsCache(&modelMeta{pairs: "orm_pairs_ttl"}), "存储支持过期时间时应当返回存储。")
+	assert.Nil(t, getPairsCache(&modelMeta{pairs: "orm_pairs_nottl"}), "存储不支持过期时间时不应当使用二级缓存。")
+	assert.Nil(t, getPairsCache(&modelMeta{pairs: "orm_pairs_nottl"}), "探测的结果应当被记录。")
+
+	setupPairs(XPrefs.New().Set(pairsCacheTTLPrefs, 0))
+	assert.Nil(t, getPairsCache(&modelMeta{pairs: "orm_pairs_ttl"}), "有效期小于等于 0 时不应当使用二级缓存。")
+
+	assert.False(t, strings.HasPrefix(pairsCacheScope("main_user_item"), pairsCacheScope("main_user")), "模型的键名前缀不应当匹配其他以该模型标识开头的模型。")
+	assert.True(t, XPairs.RedisSameSlot(pairsCacheScope("main_user")+pairsCacheGen, pairsCacheScope("main_user")+"0/main_user_1"), "模型的所有键应当位于同一槽位。")
+}
+
+// TestContextPairsStamp 测试二级缓存的代数及墓碑，模拟多个实例并发读取及提交。
+func TestContextPairsStamp(t *testing.T) {
+	defer orm.ResetModelCache()
+	orm.ResetModelCache()
+	model := XObject.New[TestSQLModel]()
+	Meta(model, false, true, "orm_pairs_stamp")
+	meta := getModelMeta(model)
+
+	XPairs.Register("orm_pairs_stamp", XPairs.NewMemory())
+	defer XPairs.Of("orm_pairs_stamp").Close()
+	pairs := XPairs.Of("orm_pairs_stamp")
+
+	newModel := func(id int, name string) *TestSQLModel {
+		data := XObject.New[TestSQLModel]()
+		data.ID = id
+		data.Name = name
+		return data
+	}
+	read := func(id int) *TestSQLModel {
+		data := newModel(id, "")
+		if _, hit := readPairsCache(meta, data); !hit {
+			return nil
+		}
+		return data
+	}
+
+	// 读取远端数据后、填充前，其他实例提交了删除
+	stamp := stampPairsCache(meta, model)
+	syncPairsCache(meta, newModel(1, "deleted"), true)
+	fillPairsCache(stamp, meta, newModel(1, "stale"))
+	assert.Nil(t, read(1), "删除后不应当填充删除前读取的数据。")
+	key := pairsCacheScope(model.ModelUnique()) + "0/" + newModel(1, "").DataUnique()
+	ttl, _ := pairs.TTL(key)
+	assert.True(t, ttl > 0 && ttl <= pairsCacheTTL, "墓碑应当设置有效期。")
+
+	// 读取远端数据后、填充前，其他实例提交了更新
+	stamp = stampPairsCache(meta, model)
+	syncPairsCache(meta, newModel(2, "new"), false)
+	fillPairsCache(stamp, meta, newModel(2, "stale"))
+	assert.Equal(t, "new", read(2).Name, "填充不应当覆盖提交的较新数据。")
+
+	// 写入失败后写入墓碑
+	syncPairsCache(meta, newModel(2, "failed"), true)
+	assert.Nil(t, read(2), "写入失败后不应当读取缓存的数据。")
+
+	// 读取远端数据后、填充前，其他实例提交了清除
+	fillPairsCache(stampPairsCache(meta, model), meta, newModel(3, "cleared"))
+	assert.Equal(t, "cleared", read(3).Name, "未命中时应当填充远端读取的数据。")
+	stamp = stampPairsCache(meta, model)
+	clearPairsCache(meta, model)
+	assert.Nil(t, read(3), "清除后不应当读取旧代数的数据。")
+	fillPairsCache(stamp, meta, newModel(4, "stale"))
+	assert.Nil(t, read(4), "清除后不应当填充清除前读取的数据。")
+	fillPairsCache(stampPairsCache(meta, model), meta, newModel(4, "fresh"))
+	assert.Equal(t, "fresh", read(4).Name, "清除后应当填充至新的代数中。")
+}
+
+// TestContextPairsCodec 测试二级缓存数据的编码及 OnQuery 的过滤条件。
+func TestContextPairsCodec(t *testing.T) {
+	defer orm.ResetModelCache()
+	orm.ResetModelCache()
+	model := XObject.New[TestPairsModel]()
+	Meta(model, false, true, "orm_pairs_codec")
+	meta := getModelMeta(model)
+
+	XPairs.Register("orm_pairs_codec", XPairs.NewMemory())
+	defer XPairs.Of("orm_pairs_codec").Close()
+
+	birth := time.Date(2025, 1, 2, 0, 0, 0, 0, time.Local)
+	data := XObject.New[TestPairsModel]()
+	data.ID = 1
+	data.Name = "name"
+	data.Tenant = 2
+	data.Birth = birth
+	syncPairsCache(meta, data, false)
+
+	read := func() *TestPairsModel {
+		data := XObject.New[TestPairsModel]()
+		data.ID = 1
+		if _, hit := readPairsCache(meta, data); !hit {
+			return nil
+		}
+		return data
+	}
+
+	t.Run("Field", func(t *testing.T) {
+		data := read()
+		assert.NotNil(t, data, "同步的数据应当命中二级缓存。")
+		assert.Equal(t, "name", data.Name, "忽略 JSON 的数据库字段应当被缓存。")
+		assert.Equal(t, 2, data.Tenant, "重命名 JSON 的数据库字段应当被缓存。")
+		assert.True(t, birth.Equal(data.Birth), "时间字段应当被缓存。")
+	})
+
+	t.Run("OnQuery", func(t *testing.T) {
+		defer func() { testPairsTenant = 0 }()
+		testPairsTenant = 3
+		assert.Nil(t, read(), "不匹配 OnQuery 过滤条件的缓存数据应当视为未命中。")
+		testPairsTenant = 2
+		assert.NotNil(t, read(), "匹配 OnQuery 过滤条件的缓存数据应当命中。")
+	})
+}
+
+// TestContextPairs 测试二级缓存的读取及同步。
+func TestContextPairs(t *testing.T) {
+	defer ResetContext()
+	defer ResetBaseTest()
+
+	ResetContext()
+	ResetBaseTest()
+	SetupBaseTest(false, true) // 不使用全局内存，以验证二级缓存
+
+	XPairs.Register("orm_pairs_test", XPairs.NewMemory())
+	defer XPairs.Of("orm_pairs_test").Close()
+	model := NewTestBaseModel()
+	getModelMeta(model).pairs = "orm_pairs_test"
+
+	load := func(id int) *TestBaseModel {
+		data := NewTestBaseModel()
+		data.ID = id
+		if _, hit := readPairsCache(getModelMeta(model), data); !hit {
+			return nil
+		}
+		return data
+	}
+
+	t.Run("Read", func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			data := NewTestBaseModel()
+			data.ID = i
+			data.IntVal = i
+			data.Write()
+		}
+
+		Watch()
+		data := NewTestBaseModel()
+		data.ID = 1
+		data = Read(data)
+		List(model, Cond("id >= {0}", 2))
+		Defer()
+		assert.Equal(t, 1, load(1).IntVal, "远端读取的数据应当被填充至二级缓存。")
+		assert.NotNil(t, load(2), "远端列举的数据应当被填充至二级缓存。")
+		assert.NotNil(t, load(3), "远端列举的数据应当被填充至二级缓存。")
+
+		// 直接修改远端数据，二级缓存命中时不应当读取远端数据
+		data.IntVal = 10
+		data.Write()
+		Watch()
+		data = NewTestBaseModel()
+		data.ID = 1
+		data = Read(data)
+		Defer()
+		assert.Equal(t, 1, data.IntVal, "命中二级缓存时应当读取缓存的数据。")
+		assert.True(t, data.IsValid(), "从二级缓存读取的数据应当有效。")
+		assert.True(t, data.onDecodeCalled, "从二级缓存读取的数据应当调用 OnDecode。")
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		gid := goid.Get()
+		Watch(true)
+		data := NewTestBaseModel()
+		data.ID = 2
+		data = Read(data)
+		data.IntVal = 20
+		created := NewTestBaseModel()
+		created.ID = 4
+		created.IntVal = 4
+		Write(created)
+		deleted := NewTestBaseModel()
+		deleted.ID = 3
+		Delete(deleted)
+		Defer()
+		Flush(gid)
+
+		assert.Equal(t, 20, load(2).IntVal, "更新的数据应当被同步至二级缓存。")
+		assert.Equal(t, 4, load(4).IntVal, "新建的数据应当被同步至二级缓存。")
+		assert.Nil(t, load(3), "删除的数据应当从二级缓存中移除。")
+
+		Watch(true)
+		Clear(model, Cond("id >= {0}", 1))
+		Defer()
+		Flush(gid)
+		assert.Nil(t, load(2), "清除操作后模型的二级缓存应当失效。")
+		assert.Nil(t, load(4), "清除操作后模型的二级缓存应当失效。")
+	})
+}
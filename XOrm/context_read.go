@@ -5,34 +5,47 @@
 package XOrm
 
 import (
+	"errors"
+	"fmt"
+	"sync"
+
 	"github.com/eframework-org/GO.UTIL/XLog"
 	"github.com/eframework-org/GO.UTIL/XTime"
-	"github.com/petermattis/goid"
 )
 
 // Read 从数据源读取数据模型。model 参数为要读取的数据模型，必须实现 IModel 接口。
 // writableAndCond 为可变参数，可包含布尔值（表示是否可写）和查询条件对象（*Condition 类型）。
 //
 // 函数根据是否有查询条件采用不同的读取策略。对于精确查找（无查询条件），首先尝试从会话内存中读取，
-// 如果启用缓存则尝试从全局内存中读取，最后从远端数据读取。对于模糊查找（有查询条件），
+// 如果启用缓存则尝试从全局内存中读取，如果配置了二级缓存则尝试从二级缓存中读取，最后从远端数据读取。对于模糊查找（有查询条件），
 // 仅缓存模式下先查询会话内存再查询全局内存；其他模式则按照会话列表、全局列表、远端数据的顺序查找。
 //
+// 模糊查找时若条件指定了排序规则（orderby），将读取排序后的首条记录。
+//
 // 函数返回读取到的数据模型，如果数据被标记为删除，模型的 IsValid 将被设置为 false。
 //
 // 该函数是线程安全的，可以确保单实例内的数据一致性。
 func Read[T IModel](model T, writableAndCond ...any) T {
+	model, err := read(model, false, writableAndCond...)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		logContextError("XOrm.Read", err)
+	}
+	return model
+}
+
+// ReadE 从数据源读取数据模型，读取规则与 Read 相同。
+// 未找到数据或数据被标记为删除时返回的错误匹配 ErrNotFound，此外还可能返回 ErrNoContext、ErrNotRegistered、参数类型及远端读取的错误。
+func ReadE[T IModel](model T, writableAndCond ...any) (T, error) {
+	return read(model, true, writableAndCond...)
+}
+
+// read 从数据源读取数据模型，strict 为 true 时参数类型错误将返回错误，否则记录严重错误并忽略该参数。
+func read[T IModel](model T, strict bool, writableAndCond ...any) (T, error) {
 	cacheDumpWait.Wait()
 
-	gid := goid.Get()
-	ctx := getContext(gid)
-	if ctx == nil {
-		XLog.Critical("XOrm.Read: context was not found: %v", XLog.Caller(1, false))
-		return model
-	}
-	meta := getModelMeta(model)
-	if meta == nil {
-		XLog.Critical("XOrm.Read: model of %v was not registered: %v", model.ModelUnique(), XLog.Caller(1, false))
-		return model
+	gid, ctx, meta, err := checkContext(model, false)
+	if err != nil {
+		return model, err
 	}
 
 	time := XTime.GetMicrosecond()
@@ -41,20 +54,15 @@ func Read[T IModel](model T, writableAndCond ...any) T {
 		ctx.readCount++
 	}()
 
-	writable := meta.writable
-	var cond *Condition
-	for _, v := range writableAndCond {
-		switch nv := v.(type) {
-		case bool:
-			writable = nv
-		case *Condition:
-			cond = nv
-		default:
-			XLog.Critical("XOrm.Read: writableAndCond of %v type is error: %v", v, XLog.Caller(1, false))
-		}
+	writable, cond, err := parseWritableAndCond("XOrm.Read", meta.writable, strict, writableAndCond)
+	if err != nil {
+		return model, err
+	}
+	if err := cond.checkOrders(meta); err != nil {
+		return model, err
 	}
+	isGet := false
 	if cond == nil { // 精确查找
-		isGet := false
 		scache := getSessionCache(gid, model)
 		if scache != nil { // 会话内存读取
 			obj, _ := scache.Load(model.DataUnique())
@@ -88,51 +96,95 @@ func Read[T IModel](model T, writableAndCond ...any) T {
 				}
 			}
 		}
-		if !isGet { // 远端读取
+		if !isGet { // 二级缓存或远端读取
 			globalWait("XOrm.Read", model)
-			if model.Read(cond) {
+			if stamp, hit := readPairsCache(meta, model); hit { // 二级缓存读取
 				isGet = true
-				if meta.cache {
-					setGlobalCache(model.Clone()) // 保存至全局内存中
-				}
-				setSessionCache(gid, model) // 监控内存
+			} else if err := modelReadE(model); err != nil {
+				return model, err
+			} else {
+				isGet = true
+				fillPairsCache(stamp, meta, model) // 保存至二级缓存中
 			}
+			if meta.cache {
+				setGlobalCache(model.Clone()) // 保存至全局内存中
+			}
+			setSessionCache(gid, model) // 监控内存
 		}
 	} else { // 模糊查找
+		orders := cond.orderBy(meta)
 		if isSessionListed(gid, model) { // 会话内存被列举过
 			scache := getSessionCache(gid, model)
 			if scache != nil { // 会话内存读取
+				var first *sessionObject
+				var mutex sync.Mutex
 				scache.RangeConcurrent(func(index int, key, value any) bool {
 					sobj := value.(*sessionObject)
 					if !sobj.ptr.IsValid() { // 忽略无效数据
 						// 已经被标记删除，则不读取
 					} else if sobj.ptr.Matchs(cond) {
+						if len(cond.Orders) > 0 { // 指定了排序规则，则读取排序后的首条记录
+							mutex.Lock()
+							if first == nil || compareOrders(meta, sobj.ptr, first.ptr, orders) < 0 {
+								first = sobj
+							}
+							mutex.Unlock()
+							return true
+						}
 						model = sobj.ptr.(any).(T)
 						sobj.isWritable(writable)
+						isGet = true
 						return false
 					}
 					return true
 				})
+				if first != nil {
+					model = first.ptr.(any).(T)
+					first.isWritable(writable)
+					isGet = true
+				}
 			}
 		} else if isGlobalListed(model) { // 全局内存被列举过
 			gcache := getGlobalCache(model)
 			if gcache != nil { // 全局内存读取
+				var first IModel
+				var mutex sync.Mutex
 				gcache.RangeConcurrent(func(index int, key, value any) bool {
 					gobj := value.(IModel)
 					if !gobj.IsValid() {
 						// 已经被标记删除，则不读取
 					} else if gobj.Matchs(cond) {
+						if len(cond.Orders) > 0 { // 指定了排序规则，则读取排序后的首条记录
+							mutex.Lock()
+							if first == nil || compareOrders(meta, gobj, first, orders) < 0 {
+								first = gobj
+							}
+							mutex.Unlock()
+							return true
+						}
 						model = gobj.Clone().(any).(T)      // 内存拷贝
 						sobj := setSessionCache(gid, model) // 监控内存
 						sobj.isWritable(writable)
+						isGet = true
 						return false
 					}
 					return true
 				})
+				if first != nil {
+					model = first.Clone().(any).(T)     // 内存拷贝
+					sobj := setSessionCache(gid, model) // 监控内存
+					sobj.isWritable(writable)
+					isGet = true
+				}
 			}
 		} else { // 远端筛选
 			globalWait("XOrm.Read", model)
-			if model.Read(cond) {
+			stamp := stampPairsCache(meta, model)
+			if err := modelReadE(model, cond); err != nil {
+				return model, err
+			} else {
+				isGet = true
+				fillPairsCache(stamp, meta, model) // 保存至二级缓存中
 				// 判断内存中是否有
 				isSCache := false
 				scache := getSessionCache(gid, model)
@@ -144,7 +196,7 @@ func Read[T IModel](model T, writableAndCond ...any) T {
 							// 已经被标记删除，则不读取
 							model.IsValid(false) // 设置为不合法，直接返回
 							XLog.Notice("XOrm.Read: session object is marked as invalid or deleted: %v", model.DataUnique())
-							return model
+							return model, fmt.Errorf("%w: %v is marked as deleted", ErrNotFound, model.DataUnique())
 						} else {
 							if model.Matchs(cond) { // 执行一遍条件
 								sobj := obj.(*sessionObject)
@@ -170,7 +222,7 @@ func Read[T IModel](model T, writableAndCond ...any) T {
 								// 已经被标记删除，则不读取
 								model.IsValid(false) // 设置为不合法，直接返回
 								XLog.Notice("XOrm.Read: global object is marked as invalid: %v", model.DataUnique())
-								return model
+								return model, fmt.Errorf("%w: %v is marked as deleted", ErrNotFound, model.DataUnique())
 							} else if !isSCache { // 未在会话内存中，但在全局内存中，替换之
 								model = gobj.Clone().(any).(T)      // 内存拷贝
 								sobj := setSessionCache(gid, model) // 监控内存
@@ -186,5 +238,8 @@ func Read[T IModel](model T, writableAndCond ...any) T {
 			}
 		}
 	}
-	return model
+	if !isGet || !model.IsValid() {
+		return model, fmt.Errorf("%w: %v", ErrNotFound, model.ModelUnique())
+	}
+	return model, nil
 }
@@ -5,9 +5,7 @@
 package XOrm
 
 import (
-	"github.com/eframework-org/GO.UTIL/XLog"
 	"github.com/eframework-org/GO.UTIL/XTime"
-	"github.com/petermattis/goid"
 )
 
 // Write 将数据模型写入到内存缓存中。model 参数为要写入的数据模型，必须实现 IModel 接口。
@@ -19,26 +17,19 @@ import (
 // 需要注意的是，写入操作不会立即持久化到远端数据。
 // 该函数是线程不安全的，操作相同的数据模型时，需要控制并发或使用适当的同步方式（如：Mutex）以确保操作的正确性。
 func Write[T IModel](model T) {
+	if err := WriteE(model); err != nil {
+		logContextError("XOrm.Write", err)
+	}
+}
+
+// WriteE 将数据模型写入到内存缓存中，规则与 Write 相同。
+// 返回发生的错误，如 ErrNoContext、ErrNotRegistered 及 ErrNotWritable。
+func WriteE[T IModel](model T) error {
 	cacheDumpWait.Wait()
 
-	gid := goid.Get()
-	ctx := getContext(gid)
-	if ctx == nil {
-		XLog.Critical("XOrm.Write: context was not found: %v", XLog.Caller(1, false))
-		return
-	}
-	meta := getModelMeta(model)
-	if meta == nil {
-		XLog.Critical("XOrm.Write: model of %v was not registered: %v", model.ModelUnique(), XLog.Caller(1, false))
-		return
-	}
-	if !ctx.writable {
-		XLog.Error("XOrm.Write: context was not writable.")
-		return
-	}
-	if !meta.writable {
-		XLog.Error("XOrm.Write: model of %v was not writable.", model.ModelUnique())
-		return
+	gid, ctx, meta, err := checkContext(model, true)
+	if err != nil {
+		return err
 	}
 
 	time := XTime.GetMicrosecond()
@@ -57,4 +48,5 @@ func Write[T IModel](model T) {
 	sobj.create = true
 	sobj.delete = false
 	sobj.clear = nil
+	return nil
 }
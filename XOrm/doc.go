@@ -10,6 +10,8 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
   - 多源配置：通过解析首选项中的配置自动初始化数据库连接
   - 数据模型：提供了面向对象的模型设计及常用的数据操作
   - 事务操作：基于上下文的事务机制，支持缓存和并发控制
+  - 二级缓存：可选地使用 XPairs 存储（如 Redis）作为多实例共享的缓存，避免重启或扩容时的冷启动
+  - 分布式自增：可按模型及列选择号段、XPairs 计数器或雪花算法的分配器，确保多实例写入时自增值的唯一性
 
 使用手册
 
@@ -101,6 +103,15 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 	Max(column ...string) int                      // 获取最大值
 	Min(column ...string) int                      // 获取最小值
 
+聚合查询：XOrm.Sum、XOrm.Avg、XOrm.Max、XOrm.Min、XOrm.Distinct 及 XOrm.GroupBy 支持查询条件并返回类型化的结果及错误信息，查询通过 Beego ORM 的 QuerySeter 执行，条件、分组及排序由 Beego ORM 编译，时间参数及结果按照数据库别名的时区转换，与 List 及 Count 的条件语义保持一致；Model.Max 及 Model.Min 出错时仍返回 -1。
+
+投影查询：XOrm.ListColumns 及 XOrm.ListColumnsAs 仅读取指定的列，返回以列名为键的数据或自定义的结构体，模型已被全量列举时直接从内存读取，否则通过 Beego ORM 的 ValuesList 从远端读取，远端读取前会调用模型的 OnQuery("ListColumns", cond) 以追加全局条件（如数据分区）。
+
+分批遍历：XOrm.Each 及 XOrm.Iter 按主键分批从远端读取数据，不写入会话内存（可选写入全局内存），每批查询前会调用模型的 OnQuery("Each", cond)，适用于数据规模较大的离线任务。
+
+错误处理：CountE、ReadE、ListE、WriteE、DeleteE 及 ClearE 返回驱动的错误而非 -1 或 false，ReadE 未找到记录时返回的错误匹配 XOrm.ErrNotFound。
+这些方法定义在可选的 IModelE 接口中，Model 已实现该接口，自行实现 IModel 的类型无需实现，此时上下文的 ReadE、ListE 等函数将回退至 Read、List 等方法。
+
 工具方法：
 
 	IsValid(value ...bool) bool // 检查/设置有效性
@@ -114,6 +125,7 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 参数说明：
   - cache：是否缓存，启用后支持全局缓存
   - writable：是否可写，启用后支持修改数据
+  - pairs：可选的 XPairs 别名，指定后使用该存储作为二级缓存
 
 应用场景：
 
@@ -139,6 +151,10 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 	// cache=false, writable=true
 	XOrm.Meta(NewLog(), false, true)
 
+	// 角色模型：多实例共享二级缓存，避免重启后直接读取数据库
+	// cache=true, writable=true, pairs=Cache
+	XOrm.Meta(NewRole(), true, true, "Cache")
+
 2.4 条件查询
 
 支持多种查询方式和复杂的条件组合。
@@ -154,6 +170,10 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 	// 3. 从表达式创建（推荐）
 	cond := XOrm.Cond("age > {0} && name == {1}", 18, "test")
 
+	// 4. 使用命名参数，参数可以是 map[string]any 或结构体（按 cond 标签或字段名对应）
+	cond := XOrm.Cond("level >= {min} && guild == {guild}", map[string]any{"min": 10, "guild": "g1"})
+	cond := XOrm.Cond("level >= {min} && guild == {guild}", Filter{Min: 10, Guild: "g1"})
+
 比较运算符：
 
 	// 大于/大于等于
@@ -171,6 +191,11 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 	// 空值判断
 	cond := XOrm.Cond("age isnull {0}", true) // age__isnull
 
+	// 集合/区间，参数为切片
+	cond := XOrm.Cond("age in {0}", []int{18, 20})      // age__in
+	cond := XOrm.Cond("age notin {0}", []int{18, 20})   // NOT age__in
+	cond := XOrm.Cond("age between {0}", []int{18, 30}) // age__between
+
 字符串匹配：
 
 	// 包含
@@ -182,6 +207,15 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 	// 后缀匹配
 	cond := XOrm.Cond("name endswith {0}", "test") // name__endswith
 
+	// 忽略大小写
+	cond := XOrm.Cond("name iexact {0}", "Test")      // name__iexact
+	cond := XOrm.Cond("name icontains {0}", "Test")   // name__icontains
+	cond := XOrm.Cond("name istartswith {0}", "Test") // name__istartswith
+	cond := XOrm.Cond("name iendswith {0}", "Test")   // name__iendswith
+
+注意：内存匹配时字符串操作符按字面值比较，而 MySQL 等数据库使用 LIKE 实现 iexact、contains 等字符串操作符，参数中的 % 会被转义，但 _ 仍为匹配任意单个字符的通配符；
+如 name iexact {0} 的参数为 "a_b" 时，远端查询会匹配 "A-B"，内存匹配则不会，故参数中包含 _ 时两者的结果可能不同。
+
 逻辑组合：
 
 	// AND 组合
@@ -208,6 +242,31 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 	// 组合使用
 	cond := XOrm.Cond("age > {0} && limit = {1} && offset = {2}", 18, 10, 20)
 
+排序查询：
+
+	// 排序规则：字段名或列名，前缀 - 表示降序，多个字段以逗号分隔
+	cond := XOrm.Cond("age > {0} && orderby = -age,id", 18)
+
+	// 排序参数：支持逗号分隔的字符串或字符串切片
+	cond := XOrm.Cond("age > {0} && orderby = {1} && limit = {2}", 18, "-age,id", 10)
+
+指定排序规则或分页参数时，若排序规则未包含主键则追加主键作为末位规则，以确保分页结果的稳定性；从会话内存或全局内存中列举的结果亦按照相同的规则排序及分页，远端列举时在合并内存中的数据后分页，以确保与远端查询的结果一致。
+
+注意：远端列举时需读取 offset + limit 条记录后再分页，深分页的开销随偏移量线性增长，建议使用排序字段的范围条件（如 id > {0} && limit = {1}）代替较大的偏移量；远端记录在内存中被标记删除或修改后不再匹配时将被移除，此时该页的结果可能少于 limit 条。排序规则中的字段须为模型的数据库字段，否则 ListE、ReadE 及 ListColumns 等接口返回匹配 XOrm.ErrInvalidCond 的错误。
+
+条件校验：
+
+	// 校验条件中的字段、操作符及参数类型，返回所有的校验错误
+	err := XOrm.Cond("level >= {0} && nmae == {1}", 10, "test").Validate(NewUser())
+
+	// 创建并校验条件，语法或参数错误作为错误返回而不会 panic
+	cond, err := XOrm.CondFor[*User]("level >= {0} && name contains {1}", 10, "test")
+
+	// 仅创建条件，语法或参数错误返回匹配 XOrm.ErrInvalidCond 的错误
+	cond, err := XOrm.CondE("level >= {0}", 10)
+
+字段名错误的条件在远端查询时会返回错误，在内存匹配时会被视为不匹配，建议对外部输入构建的条件进行校验。时间字段的参数可以是 time.Time，或 Beego ORM 可解析的日期时间（2006-01-02 15:04:05）、日期（2006-01-02）及时间（15:04:05）字符串。
+
 使用示例：
 
 	// 1. 简单查询
@@ -237,9 +296,36 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 	cond := XOrm.Cond("status == {0} && age > {1}", "active", 18)
 	count := model.Count(cond)
 	fmt.Printf("Found %d users\n", count)
+	// 5. 聚合查询
+	model := NewUser()
+	cond := XOrm.Cond("status == {0}", "active")
+	total, err := XOrm.Sum[int64](model, "age", cond)        // 总和
+	avg, err := XOrm.Avg(model, "age", cond)                 // 平均值
+	last, err := XOrm.Max[time.Time](model, "login_time")    // 最大值，无记录时返回 orm.ErrNoRows
+	names, err := XOrm.Distinct[string](model, "name", cond) // 去重值
+	rows, err := XOrm.GroupBy(model, []string{"status"}, []XOrm.Aggregation{
+	    {Func: "COUNT"},                                    // 键名为 count
+	    {Func: "AVG", Column: "age"},                       // 键名为 avg_age
+	}, cond)
+
+	// 6. 投影查询
+	type UserBrief struct {
+	    ID   int    `orm:"column(id)"`
+	    Name string `orm:"column(name)"`
+	}
+	rows, err := XOrm.ListColumns(model, []string{"id", "name"}, cond) // []orm.Params，键为列名
+	briefs, err := XOrm.ListColumnsAs[UserBrief](model, cond)         // []UserBrief
+
+	// 7. 分批遍历
+	err := XOrm.Each(model, cond, func(user *User) bool {
+	    return true // 返回 false 时停止遍历
+	}, XOrm.EachOption{Batch: 500})
+	for user, err := range XOrm.Iter(model, cond) {
+	    // ...
+	}
 
 注意事项：
-1. 条件表达式中的参数使用 {n} 形式引用，n 从 0 开始
+1. 条件表达式中的参数使用 {n} 形式引用，n 从 0 开始，或使用 {name} 形式的命名参数（不能与位置参数混用）
 2. 参数数量必须与表达式中的占位符数量一致
 3. 复杂条件建议使用括号明确优先级
 4. 条件会被缓存以提高性能，相同的表达式只会解析一次
@@ -289,10 +375,28 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 	cond = XOrm.Cond("age > {0} && name contains {1}", 18, "test")
 	XOrm.List(&users, cond) // 依次检查会话缓存、全局缓存、远端数据。
 
+错误处理：以上函数均提供返回错误的版本（ReadE、ListE、WriteE、DeleteE、ClearE 及 IncreE），可以使用 errors.Is 区分错误类型：
+
+	user, err := XOrm.ReadE(NewUser(), XOrm.Cond("name == {0}", "test"))
+	if errors.Is(err, XOrm.ErrNotFound) {
+		// 数据不存在或已被标记删除
+	} else if err != nil {
+		// 数据库错误（如连接失败），或 ErrNoContext、ErrNotRegistered 等使用错误
+	}
+
+错误类型：
+  - ErrNoContext：未在 Watch() 和 Defer() 之间调用
+  - ErrNotRegistered：模型未通过 Meta 注册
+  - ErrNotWritable：上下文或模型为只读
+  - ErrNotFound：未找到数据或数据已被标记删除
+  - ErrInvalidCond：条件表达式的语法、参数或字段错误
+
+不带 E 后缀的函数保持原有的行为，发生错误时记录日志并返回默认值。
+
 注意：
 1. 所有操作必须在 Watch() 和 Defer() 之间进行
 2. 写入操作会同时更新会话缓存和全局缓存
-3. 读取操作遵循缓存优先级：会话缓存 > 全局缓存 > 远端数据
+3. 读取操作遵循缓存优先级：会话缓存 > 全局缓存 > 二级缓存 > 远端数据
 4. 删除和清除操作仅做标记，实际删除在会话提交时执行
 5. 列举操作可能会同时访问缓存和远端数据
 
@@ -317,14 +421,59 @@ XOrm 拓展了 Beego 的 ORM 功能，同时实现了基于上下文的事务机
 
   - Orm/Commit/Queue：提交队列的数量，默认为 CPU 核心数，-1 表示禁用提交队列
   - Orm/Commit/Queue/Capacity：单个队列的容量，默认为 100000
+  - Orm/Pairs/TTL：二级缓存的有效期（秒），默认为 3600，小于等于 0 时不使用二级缓存
 
 配置示例：
 
 	{
 	    "Orm/Commit/Queue": 8,
-	    "Orm/Commit/Queue/Capacity": 100000
+	    "Orm/Commit/Queue/Capacity": 100000,
+	    "Orm/Pairs/TTL": 3600
 	}
 
+二级缓存：
+1. 通过 XOrm.Meta 的 pairs 参数为模型指定 XPairs 别名，数据库字段以列名为键编码为 JSON 对象（不受模型的 json 标签影响）并存储于 XOrm/{<ModelUnique>}/<代数>/<DataUnique> 键中，花括号为 Redis 集群的哈希标签，使模型的所有键位于同一槽位；存储不支持过期时间（如 Consul）时不使用二级缓存
+2. 精确读取时在全局缓存未命中后读取二级缓存，命中后同步至全局缓存及会话缓存；缓存的数据需匹配 OnQuery("Read", cond) 返回的条件（如租户过滤），否则视为未命中并由远端读取；列举操作不读取二级缓存
+3. 远端读取及列举的数据仅在二级缓存不存在时填充至读取前的代数中，避免覆盖提交队列已同步的较新数据或已删除数据的墓碑
+4. 提交队列写入后更新二级缓存，删除或写入失败时写入墓碑（空值），以避免其他实例填充删除前读取的数据；清除时更新模型的代数（XOrm/{<ModelUnique>}/Gen），使该模型的所有键失效且在有效期后自然过期
+5. 多实例并发读写时二级缓存可能短暂地不一致，其有效期（Orm/Pairs/TTL）决定了不一致的最长时间
+
+3.4 分布式自增
+
+XOrm.Incre 默认在进程内自增（首次通过模型的 Max 获取最大值，故模型重写的 Max 同样生效），仅可以确保单实例内的数据唯一性。多个实例写入同一数据表时，可以通过 XOrm.SetAllocator 为模型的列选择分布式的分配器：
+
+	// 号段分配：每次从序列表 xorm_sequence 中预留 100 个值，用于主键，序列表需在部署或启动时创建
+	hilo := XOrm.NewHiLoAllocator("xorm_sequence", 100)
+	if err := hilo.Migrate("Main"); err != nil {
+		return err
+	}
+	XOrm.SetAllocator(NewUser(), hilo)
+
+	// XPairs 计数器：使用别名为 Main 的存储原子地自增，用于 seq 列
+	XOrm.SetAllocator(NewMail(), XOrm.NewPairsAllocator("Main"), "seq")
+
+	// 雪花算法：node 为实例的节点编号（0 ~ 1023），多个实例之间不能重复
+	allocator, err := XOrm.NewSnowflakeAllocator(node)
+	XOrm.SetAllocator(NewLog(), allocator)
+
+	// 使用方式不变
+	user.ID = XOrm.Incre(user)
+
+	| 分配器 | 存储 | 分配的值 | 访问远端 |
+	|--------|------|----------|----------|
+	| HiLoAllocator | 模型所在数据库的序列表 | 唯一，不连续（实例间交错，重启时跳过未使用的号段） | 号段耗尽时访问数据库 |
+	| PairsAllocator | XPairs 存储的 XOrm/Incre 哈希表（需实现 IHash，如 Redis） | 唯一，连续递增 | 每次访问存储 |
+	| SnowflakeAllocator | 无 | 唯一，实例内递增，不连续，增量范围为 1 ~ 4096 | 无 |
+
+注意：
+1. 序列及计数器不存在时以模型的 Max 返回的最大值为起始值，已存在的值不会被重复分配
+2. HiLoAllocator 分配时不会自动创建序列表，需在部署或启动时调用 Migrate 或执行等价的 DDL（MySQL）：CREATE TABLE IF NOT EXISTS <table> (name VARCHAR(255) NOT NULL PRIMARY KEY, value BIGINT NOT NULL)，序列表不存在时 IncreE 返回数据库的错误
+3. PairsAllocator 的存储未注册或未实现 XPairs.IHash（如 Consul）时，IncreE 返回 XPairs.ErrNotRegistered 或 XPairs.ErrNotSupported
+4. SnowflakeAllocator 的值由 41 位的毫秒时间、10 位的节点编号及 12 位的序号组成，时钟回拨时继续使用最近一次分配的时间以保证实例内不重复
+5. 选择分配器后 Incre 的增量必须不小于 1，否则返回错误，避免重复分配已分配的值
+6. 可以实现 XOrm.IAllocator 接口以提供自定义的分配器，SetAllocator 的 allocator 为 nil 时恢复使用默认分配器
+7. IncreE 返回 int64 类型的值，Incre 返回 int 类型的值，在 32 位平台上使用雪花算法等分配器时请使用 IncreE
+
 更多信息请参考模块文档。
 */
 package XOrm
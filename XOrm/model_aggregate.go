@@ -0,0 +1,503 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// AggregateType 定义了聚合查询支持的结果类型。
+type AggregateType interface {
+	int64 | float64 | string | time.Time
+}
+
+// Aggregation 定义了分组查询的聚合项。
+type Aggregation struct {
+	Func   string // 聚合函数，包括：COUNT、SUM、AVG、MAX、MIN
+	Column string // 聚合的列名，COUNT 的列名为空时统计所有记录
+	Alias  string // 结果的键名，为空时使用小写的 "函数_列名"，如 sum_score
+}
+
+// aggregateTimeLayouts 定义了解析时间结果的格式。
+var aggregateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05",
+}
+
+// Sum 统计符合条件的记录中指定列的总和。
+// column 为列名，若为空则使用主键列；cond 为可选的查询条件。
+// 返回列的总和，无匹配记录时返回 0，执行查询前会调用模型的 OnQuery("Sum", cond)。
+//
+// 使用示例：
+//
+//	total, err := XOrm.Sum[int64](model, "score", XOrm.Cond("level > {0}", 10))
+func Sum[R int64 | float64](model IModel, column string, cond ...*Condition) (R, error) {
+	var ret R
+	rows, err := aggregate(model, "Sum", cond, func(agg *aggregator) error {
+		field, err := agg.field(column)
+		if err != nil {
+			return err
+		}
+		agg.selects("SUM("+agg.column(field)+")", agg.carrier(field))
+		return nil
+	})
+	if err != nil {
+		return ret, err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 || rows[0][0] == nil {
+		return ret, nil
+	}
+	return aggregateValue[R](rows[0][0])
+}
+
+// Avg 统计符合条件的记录中指定列的平均值。
+// column 为列名，若为空则使用主键列；cond 为可选的查询条件。
+// 平均值由非空值的总和及数量计算得出，返回列的平均值，无匹配记录时返回 orm.ErrNoRows，
+// 执行查询前会调用模型的 OnQuery("Avg", cond)。
+func Avg(model IModel, column string, cond ...*Condition) (float64, error) {
+	rows, err := aggregate(model, "Avg", cond, func(agg *aggregator) error {
+		field, err := agg.field(column)
+		if err != nil {
+			return err
+		}
+		agg.average(field)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 || len(rows[0]) < 2 {
+		return 0, orm.ErrNoRows
+	}
+	avg, err := aggregateAverage(rows[0][0], rows[0][1])
+	if err != nil {
+		return 0, err
+	}
+	if avg == nil {
+		return 0, orm.ErrNoRows
+	}
+	return avg.(float64), nil
+}
+
+// Max 获取符合条件的记录中指定列的最大值。
+// column 为列名，若为空则使用主键列；cond 为可选的查询条件。
+// R 为结果的类型，整型列使用 int64，浮点列使用 float64，时间列使用 time.Time。
+// 返回列的最大值，无匹配记录时返回 orm.ErrNoRows，执行查询前会调用模型的 OnQuery("Max", cond)。
+//
+// 使用示例：
+//
+//	last, err := XOrm.Max[time.Time](model, "create_time")
+func Max[R AggregateType](model IModel, column string, cond ...*Condition) (R, error) {
+	return aggregateExtreme[R](model, "Max", column, cond)
+}
+
+// Min 获取符合条件的记录中指定列的最小值。
+// column 为列名，若为空则使用主键列；cond 为可选的查询条件。
+// R 为结果的类型，整型列使用 int64，浮点列使用 float64，时间列使用 time.Time。
+// 返回列的最小值，无匹配记录时返回 orm.ErrNoRows，执行查询前会调用模型的 OnQuery("Min", cond)。
+func Min[R AggregateType](model IModel, column string, cond ...*Condition) (R, error) {
+	return aggregateExtreme[R](model, "Min", column, cond)
+}
+
+// Distinct 获取符合条件的记录中指定列的去重值。
+// column 为列名，若为空则使用主键列；cond 为可选的查询条件。
+// 返回按升序排列的去重值，空值（NULL）会被忽略，执行查询前会调用模型的 OnQuery("Distinct", cond)。
+func Distinct[R AggregateType](model IModel, column string, cond ...*Condition) ([]R, error) {
+	rows, err := aggregate(model, "Distinct", cond, func(agg *aggregator) error {
+		field, err := agg.field(column)
+		if err != nil {
+			return err
+		}
+		agg.query = agg.query.Distinct().OrderBy(field.name)
+		agg.carriers = append(agg.carriers, field.name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	rets := make([]R, 0, len(rows))
+	for _, row := range rows {
+		if len(row) == 0 || row[0] == nil {
+			continue
+		}
+		ret, err := aggregateValue[R](row[0])
+		if err != nil {
+			return nil, err
+		}
+		rets = append(rets, ret)
+	}
+	return rets, nil
+}
+
+// GroupBy 按指定列分组统计符合条件的记录。
+// columns 为分组的列名，aggs 为聚合项，cond 为可选的查询条件。
+// 返回按分组列升序排列的统计结果，每行的键为分组列的列名及聚合项的键名，
+// 分组列及 MAX、MIN 的值与 beego/orm 的 Values 结果一致（按字段类型转换为 int64、uint64、float64、bool、time.Time 或 string），
+// COUNT 的值为 int64，AVG 的值为 float64，SUM 的值在整型列上为 int64、其他列上为 float64，空值为 nil。
+// 执行查询前会调用模型的 OnQuery("GroupBy", cond)。
+//
+// 使用示例：
+//
+//	rows, err := XOrm.GroupBy(model, []string{"level"}, []XOrm.Aggregation{
+//		{Func: "COUNT"},
+//		{Func: "AVG", Column: "score", Alias: "score"},
+//	})
+//	for _, row := range rows {
+//		fmt.Println(row["level"], row["count"], row["score"])
+//	}
+func GroupBy(model IModel, columns []string, aggs []Aggregation, cond ...*Condition) ([]orm.Params, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("group columns was empty")
+	}
+
+	type groupItem struct {
+		key   string          // 结果的键名
+		fn    string          // 聚合函数，分组列为空
+		field *beegoFieldInfo // 字段信息，COUNT(*) 为空
+	}
+	var items []groupItem
+	rows, err := aggregate(model, "GroupBy", cond, func(agg *aggregator) error {
+		var groups []string
+		for _, column := range columns {
+			field, err := agg.field(column)
+			if err != nil {
+				return err
+			}
+			items = append(items, groupItem{key: field.column, field: field})
+			agg.selects(agg.column(field), field)
+			groups = append(groups, field.name)
+		}
+		for _, ag := range aggs {
+			fn := strings.ToUpper(ag.Func)
+			item := groupItem{key: ag.Alias, fn: fn}
+			switch fn {
+			case "COUNT", "SUM", "AVG", "MAX", "MIN":
+			default:
+				return fmt.Errorf("unsupported aggregate function `%v`", ag.Func)
+			}
+			if fn == "COUNT" && ag.Column == "" {
+				agg.selects("COUNT(*)", agg.carrier(nil))
+				if item.key == "" {
+					item.key = "count"
+				}
+			} else {
+				if ag.Column == "" {
+					return fmt.Errorf("column of aggregate function `%v` was empty", ag.Func)
+				}
+				field, err := agg.field(ag.Column)
+				if err != nil {
+					return err
+				}
+				item.field = field
+				switch fn {
+				case "COUNT", "SUM":
+					agg.selects(fn+"("+agg.column(field)+")", agg.carrier(field))
+				case "AVG":
+					agg.average(field)
+				default:
+					agg.selects(fn+"("+agg.column(field)+")", field)
+				}
+				if item.key == "" {
+					item.key = strings.ToLower(fn) + "_" + field.column
+				}
+			}
+			items = append(items, item)
+		}
+		agg.query = agg.query.GroupBy(groups...).OrderBy(groups...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rets := make([]orm.Params, 0, len(rows))
+	for _, row := range rows {
+		ret := make(orm.Params, len(items))
+		index := 0
+		for _, item := range items {
+			var value any
+			if item.fn == "AVG" {
+				if index+1 < len(row) {
+					value, err = aggregateAverage(row[index], row[index+1])
+				}
+				index += 2
+			} else {
+				if index < len(row) && row[index] != nil {
+					switch {
+					case item.fn == "COUNT":
+						value, err = aggregateValue[int64](row[index])
+					case item.fn == "SUM" && item.field.fieldType&orm.IsIntegerField != 0:
+						value, err = aggregateValue[int64](row[index])
+					case item.fn == "SUM":
+						value, err = aggregateValue[float64](row[index])
+					default:
+						value = row[index]
+					}
+				}
+				index++
+			}
+			if err != nil {
+				return nil, err
+			}
+			ret[item.key] = value
+		}
+		rets = append(rets, ret)
+	}
+	return rets, nil
+}
+
+// aggregator 定义了聚合查询的构建器。
+// 查询通过 beego/orm 的 QuerySeter 执行，条件、分组及排序由 beego/orm 编译，
+// 仅聚合的列表达式（如 SUM(T0.`score`)）通过 Aggregate 指定；
+// beego/orm 按照 ValuesList 的字段类型转换每一列的结果，故每个列表达式需指定承载结果的字段。
+type aggregator struct {
+	meta     *modelMeta     // 模型信息
+	quote    string         // 标识符引号，与 beego/orm 的 TableQuote 一致
+	query    orm.QuerySeter // 查询器
+	exprs    []string       // 列表达式，为空时查询承载的字段本身
+	carriers []string       // 承载结果的字段名
+}
+
+// field 获取指定名称的字段信息，名称可以是字段名、小写字段名或列名，为空时使用主键。
+func (agg *aggregator) field(name string) (*beegoFieldInfo, error) {
+	if name == "" {
+		if agg.meta.fields.pk == nil {
+			return nil, fmt.Errorf("column was empty and primary key of %v was not found", agg.meta.table)
+		}
+		return agg.meta.fields.pk, nil
+	}
+	if field := agg.meta.field(name); field != nil {
+		return field, nil
+	}
+	return nil, fmt.Errorf("unknown field/column name `%v` of %v", name, agg.meta.table)
+}
+
+// column 返回字段在查询中的列名，beego/orm 查询的主表别名为 T0。
+func (agg *aggregator) column(field *beegoFieldInfo) string {
+	return "T0." + agg.quote + field.column + agg.quote
+}
+
+// selects 添加查询的列表达式及承载结果的字段。
+func (agg *aggregator) selects(expr string, carrier *beegoFieldInfo) {
+	agg.exprs = append(agg.exprs, expr)
+	agg.carriers = append(agg.carriers, carrier.name)
+}
+
+// average 添加计算平均值所需的总和及数量，整型列的 AVG 结果为小数，无法由整型字段承载，故由 aggregateAverage 计算。
+func (agg *aggregator) average(field *beegoFieldInfo) {
+	carrier := agg.carrier(field)
+	agg.selects("SUM("+agg.column(field)+")", carrier)
+	agg.selects("COUNT("+agg.column(field)+")", carrier)
+}
+
+// carrier 返回承载 COUNT、SUM 结果的字段，field 为聚合的字段，COUNT(*) 时为空。
+// 字符及文本字段保持原始的字符串结果，故优先使用；整型列的结果其次使用 64 位整型字段，
+// 再次使用浮点字段，均不存在时使用聚合的字段本身（COUNT(*) 时为主键）。
+func (agg *aggregator) carrier(field *beegoFieldInfo) *beegoFieldInfo {
+	var float, bigint *beegoFieldInfo
+	for _, fi := range agg.meta.fields.fieldsDB {
+		switch fi.fieldType {
+		case orm.TypeVarCharField, orm.TypeCharField, orm.TypeTextField:
+			return fi
+		case orm.TypeFloatField, orm.TypeDecimalField:
+			if float == nil {
+				float = fi
+			}
+		case orm.TypeBigIntegerField:
+			if bigint == nil {
+				bigint = fi
+			}
+		}
+	}
+	if bigint != nil && (field == nil || field.fieldType&orm.IsIntegerField != 0) {
+		return bigint
+	}
+	if float != nil {
+		return float
+	}
+	if field != nil {
+		return field
+	}
+	return agg.meta.fields.pk
+}
+
+// aggregate 执行模型的聚合查询。
+// action 为查询的类型，将传递给模型的 OnQuery；build 添加查询的列表达式，或设置查询器的分组、排序等参数。
+// 返回查询结果的所有行，值按承载字段的类型由 beego/orm 转换。
+func aggregate(model IModel, action string, cond []*Condition, build func(agg *aggregator) error) (rows []orm.ParamsList, err error) {
+	defer func() { // beego/orm 在数据库未注册、字段解析或值转换失败时会 panic
+		if r := recover(); r != nil {
+			rows = nil
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	if model == nil {
+		return nil, fmt.Errorf("nil model instance")
+	}
+	meta := getModelMeta(model)
+	if meta == nil {
+		return nil, fmt.Errorf("model of %v was not registered", model.TableName())
+	}
+	ormer := orm.NewOrmUsingDB(model.AliasName())
+	if ormer == nil {
+		return nil, fmt.Errorf("failed to create orm instance of %v", model.AliasName())
+	}
+
+	agg := &aggregator{meta: meta, quote: tableQuote(ormer.Driver().Type()), query: ormer.QueryTable(model)}
+	var ncond *orm.Condition
+	if len(cond) > 0 && cond[0] != nil {
+		ncond = model.OnQuery(action, cond[0].Base)
+	} else {
+		ncond = model.OnQuery(action, nil)
+	}
+	if ncond != nil {
+		agg.query = agg.query.SetCond(ncond)
+	}
+	if err := build(agg); err != nil {
+		return nil, err
+	}
+	if len(agg.exprs) > 0 {
+		agg.query = agg.query.Aggregate(strings.Join(agg.exprs, ", "))
+	}
+	if _, err := agg.query.ValuesList(&rows, agg.carriers...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// tableQuote 返回数据库的标识符引号，与 beego/orm 各数据库的 TableQuote 保持一致。
+func tableQuote(driver orm.DriverType) string {
+	if driver == orm.DRPostgres {
+		return `"`
+	}
+	return "`"
+}
+
+// aggregateExtreme 获取指定列的最大值或最小值，action 为 Max 或 Min。
+func aggregateExtreme[R AggregateType](model IModel, action, column string, cond []*Condition) (R, error) {
+	var ret R
+	rows, err := aggregate(model, action, cond, func(agg *aggregator) error {
+		field, err := agg.field(column)
+		if err != nil {
+			return err
+		}
+		agg.selects(strings.ToUpper(action)+"("+agg.column(field)+")", field)
+		return nil
+	})
+	if err != nil {
+		return ret, err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 || rows[0][0] == nil {
+		return ret, orm.ErrNoRows
+	}
+	return aggregateValue[R](rows[0][0])
+}
+
+// aggregateAverage 由总和及数量计算平均值，数量为 0 或总和为空时返回 nil。
+func aggregateAverage(sum, count any) (any, error) {
+	if sum == nil || count == nil {
+		return nil, nil
+	}
+	num, err := aggregateValue[float64](count)
+	if err != nil {
+		return nil, err
+	}
+	if num == 0 {
+		return nil, nil
+	}
+	total, err := aggregateValue[float64](sum)
+	if err != nil {
+		return nil, err
+	}
+	return total / num, nil
+}
+
+// aggregateValue 将 beego/orm 转换后的查询结果转换为指定的类型。
+// 整型结果允许为整数值的浮点数（如 DECIMAL 类型的 SUM 结果），字符串结果按照目标类型解析。
+func aggregateValue[R AggregateType](value any) (R, error) {
+	var ret R
+	switch pret := any(&ret).(type) {
+	case *int64:
+		switch v := value.(type) {
+		case int64:
+			*pret = v
+		case uint64:
+			if v > math.MaxInt64 {
+				return ret, fmt.Errorf("aggregate result `%v` overflows int64", v)
+			}
+			*pret = int64(v)
+		case bool:
+			if v {
+				*pret = 1
+			}
+		case float64:
+			if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+				return ret, fmt.Errorf("parse aggregate result `%v` as int64 failed", v)
+			}
+			*pret = int64(v)
+		case string:
+			if val, err := strconv.ParseInt(v, 10, 64); err == nil {
+				*pret = val
+			} else if fval, ferr := strconv.ParseFloat(v, 64); ferr == nil && fval == math.Trunc(fval) &&
+				fval >= math.MinInt64 && fval < math.MaxInt64 {
+				*pret = int64(fval)
+			} else {
+				return ret, fmt.Errorf("parse aggregate result `%v` as int64 failed", v)
+			}
+		default:
+			return ret, fmt.Errorf("unexpected aggregate result `%v`", value)
+		}
+	case *float64:
+		switch v := value.(type) {
+		case float64:
+			*pret = v
+		case int64:
+			*pret = float64(v)
+		case uint64:
+			*pret = float64(v)
+		case string:
+			val, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return ret, fmt.Errorf("parse aggregate result `%v` as float64 failed", v)
+			}
+			*pret = val
+		default:
+			return ret, fmt.Errorf("unexpected aggregate result `%v`", value)
+		}
+	case *string:
+		switch v := value.(type) {
+		case string:
+			*pret = v
+		case int64, uint64, float64, bool:
+			*pret = fmt.Sprint(v)
+		default:
+			return ret, fmt.Errorf("unexpected aggregate result `%v`", value)
+		}
+	case *time.Time:
+		switch v := value.(type) {
+		case time.Time:
+			*pret = v
+		case string:
+			for _, layout := range aggregateTimeLayouts {
+				if val, err := time.ParseInLocation(layout, v, orm.DefaultTimeLoc); err == nil {
+					*pret = val
+					return ret, nil
+				}
+			}
+			return ret, fmt.Errorf("parse aggregate result `%v` as time failed", v)
+		default:
+			return ret, fmt.Errorf("unexpected aggregate result `%v`", value)
+		}
+	}
+	return ret, nil
+}
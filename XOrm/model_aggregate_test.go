@@ -0,0 +1,206 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.UTIL/XObject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelAggregate(t *testing.T) {
+	t.Run("Value", func(t *testing.T) {
+		ival, err := aggregateValue[int64]("15")
+		assert.NoError(t, err, "整数结果应当转换成功。")
+		assert.Equal(t, int64(15), ival, "整数结果应当和预期相等。")
+
+		ival, err = aggregateValue[int64]("15.000")
+		assert.NoError(t, err, "整数值的小数结果应当转换成功。")
+		assert.Equal(t, int64(15), ival, "整数值的小数结果应当和预期相等。")
+
+		_, err = aggregateValue[int64]("15.5")
+		assert.Error(t, err, "非整数值的小数结果转换为整数时应当返回错误。")
+
+		fval, err := aggregateValue[float64]("2.5000")
+		assert.NoError(t, err, "小数结果应当转换成功。")
+		assert.Equal(t, 2.5, fval, "小数结果应当和预期相等。")
+
+		tval, err := aggregateValue[time.Time]("2025-01-02 03:04:05")
+		assert.NoError(t, err, "时间结果应当转换成功。")
+		assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, orm.DefaultTimeLoc), tval, "时间结果应当和预期相等。")
+
+		tval, err = aggregateValue[time.Time]("2025-01-02T03:04:05Z")
+		assert.NoError(t, err, "RFC3339 格式的时间结果应当转换成功。")
+		assert.True(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC).Equal(tval), "RFC3339 格式的时间结果应当和预期相等。")
+
+		_, err = aggregateValue[int64](nil)
+		assert.Error(t, err, "空结果应当返回错误。")
+
+		ival, err = aggregateValue[int64](uint64(7))
+		assert.NoError(t, err, "无符号整数结果应当转换成功。")
+		assert.Equal(t, int64(7), ival, "无符号整数结果应当和预期相等。")
+
+		ival, err = aggregateValue[int64](true)
+		assert.NoError(t, err, "布尔结果应当转换成功。")
+		assert.Equal(t, int64(1), ival, "布尔结果 true 应当转换为 1。")
+
+		fval, err = aggregateValue[float64](int64(3))
+		assert.NoError(t, err, "整数结果转换为浮点数应当成功。")
+		assert.Equal(t, 3.0, fval, "整数结果转换为浮点数应当和预期相等。")
+
+		now := time.Now()
+		tval, err = aggregateValue[time.Time](now)
+		assert.NoError(t, err, "beego/orm 转换后的时间结果应当转换成功。")
+		assert.Equal(t, now, tval, "beego/orm 转换后的时间结果应当保持不变。")
+
+		avg, err := aggregateAverage("9", int64(2))
+		assert.NoError(t, err, "计算平均值不应当返回错误。")
+		assert.Equal(t, 4.5, avg, "平均值应当为总和除以数量。")
+
+		avg, err = aggregateAverage(nil, int64(0))
+		assert.NoError(t, err, "无匹配记录时计算平均值不应当返回错误。")
+		assert.Nil(t, avg, "无匹配记录的平均值应当为 nil。")
+	})
+
+	t.Run("Builder", func(t *testing.T) {
+		defer orm.ResetModelCache()
+		orm.ResetModelCache()
+		Meta(XObject.New[TestSQLModel](), false, true)
+		meta := getModelMeta(XObject.New[TestSQLModel]())
+
+		agg := &aggregator{meta: meta, quote: tableQuote(orm.DRMySQL)}
+		field, err := agg.field("")
+		assert.NoError(t, err, "列名为空时应当使用主键。")
+		assert.Equal(t, "id", field.column, "列名为空时应当使用主键。")
+		_, err = agg.field("unknown")
+		assert.Error(t, err, "未知的列名应当返回错误。")
+
+		score, _ := agg.field("Score")
+		assert.Equal(t, "T0.`score`", agg.column(score), "MySQL 的列名应当使用反引号。")
+		assert.Equal(t, `T0."score"`, (&aggregator{meta: meta, quote: tableQuote(orm.DRPostgres)}).column(score), "PostgreSQL 的列名应当使用双引号。")
+		assert.Equal(t, "Name", agg.carrier(score).name, "存在字符字段时应当使用字符字段承载结果。")
+
+		agg.average(score)
+		assert.Equal(t, []string{"SUM(T0.`score`)", "COUNT(T0.`score`)"}, agg.exprs, "平均值应当查询总和及数量。")
+		assert.Equal(t, []string{"Name", "Name"}, agg.carriers, "平均值的总和及数量应当由字符字段承载。")
+
+		// 不存在字符字段时，整型列使用 64 位整型字段承载，其他列使用浮点字段承载
+		id, _ := agg.field("id")
+		fields := &beegoFieldMap{pk: id, fieldsDB: []*beegoFieldInfo{
+			id,
+			{name: "Big", fieldType: orm.TypeBigIntegerField},
+			{name: "Score", fieldType: orm.TypeFloatField},
+		}}
+		nagg := &aggregator{meta: &modelMeta{beegoModelInfo: &beegoModelInfo{fields: fields}}}
+		assert.Equal(t, "Big", nagg.carrier(id).name, "整型列应当使用 64 位整型字段承载结果。")
+		assert.Equal(t, "Big", nagg.carrier(nil).name, "COUNT(*) 应当使用 64 位整型字段承载结果。")
+		assert.Equal(t, "Score", nagg.carrier(score).name, "浮点列应当使用浮点字段承载结果。")
+		fields.fieldsDB = []*beegoFieldInfo{id}
+		assert.Equal(t, id, nagg.carrier(nil), "均不存在时 COUNT(*) 应当使用主键承载结果。")
+		assert.Equal(t, score, nagg.carrier(score), "均不存在时应当使用聚合的字段本身承载结果。")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		defer orm.ResetModelCache()
+		orm.ResetModelCache()
+		model := XObject.New[TestSQLModel]()
+
+		_, err := Max[int64](model, "id")
+		assert.Error(t, err, "未注册的模型应当返回错误。")
+		assert.Equal(t, -1, model.Max(), "未注册的模型的 Max 应当返回 -1。")
+		assert.Equal(t, -1, model.Min(), "未注册的模型的 Min 应当返回 -1。")
+
+		Meta(model, false, true)
+		_, err = Sum[int64](model, "score")
+		assert.Error(t, err, "数据库未注册时应当返回错误而非 panic。")
+		assert.Equal(t, -1, model.Max("id"), "数据库未注册时 Max 应当返回 -1。")
+		assert.Equal(t, -1, model.Min("unknown"), "未知的列名的 Min 应当返回 -1。")
+	})
+
+	SetupBaseTest()
+	defer ResetBaseTest()
+	model := NewTestBaseModel()
+	WriteBaseTest(5)
+
+	t.Run("Sum", func(t *testing.T) {
+		isum, err := Sum[int64](model, "int_val")
+		assert.NoError(t, err, "Sum 不应当返回错误。")
+		assert.Equal(t, int64(15), isum, "整型列的总和应当为 15。")
+		assert.Equal(t, "Sum", model.onQueryParams[0], "OnQuery 的类型应当为 Sum。")
+
+		fsum, err := Sum[float64](model, "float_val", Cond("int_val > {0}", 3))
+		assert.NoError(t, err, "带条件的 Sum 不应当返回错误。")
+		assert.Equal(t, 10.0, fsum, "带条件的浮点列的总和应当为 10。")
+
+		isum, err = Sum[int64](model, "int_val", Cond("int_val > {0}", 10))
+		assert.NoError(t, err, "无匹配记录的 Sum 不应当返回错误。")
+		assert.Equal(t, int64(0), isum, "无匹配记录的总和应当为 0。")
+	})
+
+	t.Run("Avg", func(t *testing.T) {
+		avg, err := Avg(model, "int_val", Cond("int_val <= {0}", 2))
+		assert.NoError(t, err, "Avg 不应当返回错误。")
+		assert.Equal(t, 1.5, avg, "整型列的平均值应当为 1.5。")
+		assert.Equal(t, "Avg", model.onQueryParams[0], "OnQuery 的类型应当为 Avg。")
+
+		_, err = Avg(model, "int_val", Cond("int_val > {0}", 10))
+		assert.ErrorIs(t, err, orm.ErrNoRows, "无匹配记录的 Avg 应当返回 ErrNoRows。")
+	})
+
+	t.Run("Max", func(t *testing.T) {
+		imax, err := Max[int64](model, "int_val", Cond("int_val < {0}", 4))
+		assert.NoError(t, err, "Max 不应当返回错误。")
+		assert.Equal(t, int64(3), imax, "带条件的最大值应当为 3。")
+
+		fmax, err := Max[float64](model, "float_val")
+		assert.NoError(t, err, "浮点列的 Max 不应当返回错误。")
+		assert.Equal(t, 5.5, fmax, "浮点列的最大值应当为 5.5。")
+
+		smax, err := Max[string](model, "string_val")
+		assert.NoError(t, err, "字符串列的 Max 不应当返回错误。")
+		assert.Equal(t, "test_string_5", smax, "字符串列的最大值应当为 test_string_5。")
+
+		_, err = Max[int64](model, "int_val", Cond("int_val > {0}", 10))
+		assert.ErrorIs(t, err, orm.ErrNoRows, "无匹配记录的 Max 应当返回 ErrNoRows。")
+
+		_, err = Max[int64](model, "unknown")
+		assert.Error(t, err, "未知的列名应当返回错误。")
+	})
+
+	t.Run("Min", func(t *testing.T) {
+		imin, err := Min[int64](model, "", Cond("int_val > {0}", 2))
+		assert.NoError(t, err, "Min 不应当返回错误。")
+		assert.Equal(t, int64(3), imin, "带条件的主键最小值应当为 3。")
+		assert.Equal(t, "Min", model.onQueryParams[0], "OnQuery 的类型应当为 Min。")
+	})
+
+	t.Run("Distinct", func(t *testing.T) {
+		values, err := Distinct[int64](model, "bool_val")
+		assert.NoError(t, err, "Distinct 不应当返回错误。")
+		assert.Equal(t, []int64{0, 1}, values, "去重值应当按升序排列。")
+		assert.Equal(t, "Distinct", model.onQueryParams[0], "OnQuery 的类型应当为 Distinct。")
+	})
+
+	t.Run("GroupBy", func(t *testing.T) {
+		rows, err := GroupBy(model, []string{"bool_val"}, []Aggregation{
+			{Func: "COUNT"},
+			{Func: "SUM", Column: "int_val"},
+			{Func: "AVG", Column: "int_val", Alias: "avg"},
+			{Func: "MAX", Column: "float_val"},
+		})
+		assert.NoError(t, err, "GroupBy 不应当返回错误。")
+		assert.Equal(t, "GroupBy", model.onQueryParams[0], "OnQuery 的类型应当为 GroupBy。")
+		assert.Equal(t, []orm.Params{
+			{"bool_val": false, "count": int64(3), "sum_int_val": int64(9), "avg": 3.0, "max_float_val": 5.5},
+			{"bool_val": true, "count": int64(2), "sum_int_val": int64(6), "avg": 3.0, "max_float_val": 4.5},
+		}, rows, "分组统计的结果应当和预期相等。")
+
+		_, err = GroupBy(model, []string{"bool_val"}, []Aggregation{{Func: "MEDIAN", Column: "int_val"}})
+		assert.Error(t, err, "不支持的聚合函数应当返回错误。")
+	})
+}
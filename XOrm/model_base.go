@@ -5,9 +5,11 @@
 package XOrm
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 	"unsafe"
 
 	"github.com/beego/beego/v2/client/orm"
@@ -37,7 +39,7 @@ type IModel interface {
 	// OnQuery 在执行查询时调用。
 	// 子类可以重写此方法以实现自定义的查询逻辑。
 	// 通常用于在执行查询前追加一个全局的条件，如数据分区等。
-	// action 是查询的类型，包括：Count、Max、Min、Read、List、Delete、Clear。
+	// action 是查询的类型，包括：Count、Max、Min、Sum、Avg、Distinct、GroupBy、Read、List、ListColumns、Each、Delete、Clear。
 	// cond 是查询的条件，传入的值可能为空。
 	// 返回执行查询的最终条件。
 	OnQuery(action string, cond *orm.Condition) *orm.Condition
@@ -74,12 +76,12 @@ type IModel interface {
 
 	// Max 获取指定列的最大值。
 	// column 为可选的列名，若不指定则使用主键列。
-	// 返回最大值，如果发生错误则返回 -1。
+	// 返回最大值，空表返回 0，如果发生错误则返回 -1。
 	Max(column ...string) int
 
 	// Min 获取指定列的最小值。
 	// column 为可选的列名，若不指定则使用主键列。
-	// 返回最小值，如果发生错误则返回 -1。
+	// 返回最小值，空表返回 0，如果发生错误则返回 -1。
 	Min(column ...string) int
 
 	// Write 写入或更新当前记录。
@@ -88,14 +90,14 @@ type IModel interface {
 	Write() int
 
 	// Read 读取符合条件的记录。
-	// cond 为可选的查询条件，若不指定则使用主键作为查询条件。
+	// cond 为可选的查询条件，若不指定则使用主键作为查询条件，指定了排序规则时读取排序后的首条记录。
 	// 读取成功后会调用 OnDecode 进行解码处理。
 	// 返回是否成功读取到记录。
 	Read(cond ...*Condition) bool
 
 	// List 查询符合条件的记录列表。
 	// rets 必须是指向切片的指针，用于存储查询结果。
-	// cond 为可选的查询条件，可以指定偏移量和限制数量。
+	// cond 为可选的查询条件，可以指定偏移量、限制数量和排序规则，分页时默认按主键排序。
 	// 返回查询到的记录数量，如果发生错误则返回 -1。
 	List(rets any, cond ...*Condition) int
 
@@ -135,7 +137,59 @@ type IModel interface {
 	Matchs(cond ...*Condition) bool
 }
 
-// Model 实现了 IModel 接口的基础模型。
+// IModelE 定义了数据模型返回错误的可选接口，Model 实现了此接口。
+// 为兼容自行实现 IModel 的类型，该接口未合并至 IModel，模型未实现此接口时使用 IModel 的对应方法并返回通用的错误。
+type IModelE interface {
+	// CountE 统计符合条件的记录数量。
+	// cond 为可选的查询条件。
+	// 返回记录数量及发生的错误。
+	CountE(cond ...*Condition) (int, error)
+
+	// WriteE 写入或更新当前记录。
+	// 返回受影响的行数及发生的错误。
+	WriteE() (int, error)
+
+	// ReadE 读取符合条件的记录。
+	// 返回发生的错误，未找到记录时返回的错误匹配 ErrNotFound 及 orm.ErrNoRows。
+	ReadE(cond ...*Condition) error
+
+	// ListE 查询符合条件的记录列表。
+	// 返回查询到的记录数量及发生的错误。
+	ListE(rets any, cond ...*Condition) (int, error)
+
+	// DeleteE 删除当前记录。
+	// 返回受影响的行数及发生的错误。
+	DeleteE() (int, error)
+
+	// ClearE 清理符合条件的记录。
+	// 返回受影响的行数及发生的错误。
+	ClearE(cond ...*Condition) (int, error)
+}
+
+// modelReadE 读取符合条件的记录，模型未实现 IModelE 时使用 Read，未读取到记录时返回的错误匹配 ErrNotFound。
+func modelReadE(model IModel, cond ...*Condition) error {
+	if emodel, ok := model.(IModelE); ok {
+		return emodel.ReadE(cond...)
+	}
+	if !model.Read(cond...) {
+		return fmt.Errorf("%w: read of %v failed", ErrNotFound, model.ModelUnique())
+	}
+	return nil
+}
+
+// modelListE 查询符合条件的记录列表，模型未实现 IModelE 时使用 List。
+func modelListE(model IModel, rets any, cond ...*Condition) (int, error) {
+	if emodel, ok := model.(IModelE); ok {
+		return emodel.ListE(rets, cond...)
+	}
+	count := model.List(rets, cond...)
+	if count < 0 {
+		return count, fmt.Errorf("list of %v failed", model.ModelUnique())
+	}
+	return count, nil
+}
+
+// Model 实现了 IModel 及 IModelE 接口的基础模型。
 // T 为具体的模型类型，必须是结构体类型。
 // 所有的具体模型类型都应该嵌入此类型。
 type Model[T any] struct {
@@ -168,7 +222,7 @@ func (md *Model[T]) OnDecode() {}
 // OnQuery 在执行查询时调用。
 // 子类可以重写此方法以实现自定义的查询逻辑。
 // 通常用于在执行查询前追加一个全局的条件，如数据分区等。
-// action 是查询的类型，包括：Count、Max、Min、Read、List、Delete、Clear。
+// action 是查询的类型，包括：Count、Max、Min、Sum、Avg、Distinct、GroupBy、Read、List、ListColumns、Each、Delete、Clear。
 // cond 是查询的条件，传入的值可能为空。
 // 返回执行查询的最终条件。
 func (md *Model[T]) OnQuery(action string, cond *orm.Condition) *orm.Condition { return cond }
@@ -226,9 +280,20 @@ func (md *Model[T]) DataValue(field string) any {
 // cond 为可选的查询条件。
 // 返回记录数量，如果发生错误则返回 -1。
 func (md *Model[T]) Count(cond ...*Condition) int {
-	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
-		XLog.Error("XOrm.Model.Count(%v): failed to create orm instance of %v.", md.this.TableName(), md.this.AliasName())
+	count, err := md.CountE(cond...)
+	if err != nil {
+		XLog.Warn("XOrm.Model.Count(%v): %v", md.this.TableName(), err)
 		return -1
+	}
+	return count
+}
+
+// CountE 统计符合条件的记录数量。
+// cond 为可选的查询条件。
+// 返回记录数量及发生的错误。
+func (md *Model[T]) CountE(cond ...*Condition) (int, error) {
+	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
+		return -1, fmt.Errorf("failed to create orm instance of %v", md.this.AliasName())
 	} else {
 		query := ormer.QueryTable(md.this)
 		if len(cond) > 0 && cond[0] != nil {
@@ -242,153 +307,116 @@ func (md *Model[T]) Count(cond ...*Condition) int {
 		}
 		count, err := query.Count()
 		if err != nil {
-			XLog.Warn("XOrm.Model.Count(%v): %v", md.this.TableName(), err)
-			return -1
-		} else {
-			return int(count)
+			return -1, err
 		}
+		return int(count), nil
 	}
 }
 
 // Max 获取指定列的最大值。
 // column 为可选的列名，若不指定则使用主键列。
-// 返回最大值，如果发生错误则返回 -1。
+// 返回最大值，空表返回 0，如果发生错误则返回 -1。
+// 如需按条件查询或获取浮点、时间等类型的结果，请使用 XOrm.Max。
 func (md *Model[T]) Max(column ...string) int {
-	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
-		XLog.Error("XOrm.Model.Max(%v): failed to create orm instance of %v.", md.this.TableName(), md.this.AliasName())
+	name := ""
+	if len(column) > 0 {
+		name = column[0]
+	}
+	val, err := Max[int64](md.this, name)
+	if errors.Is(err, orm.ErrNoRows) { // 空表返回的结果为 nil，这里返回 0。
+		return 0
+	} else if err != nil {
+		XLog.Error("XOrm.Model.Max(%v): %v", md.this.TableName(), err)
 		return -1
-	} else {
-		name := ""
-		if len(column) > 0 {
-			name = column[0]
-		}
-		if name == "" {
-			meta := getModelMeta(md.this)
-			if meta != nil && meta.fields.pk != nil {
-				name = meta.fields.pk.column
-			}
-		}
-		if name == "" {
-			XLog.Error("XOrm.Model.Max(%v): column was empty.", md.this.TableName())
-			return -1
-		}
-		query := ormer.QueryTable(md.this)
-		cond := md.this.OnQuery("Max", nil)
-		if cond != nil {
-			query = query.SetCond(cond)
-		}
-		var result orm.ParamsList
-		count, err := query.Aggregate(fmt.Sprintf("MAX(`%v`)", name)).ValuesFlat(&result, name)
-		if err != nil {
-			XLog.Error("XOrm.Model.Max(%v): %v", md.this.TableName(), err)
-			return -1
-		}
-		if count <= 0 || len(result) <= 0 {
-			XLog.Error("XOrm.Model.Max(%v): empty result count.", md.this.TableName(), err)
-			return -1
-		}
-		if result[0] == nil { // 空表返回的计数为 nil，这里返回 0。
-			return 0
-		}
-		val, ok := toInt64(result[0])
-		if !ok {
-			XLog.Error("XOrm.Model.Max(%v): parse result failed: %v.", md.this.TableName(), result[0])
-			return -1
-		}
-		return int(val)
 	}
+	return int(val)
 }
 
 // Min 获取指定列的最小值。
 // column 为可选的列名，若不指定则使用主键列。
-// 返回最小值，如果发生错误则返回 -1。
+// 返回最小值，空表返回 0，如果发生错误则返回 -1。
+// 如需按条件查询或获取浮点、时间等类型的结果，请使用 XOrm.Min。
 func (md *Model[T]) Min(column ...string) int {
-	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
-		XLog.Error("XOrm.Model.Min(%v): failed to create orm instance of %v.", md.this.TableName(), md.this.AliasName())
+	name := ""
+	if len(column) > 0 {
+		name = column[0]
+	}
+	val, err := Min[int64](md.this, name)
+	if errors.Is(err, orm.ErrNoRows) { // 空表返回的结果为 nil，这里返回 0。
+		return 0
+	} else if err != nil {
+		XLog.Error("XOrm.Model.Min(%v): %v", md.this.TableName(), err)
 		return -1
-	} else {
-		name := ""
-		if len(column) > 0 {
-			name = column[0]
-		}
-		if name == "" {
-			meta := getModelMeta(md.this)
-			if meta != nil && meta.fields.pk != nil {
-				name = meta.fields.pk.column
-			}
-		}
-		if name == "" {
-			XLog.Error("XOrm.Model.Min(%v): column was empty.", md.this.TableName())
-			return -1
-		}
-		query := ormer.QueryTable(md.this)
-		cond := md.this.OnQuery("Min", nil)
-		if cond != nil {
-			query = query.SetCond(cond)
-		}
-		var result orm.ParamsList
-		count, err := query.Aggregate(fmt.Sprintf("MIN(`%v`)", name)).ValuesFlat(&result, name)
-		if err != nil {
-			XLog.Error("XOrm.Model.Min(%v): %v", md.this.TableName(), err)
-			return -1
-		}
-		if count <= 0 || len(result) <= 0 {
-			XLog.Error("XOrm.Model.Min(%v): empty result count.", md.this.TableName(), err)
-			return -1
-		}
-		if result[0] == nil { // 空表返回的计数为 nil，这里返回 0。
-			return 0
-		}
-		val, ok := toInt64(result[0])
-		if !ok {
-			XLog.Error("XOrm.Model.Min(%v): parse result failed: %v.", md.this.TableName(), result[0])
-			return -1
-		}
-		return int(val)
 	}
+	return int(val)
 }
 
 // Write 写入或更新当前记录。
 // 在写入前会调用 OnEncode 进行编码处理。
 // 返回受影响的行数，如果发生错误则返回 -1。
 func (md *Model[T]) Write() int {
+	count, err := md.WriteE()
+	if err != nil {
+		XLog.Error("XOrm.Model.Write(%v): %v", md.this.TableName(), err)
+		return -1
+	}
+	return count
+}
+
+// WriteE 写入或更新当前记录。
+// 在写入前会调用 OnEncode 进行编码处理。
+// 返回受影响的行数及发生的错误。
+func (md *Model[T]) WriteE() (int, error) {
 	md.this.IsValid(true)
 	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
-		XLog.Error("XOrm.Model.Write(%v): failed to create orm instance of %v.", md.this.TableName(), md.this.AliasName())
-		return -1
+		return -1, fmt.Errorf("failed to create orm instance of %v", md.this.AliasName())
 	} else {
 		md.this.OnEncode()
 		count, err := ormer.InsertOrUpdate(md.this)
 		if err != nil {
-			XLog.Error("XOrm.Model.Write(%v): %v", md.this.TableName(), err)
-			return -1
+			return -1, err
 		}
-		return int(count)
+		return int(count), nil
 	}
 }
 
 // Read 读取符合条件的记录。
-// cond 为可选的查询条件，若不指定则使用主键作为查询条件。
+// cond 为可选的查询条件，若不指定则使用主键作为查询条件，指定了排序规则时读取排序后的首条记录。
 // 读取成功后会调用 OnDecode 进行解码处理。
 // 返回是否成功读取到记录。
 func (md *Model[T]) Read(cond ...*Condition) bool {
-	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
-		XLog.Error("XOrm.Model.Read(%v): failed to create orm instance of %v.", md.this.TableName(), md.this.AliasName())
+	if err := md.ReadE(cond...); err != nil {
+		XLog.Warn("XOrm.Model.Read(%v): %v", md.this.TableName(), err)
 		return false
+	}
+	return true
+}
+
+// ReadE 读取符合条件的记录。
+// cond 为可选的查询条件，若不指定则使用主键作为查询条件，指定了排序规则时读取排序后的首条记录。
+// 读取成功后会调用 OnDecode 进行解码处理。
+// 返回发生的错误，未找到记录时返回的错误匹配 ErrNotFound 及 orm.ErrNoRows。
+func (md *Model[T]) ReadE(cond ...*Condition) error {
+	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
+		return fmt.Errorf("failed to create orm instance of %v", md.this.AliasName())
 	} else {
 		meta := getModelMeta(md.this)
 		if meta == nil {
-			XLog.Error("XOrm.Model.Read(%v): model info is nil", md.this.TableName())
-			return false
+			return fmt.Errorf("%w: %v", ErrNotRegistered, md.this.ModelUnique())
 		}
 		if meta.fields.pk == nil {
-			XLog.Error("XOrm.Model.Read(%v): primary key was not found", md.this.TableName())
-			return false
+			return fmt.Errorf("primary key of %v was not found", md.this.TableName())
 		}
 		query := ormer.QueryTable(md.this)
 		if len(cond) > 0 && cond[0] != nil {
+			if err := cond[0].checkOrders(meta); err != nil {
+				return err
+			}
 			ncond := md.this.OnQuery("Read", cond[0].Base)
 			query = query.SetCond(ncond)
+			if len(cond[0].Orders) > 0 { // 读取排序后的首条记录
+				query = query.OrderBy(cond[0].orderBy(meta)...)
+			}
 		} else {
 			ncond := orm.NewCondition().And(meta.fields.pk.column, md.this.DataValue(meta.fields.pk.name)) // 附加主键值
 			ncond = md.this.OnQuery("Read", ncond)
@@ -397,35 +425,54 @@ func (md *Model[T]) Read(cond ...*Condition) bool {
 		that := md.this // query.One() 会修改对象，所以需要暂存指针
 		e := query.One(that)
 		md.this = that // 恢复指针
-		if e != nil {
-			XLog.Warn("XOrm.Model.Read(%v): %v", md.this.TableName(), e)
-			return false
+		if errors.Is(e, orm.ErrNoRows) {
+			return fmt.Errorf("%w: %w", ErrNotFound, e)
+		} else if e != nil {
+			return e
 		} else {
 			md.this.IsValid(true)
 			md.this.OnDecode()
-			return true
+			return nil
 		}
 	}
 }
 
 // List 查询符合条件的记录列表。
 // rets 必须是指向切片的指针，用于存储查询结果。
-// cond 为可选的查询条件，可以指定偏移量和限制数量。
+// cond 为可选的查询条件，可以指定偏移量、限制数量和排序规则，分页时默认按主键排序。
 // 返回查询到的记录数量，如果发生错误则返回 -1。
 func (md *Model[T]) List(rets any, cond ...*Condition) int {
-	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
-		XLog.Error("XOrm.Model.List(%v): failed to create orm instance of %v.", md.this.TableName(), md.this.AliasName())
+	count, err := md.ListE(rets, cond...)
+	if err != nil {
+		XLog.Warn("XOrm.Model.List(%v): %v", md.this.TableName(), err)
 		return -1
+	}
+	return count
+}
+
+// ListE 查询符合条件的记录列表。
+// rets 必须是指向切片的指针，用于存储查询结果。
+// cond 为可选的查询条件，可以指定偏移量、限制数量和排序规则，分页时默认按主键排序。
+// 返回查询到的记录数量及发生的错误。
+func (md *Model[T]) ListE(rets any, cond ...*Condition) (int, error) {
+	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
+		return -1, fmt.Errorf("failed to create orm instance of %v", md.this.AliasName())
 	} else {
 		val := reflect.ValueOf(rets)
 		if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Slice {
-			XLog.Error("XOrm.Model.List(%v): rets must be a pointer to a slice.", md.this.TableName())
-			return -1
+			return -1, fmt.Errorf("rets must be a pointer to a slice")
 		}
 
+		meta := getModelMeta(md.this)
+		if meta == nil {
+			return -1, fmt.Errorf("%w: %v", ErrNotRegistered, md.this.ModelUnique())
+		}
 		query := ormer.QueryTable(md.this)
 		if len(cond) > 0 && cond[0] != nil {
 			cond0 := cond[0]
+			if err := cond0.checkOrders(meta); err != nil {
+				return -1, err
+			}
 			ncond := md.this.OnQuery("List", cond0.Base)
 			query = query.SetCond(ncond)
 			if cond0.Offset > 0 {
@@ -434,6 +481,9 @@ func (md *Model[T]) List(rets any, cond ...*Condition) int {
 			if cond0.Limit > 0 {
 				query = query.Limit(cond0.Limit)
 			}
+			if orders := cond0.orderBy(meta); len(orders) > 0 { // 分页时默认按主键排序
+				query = query.OrderBy(orders...)
+			}
 		} else {
 			ncond := md.this.OnQuery("List", nil)
 			if ncond != nil {
@@ -442,9 +492,8 @@ func (md *Model[T]) List(rets any, cond ...*Condition) int {
 		}
 
 		tcount, terr := query.All(val.Elem().Addr().Interface())
-		if terr != nil {
-			XLog.Warn("XOrm.Model.List(%v): %v", md.this.TableName(), terr)
-			return -1
+		if terr != nil && !errors.Is(terr, orm.ErrNoRows) {
+			return -1, terr
 		}
 
 		if tcount > 0 {
@@ -459,7 +508,7 @@ func (md *Model[T]) List(rets any, cond ...*Condition) int {
 			}
 		}
 
-		return int(tcount)
+		return int(tcount), nil
 	}
 }
 
@@ -467,28 +516,36 @@ func (md *Model[T]) List(rets any, cond ...*Condition) int {
 // 使用主键作为删除条件。
 // 返回受影响的行数，如果发生错误则返回 -1。
 func (md *Model[T]) Delete() int {
-	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
-		XLog.Error("XOrm.Model.Delete(%v): failed to create orm instance of %v.", md.this.TableName(), md.this.AliasName())
+	count, err := md.DeleteE()
+	if err != nil {
+		XLog.Error("XOrm.Model.Delete(%v): %v", md.this.TableName(), err)
 		return -1
+	}
+	return count
+}
+
+// DeleteE 删除当前记录。
+// 使用主键作为删除条件。
+// 返回受影响的行数及发生的错误。
+func (md *Model[T]) DeleteE() (int, error) {
+	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
+		return -1, fmt.Errorf("failed to create orm instance of %v", md.this.AliasName())
 	} else {
 		meta := getModelMeta(md.this)
 		if meta == nil {
-			XLog.Error("XOrm.Model.Delete(%v): model info is nil", md.this.TableName())
-			return -1
+			return -1, fmt.Errorf("%w: %v", ErrNotRegistered, md.this.ModelUnique())
 		}
 		if meta.fields.pk == nil {
-			XLog.Error("XOrm.Model.Delete(%v): primary key was not found", md.this.TableName())
-			return -1
+			return -1, fmt.Errorf("primary key of %v was not found", md.this.TableName())
 		}
 		cond := orm.NewCondition().And(meta.fields.pk.column, md.this.DataValue(meta.fields.pk.name)) // 附加主键值
 		cond = md.this.OnQuery("Delete", cond)
 		query := ormer.QueryTable(md.this).SetCond(cond)
 		count, err := query.Delete()
 		if err != nil {
-			XLog.Error("XOrm.Model.Delete(%v): %v", md.this.TableName(), err)
-			return -1
+			return -1, err
 		}
-		return int(count)
+		return int(count), nil
 	}
 }
 
@@ -497,9 +554,20 @@ func (md *Model[T]) Delete() int {
 // 返回受影响的行数，如果发生错误则返回 -1。
 // 注意：MySQL Connector 最大的参数是 65535，清理大量数据时可能会触发错误：Prepared statement contains too many placeholders，解决方法为分批执行清理。
 func (md *Model[T]) Clear(cond ...*Condition) int {
-	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
-		XLog.Error("XOrm.Model.Clear(%v): failed to create orm instance of %v.", md.this.TableName(), md.this.AliasName())
+	count, err := md.ClearE(cond...)
+	if err != nil {
+		XLog.Error("XOrm.Model.Clear(%v): %v", md.this.TableName(), err)
 		return -1
+	}
+	return count
+}
+
+// ClearE 清理符合条件的记录。
+// cond 为可选的查询条件，若不指定则清理所有记录。
+// 返回受影响的行数及发生的错误。
+func (md *Model[T]) ClearE(cond ...*Condition) (int, error) {
+	if ormer := orm.NewOrmUsingDB(md.this.AliasName()); ormer == nil {
+		return -1, fmt.Errorf("failed to create orm instance of %v", md.this.AliasName())
 	} else {
 		query := ormer.QueryTable(md.this.TableName())
 		var ncond *Condition
@@ -509,12 +577,10 @@ func (md *Model[T]) Clear(cond ...*Condition) int {
 			// beego orm 的 Delete 方法不支持条件，所以需要使用主键字段 >= 0 作为条件，这样可以匹配所有记录
 			meta := getModelMeta(md.this)
 			if meta == nil {
-				XLog.Error("XOrm.Model.Clear(%v): model info is nil.", md.this.TableName())
-				return -1
+				return -1, fmt.Errorf("%w: %v", ErrNotRegistered, md.this.ModelUnique())
 			}
 			if meta.fields.pk == nil {
-				XLog.Error("XOrm.Model.Clear(%v): primary key was not found.", md.this.TableName())
-				return -1
+				return -1, fmt.Errorf("primary key of %v was not found", md.this.TableName())
 			}
 			ncond = Cond(fmt.Sprintf("%v >= {0}", meta.fields.pk.column), 0)
 		}
@@ -529,10 +595,9 @@ func (md *Model[T]) Clear(cond ...*Condition) int {
 
 		count, err := query.Delete()
 		if err != nil {
-			XLog.Error("XOrm.Model.Clear(%v): %v", md.this.TableName(), err)
-			return -1
+			return -1, err
 		}
-		return int(count)
+		return int(count), nil
 	}
 }
 
@@ -547,6 +612,7 @@ func (md *Model[T]) IsValid(value ...bool) bool {
 }
 
 // Clone 创建对象的深度拷贝。
+// 字段中的切片、字典、指针及接口等引用类型会被递归拷贝，字段类型可以实现 IDeepCopy 接口以自定义拷贝逻辑。
 // 拷贝后会调用 OnDecode 进行解码处理。
 // 返回新的对象实例，如果拷贝失败则返回 nil。
 func (md *Model[T]) Clone() IModel {
@@ -557,7 +623,7 @@ func (md *Model[T]) Clone() IModel {
 		XLog.Error("XOrm.Model.Clone(%v): invalid pointer.", md.this.TableName())
 		return md.this
 	}
-	*pdst = *psrc
+	deepClone(pdst, psrc) // 切片、字典及指针等引用类型的字段亦会被拷贝，避免会话、快照及全局内存共享数据
 
 	if model, ok := any(dst).(IModel); ok {
 		model.Ctor(dst)
@@ -578,6 +644,8 @@ func (md *Model[T]) Json() string {
 
 // Equals 比较两个对象是否相等。
 // model 为待比较的对象。
+// 字段按类型进行比较：时间按时刻比较，指针按指向的值比较，切片、字典及结构体按元素递归比较，
+// 可以通过 RegisterComparer 注册指定类型的比较函数，比较计划会按模型类型缓存。
 // 返回两个对象的所有数据库字段是否完全相等。
 func (md *Model[T]) Equals(model IModel) bool {
 	if md.this == model {
@@ -587,22 +655,20 @@ func (md *Model[T]) Equals(model IModel) bool {
 		return md.this == model
 	}
 
+	thisAddr := reflect.ValueOf(md.this).Elem()
+	compAddr := reflect.ValueOf(model).Elem()
+	if thisAddr.Type() != compAddr.Type() {
+		return false
+	}
+
 	meta := getModelMeta(md.this)
 	if meta == nil {
 		return false
 	}
 
-	thisAddr := reflect.ValueOf(md.this).Elem()
-	compAddr := reflect.ValueOf(model).Elem()
-
-	for _, field := range meta.fields.fieldsDB {
-		thisVal := thisAddr.FieldByName(field.name).Interface()
-		compFld := compAddr.FieldByName(field.name)
-		if !compFld.IsValid() {
-			return false
-		}
-		compVal := compFld.Interface()
-		if thisVal != compVal {
+	ctx := &equalContext{}
+	for _, field := range getEqualPlan(meta, thisAddr.Type()) {
+		if !field.equal(ctx, thisAddr.FieldByIndex(field.index), compAddr.FieldByIndex(field.index)) {
 			return false
 		}
 	}
@@ -666,6 +732,10 @@ func doMatch(model IModel, meta *modelMeta, ctx *Condition, conds []beegoCondVal
 //
 //	整型支持: Int, Int32, Int64
 //	浮点支持: Float32, Float64
+//	操作符支持: exact, ne, gt, gte, lt, lte, in, between, isnull, contains, startswith, endswith,
+//	          iexact, icontains, istartswith, iendswith（忽略大小写）
+//
+// 字符串操作符按字面值比较，与数据库 LIKE 中 _ 作为通配符的行为不同（如 iexact "a_b" 不匹配 "A-B"）。
 func doComp(model IModel, meta *modelMeta, ctx *Condition, cond beegoCondValue, depth int) bool {
 	if !(len(cond.exprs) > 0 && len(cond.args) > 0) {
 		return false
@@ -683,11 +753,13 @@ func doComp(model IModel, meta *modelMeta, ctx *Condition, cond beegoCondValue,
 		return isNullValue(cvalue, ctype)
 	case "in":
 		return handleInOperator(ctx, cvalue, cond.args, field, depth)
+	case "between":
+		return handleBetweenOperator(cvalue, ctype, cond.args)
 	case "exact", "ne":
 		return handleExactOperator(cvalue, ctype, operator, cond.args[0])
 	case "gt", "gte", "lt", "lte":
 		return handleComparisonOperator(cvalue, ctype, operator, cond.args[0])
-	case "contains", "startswith", "endswith":
+	case "contains", "startswith", "endswith", "iexact", "icontains", "istartswith", "iendswith":
 		return handleStringOperator(cvalue, ctype, operator, cond.args[0])
 	default:
 		XLog.Error("XOrm.Model.doComp: operator: %v wasn't supported for table: %v", operator, model.TableName())
@@ -738,6 +810,9 @@ func handleInOperator(ctx *Condition, cvalue any, args []any, field string, dept
 	if len(args) == 0 {
 		return false
 	}
+	if len(args) > 1 { // 多个参数，如 And("id__in", 1, 2, 3)
+		return handleInValues(cvalue, sqlFlatParams(args))
+	}
 
 	switch arg0 := args[0].(type) {
 	case int32:
@@ -915,9 +990,44 @@ func handleInOperator(ctx *Condition, cvalue any, args []any, field string, dept
 		_, exists := nargs[nvalue]
 		return exists
 	}
+	return handleInValues(cvalue, sqlFlatParams(args))
+}
+
+// handleInValues 逐个比较 IN 操作符的参数，用于处理未缓存的参数类型。
+func handleInValues(cvalue any, args []any) bool {
+	ctype := reflect.TypeOf(cvalue)
+	for _, arg := range args {
+		if handleExactOperator(cvalue, ctype, "exact", arg) {
+			return true
+		}
+	}
 	return false
 }
 
+// handleBetweenOperator 处理 BETWEEN 操作符，匹配闭区间 [min, max]。
+func handleBetweenOperator(cvalue any, ctype reflect.Type, args []any) bool {
+	params := sqlFlatParams(args)
+	if len(params) != 2 {
+		return false
+	}
+
+	switch {
+	case isNumericType(ctype):
+		return handleComparisonOperator(cvalue, ctype, "gte", params[0]) &&
+			handleComparisonOperator(cvalue, ctype, "lte", params[1])
+	case ctype.Kind() == reflect.String:
+		lower, ok1 := params[0].(string)
+		upper, ok2 := params[1].(string)
+		str := reflect.ValueOf(cvalue).String()
+		return ok1 && ok2 && str >= lower && str <= upper
+	default:
+		ctime, ok := cvalue.(time.Time)
+		lower, ok1 := params[0].(time.Time)
+		upper, ok2 := params[1].(time.Time)
+		return ok && ok1 && ok2 && !ctime.Before(lower) && !ctime.After(upper)
+	}
+}
+
 // handleExactOperator 处理精确匹配操作符。
 func handleExactOperator(cvalue any, ctype reflect.Type, operator string, arg any) bool {
 	switch {
@@ -973,7 +1083,9 @@ func handleComparisonOperator(cvalue any, ctype reflect.Type, operator string, a
 	}
 
 	if isIntegerType(ctype) {
-		return handleIntegerComparisonOperator(cvalue, operator, arg)
+		if _, ok := toInt64(arg); ok {
+			return handleIntegerComparisonOperator(cvalue, operator, arg)
+		}
 	}
 	return handleFloatComparisonOperator(cvalue, operator, arg)
 }
@@ -1002,8 +1114,8 @@ func handleIntegerComparisonOperator(cvalue any, operator string, arg any) bool
 
 // handleFloatComparisonOperator 处理浮点类型的比较操作。
 func handleFloatComparisonOperator(cvalue any, operator string, arg any) bool {
-	cval, ok1 := toInt64(cvalue)
-	val, ok2 := toInt64(arg)
+	cval, ok1 := toFloat64(cvalue)
+	val, ok2 := toFloat64(arg)
 	if !ok1 || !ok2 {
 		return false
 	}
@@ -1028,8 +1140,11 @@ func handleStringOperator(cvalue any, ctype reflect.Type, operator string, arg a
 		return false
 	}
 
-	str := cvalue.(string)
-	pattern := arg.(string)
+	str := reflect.ValueOf(cvalue).String()
+	pattern, ok := arg.(string)
+	if !ok {
+		return false
+	}
 
 	switch operator {
 	case "contains":
@@ -1038,6 +1153,14 @@ func handleStringOperator(cvalue any, ctype reflect.Type, operator string, arg a
 		return strings.HasPrefix(str, pattern)
 	case "endswith":
 		return strings.HasSuffix(str, pattern)
+	case "iexact":
+		return strings.EqualFold(str, pattern)
+	case "icontains":
+		return strings.Contains(strings.ToLower(str), strings.ToLower(pattern))
+	case "istartswith":
+		return strings.HasPrefix(strings.ToLower(str), strings.ToLower(pattern))
+	case "iendswith":
+		return strings.HasSuffix(strings.ToLower(str), strings.ToLower(pattern))
 	default:
 		return false
 	}
@@ -1079,7 +1202,7 @@ func toInt64(v any) (int64, bool) {
 		return val, true
 	default:
 		rv := reflect.ValueOf(v)
-		if isIntegerType(rv.Type()) {
+		if rv.IsValid() && isIntegerType(rv.Type()) {
 			return rv.Int(), true
 		}
 		return 0, false
@@ -1102,6 +1225,9 @@ func toFloat64(v any) (float64, bool) {
 		if isFloatType(rv.Type()) {
 			return rv.Float(), true
 		}
+		if isIntegerType(rv.Type()) { // 整型参数与浮点字段比较
+			return float64(rv.Int()), true
+		}
 		return 0, false
 	}
 }
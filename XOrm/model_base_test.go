@@ -55,6 +55,7 @@ type TestBaseModel struct {
 	onEncodeCalled bool
 	onDecodeCalled bool
 	onQueryParams  []any
+	onQueryFilter  *orm.Condition // 不为空时由 OnQuery 追加至查询条件
 }
 
 func (m *TestBaseModel) AliasName() string {
@@ -73,6 +74,12 @@ func (m *TestBaseModel) OnQuery(action string, cond *orm.Condition) *orm.Conditi
 	m.onQueryParams = make([]any, 2)
 	m.onQueryParams[0] = action
 	m.onQueryParams[1] = cond
+	if m.onQueryFilter != nil {
+		if cond == nil || cond.IsEmpty() {
+			return m.onQueryFilter
+		}
+		return orm.NewCondition().AndCond(cond).AndCond(m.onQueryFilter)
+	}
 	return cond
 }
 
@@ -344,6 +351,25 @@ func TestModelList(t *testing.T) {
 		}
 	})
 
+	t.Run("ListOrder", func(t *testing.T) {
+		var results []*TestBaseModel
+		count := model.List(&results, Cond("orderby = -bool_val,-int_val && limit = {0}", 3))
+		if count != 3 {
+			t.Errorf("预期列表计数 3，实际得到 %d", count)
+		}
+		expected := []int{4, 2, 5}
+		for i, result := range results {
+			if result.IntVal != expected[i] {
+				t.Errorf("预期第 %d 条记录的 IntVal 为 %d，实际得到 %d", i, expected[i], result.IntVal)
+			}
+		}
+
+		read := NewTestBaseModel()
+		if !read.Read(Cond("int_val < {0} && orderby = -int_val", 4)) || read.IntVal != 3 {
+			t.Errorf("预期读取排序后的首条记录 IntVal 为 3，实际得到 %d", read.IntVal)
+		}
+	})
+
 	// 测试Max
 	t.Run("Max", func(t *testing.T) {
 		max := model.Max("int_val")
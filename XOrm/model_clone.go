@@ -0,0 +1,189 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"reflect"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// IDeepCopy 定义了自定义深度拷贝的接口。
+// 字段类型（非指针接收者）实现此接口时，深度拷贝将使用 DeepCopy 的返回值替代反射拷贝。
+// 返回值的类型必须与字段类型一致，否则将回退至反射拷贝。
+type IDeepCopy interface {
+	// DeepCopy 返回当前值的深度拷贝。
+	DeepCopy() any
+}
+
+// clonePlan 定义了类型的深度拷贝计划。
+type clonePlan struct {
+	deep   bool  // 是否包含引用类型（需要深度拷贝）
+	custom bool  // 是否实现了 IDeepCopy 接口
+	fields []int // 需要深度拷贝的结构体字段索引
+}
+
+var (
+	// clonePlanCache 存储类型的深度拷贝计划，键为 reflect.Type，值为 *clonePlan。
+	clonePlanCache sync.Map
+
+	// cloneModelType 是 IModel 接口的类型。
+	cloneModelType = reflect.TypeOf((*IModel)(nil)).Elem()
+
+	// cloneCustomType 是 IDeepCopy 接口的类型。
+	cloneCustomType = reflect.TypeOf((*IDeepCopy)(nil)).Elem()
+
+	// cloneTimeType 是 time.Time 的类型，其内部的时区指针是共享且不可变的，按值拷贝即可。
+	cloneTimeType = reflect.TypeOf(time.Time{})
+)
+
+// getClonePlan 获取指定类型的深度拷贝计划。
+// 计划会按类型缓存，指针、切片等引用类型始终视为需要深度拷贝，故递归类型无需递归构建子类型的计划。
+func getClonePlan(typ reflect.Type) *clonePlan {
+	if tmp, ok := clonePlanCache.Load(typ); ok {
+		return tmp.(*clonePlan)
+	}
+
+	plan := &clonePlan{}
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		plan.deep = true
+	case reflect.Array:
+		plan.deep = getClonePlan(typ.Elem()).deep
+	case reflect.Struct:
+		if typ != cloneTimeType {
+			for i := 0; i < typ.NumField(); i++ {
+				field := typ.Field(i)
+				if field.Anonymous && reflect.PointerTo(field.Type).Implements(cloneModelType) {
+					continue // 内嵌的基础模型会在 Ctor 中重置，无需拷贝
+				}
+				if getClonePlan(field.Type).deep {
+					plan.fields = append(plan.fields, i)
+				}
+			}
+			plan.deep = len(plan.fields) > 0
+		}
+	}
+	if typ != cloneTimeType && typ.Implements(cloneCustomType) {
+		plan.deep = true
+		plan.custom = true
+	}
+
+	actual, _ := clonePlanCache.LoadOrStore(typ, plan)
+	return actual.(*clonePlan)
+}
+
+// cloneVisitKey 是已拷贝指针的键，结构体指针与指向其首个字段的指针地址相同，故需同时区分类型。
+type cloneVisitKey struct {
+	addr unsafe.Pointer
+	typ  reflect.Type
+}
+
+// cloneContext 定义了单次深度拷贝的上下文，用于处理循环引用。
+type cloneContext struct {
+	visited map[cloneVisitKey]reflect.Value // 已拷贝的指针
+}
+
+// deepCopy 对已完成浅拷贝的值进行深度拷贝，将其中的引用类型替换为新的副本。
+// value 必须是可寻址的值。
+func (ctx *cloneContext) deepCopy(value reflect.Value) {
+	plan := getClonePlan(value.Type())
+	if !plan.deep {
+		return
+	}
+
+	if plan.custom {
+		switch value.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			if value.IsNil() {
+				return
+			}
+		}
+		if ncopy := reflect.ValueOf(value.Interface().(IDeepCopy).DeepCopy()); ncopy.IsValid() && ncopy.Type() == value.Type() {
+			value.Set(ncopy)
+			return
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return
+		}
+		key := cloneVisitKey{addr: value.UnsafePointer(), typ: value.Type()}
+		if ctx.visited == nil {
+			ctx.visited = make(map[cloneVisitKey]reflect.Value)
+		} else if nvalue, ok := ctx.visited[key]; ok {
+			value.Set(nvalue)
+			return
+		}
+		nvalue := reflect.New(value.Type().Elem())
+		ctx.visited[key] = nvalue
+		nvalue.Elem().Set(value.Elem())
+		ctx.deepCopy(nvalue.Elem())
+		value.Set(nvalue)
+	case reflect.Slice:
+		if value.IsNil() {
+			return
+		}
+		nvalue := reflect.MakeSlice(value.Type(), value.Len(), value.Cap())
+		reflect.Copy(nvalue, value)
+		if getClonePlan(value.Type().Elem()).deep {
+			for i := 0; i < nvalue.Len(); i++ {
+				ctx.deepCopy(nvalue.Index(i))
+			}
+		}
+		value.Set(nvalue)
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			ctx.deepCopy(value.Index(i))
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			return
+		}
+		nvalue := reflect.MakeMapWithSize(value.Type(), value.Len())
+		deep := getClonePlan(value.Type().Elem()).deep
+		iter := value.MapRange()
+		for iter.Next() {
+			if deep {
+				elem := reflect.New(value.Type().Elem()).Elem()
+				elem.Set(iter.Value())
+				ctx.deepCopy(elem)
+				nvalue.SetMapIndex(iter.Key(), elem)
+			} else {
+				nvalue.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+		value.Set(nvalue)
+	case reflect.Interface:
+		if value.IsNil() {
+			return
+		}
+		elem := value.Elem()
+		if getClonePlan(elem.Type()).deep {
+			nelem := reflect.New(elem.Type()).Elem()
+			nelem.Set(elem)
+			ctx.deepCopy(nelem)
+			value.Set(nelem)
+		}
+	case reflect.Struct:
+		for _, index := range plan.fields {
+			field := value.Field(index)
+			if !field.CanSet() { // 未导出的字段需要通过地址访问
+				field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+			}
+			ctx.deepCopy(field)
+		}
+	}
+}
+
+// deepClone 将 src 深度拷贝至 dst，dst 和 src 必须是指向相同类型的指针。
+func deepClone[T any](dst, src *T) {
+	*dst = *src
+	ctx := cloneContext{}
+	ctx.deepCopy(reflect.ValueOf(dst).Elem())
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eframework-org/GO.UTIL/XObject"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCloneNode struct {
+	Value int
+	Next  *testCloneNode
+}
+
+type testCloneCustom struct {
+	Values []int
+	copied bool
+}
+
+func (c testCloneCustom) DeepCopy() any {
+	return testCloneCustom{Values: append([]int{}, c.Values...), copied: true}
+}
+
+type testCloneItem struct {
+	Name string
+	Tags []string
+}
+
+// TestCloneModel 是深度拷贝的测试模型。
+type TestCloneModel struct {
+	Model[TestCloneModel] `orm:"-" json:"-"`
+	ID                    int                       `orm:"column(id);pk"`
+	Items                 []testCloneItem           `orm:"-"`
+	Attrs                 map[string][]int          `orm:"-"`
+	Ptr                   *testCloneItem            `orm:"-"`
+	Any                   any                       `orm:"-"`
+	Array                 [2][]int                  `orm:"-"`
+	Time                  time.Time                 `orm:"-"`
+	Node                  *testCloneNode            `orm:"-"`
+	Custom                testCloneCustom           `orm:"-"`
+	Nested                map[string]*testCloneItem `orm:"-"`
+	hidden                []int
+}
+
+func (m *TestCloneModel) AliasName() string { return "clone_alias" }
+
+func (m *TestCloneModel) TableName() string { return "clone_table" }
+
+func TestModelClone(t *testing.T) {
+	t.Run("Reference", func(t *testing.T) {
+		src := XObject.New[TestCloneModel]()
+		src.ID = 1
+		src.Items = []testCloneItem{{Name: "a", Tags: []string{"x"}}}
+		src.Attrs = map[string][]int{"k": {1, 2}}
+		src.Ptr = &testCloneItem{Name: "p", Tags: []string{"y"}}
+		src.Any = []int{1}
+		src.Array = [2][]int{{1}, {2}}
+		src.Time = time.Now()
+		src.Custom = testCloneCustom{Values: []int{1}}
+		src.Nested = map[string]*testCloneItem{"n": {Name: "n"}}
+		src.hidden = []int{1}
+
+		dst := src.Clone().(*TestCloneModel)
+		assert.Equal(t, src.ID, dst.ID, "拷贝的值类型字段应当相等。")
+		assert.Equal(t, src.Items, dst.Items, "拷贝的切片内容应当相等。")
+		assert.True(t, src.Time.Equal(dst.Time), "拷贝的时间字段应当相等。")
+		assert.True(t, dst.IsValid(), "拷贝的对象应当有效。")
+
+		dst.Items[0].Name = "b"
+		dst.Items[0].Tags[0] = "z"
+		dst.Attrs["k"][0] = 100
+		dst.Attrs["v"] = []int{3}
+		dst.Ptr.Tags[0] = "w"
+		dst.Any.([]int)[0] = 100
+		dst.Array[0][0] = 100
+		dst.Custom.Values[0] = 100
+		dst.Nested["n"].Name = "m"
+		dst.hidden[0] = 100
+
+		assert.Equal(t, "a", src.Items[0].Name, "修改拷贝的切片元素不应当影响原对象。")
+		assert.Equal(t, "x", src.Items[0].Tags[0], "修改拷贝的嵌套切片不应当影响原对象。")
+		assert.Equal(t, 1, src.Attrs["k"][0], "修改拷贝的字典值不应当影响原对象。")
+		assert.Equal(t, 1, len(src.Attrs), "修改拷贝的字典不应当影响原对象。")
+		assert.Equal(t, "y", src.Ptr.Tags[0], "修改拷贝的指针字段不应当影响原对象。")
+		assert.Equal(t, 1, src.Any.([]int)[0], "修改拷贝的接口字段不应当影响原对象。")
+		assert.Equal(t, 1, src.Array[0][0], "修改拷贝的数组元素不应当影响原对象。")
+		assert.Equal(t, 1, src.Custom.Values[0], "修改拷贝的自定义字段不应当影响原对象。")
+		assert.True(t, dst.Custom.copied, "实现了 IDeepCopy 的字段应当使用自定义拷贝。")
+		assert.Equal(t, "n", src.Nested["n"].Name, "修改拷贝的字典指针值不应当影响原对象。")
+		assert.Equal(t, 1, src.hidden[0], "修改拷贝的未导出字段不应当影响原对象。")
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		src := XObject.New[TestCloneModel]()
+		dst := src.Clone().(*TestCloneModel)
+		assert.Nil(t, dst.Items, "空切片拷贝后应当为空。")
+		assert.Nil(t, dst.Attrs, "空字典拷贝后应当为空。")
+		assert.Nil(t, dst.Ptr, "空指针拷贝后应当为空。")
+		assert.Nil(t, dst.Any, "空接口拷贝后应当为空。")
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		src := XObject.New[TestCloneModel]()
+		src.Node = &testCloneNode{Value: 1}
+		src.Node.Next = &testCloneNode{Value: 2, Next: src.Node}
+
+		dst := src.Clone().(*TestCloneModel)
+		assert.NotSame(t, src.Node, dst.Node, "循环引用的指针应当被拷贝。")
+		assert.Same(t, dst.Node, dst.Node.Next.Next, "循环引用的拓扑结构应当被保留。")
+		dst.Node.Next.Value = 3
+		assert.Equal(t, 2, src.Node.Next.Value, "修改拷贝的循环引用不应当影响原对象。")
+	})
+
+	t.Run("FieldPointer", func(t *testing.T) {
+		src := XObject.New[TestCloneModel]()
+		src.Ptr = &testCloneItem{Name: "a"}
+		src.Any = &src.Ptr.Name // 与结构体指针地址相同的首个字段指针
+
+		var dst *TestCloneModel
+		assert.NotPanics(t, func() { dst = src.Clone().(*TestCloneModel) }, "地址相同但类型不同的指针不应当导致拷贝失败。")
+		name, ok := dst.Any.(*string)
+		assert.True(t, ok, "字段指针拷贝后的类型应当保持不变。")
+		assert.Equal(t, "a", *name, "字段指针拷贝后的值应当和原对象相等。")
+		assert.NotSame(t, src.Any, dst.Any, "字段指针应当被拷贝。")
+		assert.NotSame(t, src.Ptr, dst.Ptr, "结构体指针应当被拷贝。")
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		src := XObject.New[TestCloneModel]()
+		src.Items = []testCloneItem{{Name: "a"}}
+		done := make(chan struct{})
+		for range 10 {
+			go func() {
+				defer func() { done <- struct{}{} }()
+				dst := src.Clone().(*TestCloneModel)
+				dst.Items[0].Name = "b"
+			}()
+		}
+		for range 10 {
+			<-done
+		}
+		assert.Equal(t, "a", src.Items[0].Name, "并发拷贝不应当影响原对象。")
+	})
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/petermattis/goid"
+)
+
+// columnTagRegex 用于解析结构体标签中的列名，如 orm:"column(name)"。
+var columnTagRegex = regexp.MustCompile(`column\(([^)]+)\)`)
+
+// ListColumns 获取符合条件的记录中指定列的值（投影查询），避免宽表读取所有的列。
+// columns 为列名，可以是字段名、小写字段名或列名；cond 为可选的查询条件，支持分页及排序（orderby）。
+// 返回的每行数据以列名为键，值按字段类型转换为 int64、uint64、float64、bool、string、time.Time 或 nil。
+//
+// 若模型已被全量列举（会话内存或全局内存），则直接从内存中的对象读取，会话内存中的对象优先于全局内存，
+// 已被标记删除的对象将被忽略；否则通过 beego/orm 的 ValuesList 从远端读取，执行查询前会调用模型的 OnQuery("ListColumns", cond)，
+// 远端读取的结果为数据库中的数据，尚未提交的修改不会体现在结果中。
+//
+// 使用示例：
+//
+//	rows, err := XOrm.ListColumns(model, []string{"id", "score"}, XOrm.Cond("orderby = -score && limit = {0}", 10))
+//	for _, row := range rows {
+//		fmt.Println(row["id"], row["score"])
+//	}
+func ListColumns(model IModel, columns []string, cond ...*Condition) ([]orm.Params, error) {
+	if model == nil {
+		return nil, fmt.Errorf("nil model instance")
+	}
+	meta := getModelMeta(model)
+	if meta == nil {
+		return nil, fmt.Errorf("model of %v was not registered", model.TableName())
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("columns was empty")
+	}
+	fields := make([]*beegoFieldInfo, 0, len(columns))
+	for _, column := range columns {
+		field := meta.field(column)
+		if field == nil {
+			return nil, fmt.Errorf("unknown field/column name `%v` of %v", column, meta.table)
+		}
+		fields = append(fields, field)
+	}
+	var ncond *Condition
+	if len(cond) > 0 {
+		ncond = cond[0]
+	}
+	if err := ncond.checkOrders(meta); err != nil {
+		return nil, err
+	}
+
+	cacheDumpWait.Wait()
+	if objs, ok := listCachedObjects(model, meta, ncond); ok { // 内存读取
+		rets := make([]orm.Params, 0, len(objs))
+		for _, obj := range objs {
+			row := make(orm.Params, len(fields))
+			for _, field := range fields {
+				row[field.column] = columnValue(obj.DataValue(field.name))
+			}
+			rets = append(rets, row)
+		}
+		return rets, nil
+	}
+
+	// 远端读取
+	globalWait("XOrm.ListColumns", model)
+	return listRemoteColumns(model, meta, fields, ncond)
+}
+
+// ListColumnsAs 获取符合条件的记录中 R 类型所需列的值，并转换为 R 类型的结构体。
+// R 的导出字段按照 orm 标签中的 column(...) 或字段名与模型的列对应，标签为 orm:"-" 的字段将被忽略，
+// 字段可以是模型字段对应的类型、可转换的数值类型或其指针类型。cond 为可选的查询条件，读取规则与 ListColumns 相同。
+//
+// 使用示例：
+//
+//	type RankItem struct {
+//		ID    int     `orm:"column(id)"`
+//		Score float64 `orm:"column(score)"`
+//	}
+//	items, err := XOrm.ListColumnsAs[RankItem](model, XOrm.Cond("orderby = -score && limit = {0}", 10))
+func ListColumnsAs[R any](model IModel, cond ...*Condition) ([]R, error) {
+	rtype := reflect.TypeFor[R]()
+	if rtype.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("result type %v must be a struct", rtype)
+	}
+	var columns []string
+	var indexes []int
+	for i := 0; i < rtype.NumField(); i++ {
+		sfield := rtype.Field(i)
+		tag := sfield.Tag.Get("orm")
+		if !sfield.IsExported() || tag == "-" {
+			continue
+		}
+		name := sfield.Name
+		if match := columnTagRegex.FindStringSubmatch(tag); len(match) == 2 {
+			name = strings.TrimSpace(match[1])
+		}
+		columns = append(columns, name)
+		indexes = append(indexes, i)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("result type %v has no column field", rtype)
+	}
+
+	rows, err := ListColumns(model, columns, cond...)
+	if err != nil {
+		return nil, err
+	}
+	meta := getModelMeta(model)
+	rets := make([]R, len(rows))
+	for i, row := range rows {
+		rval := reflect.ValueOf(&rets[i]).Elem()
+		for j, column := range columns {
+			field := meta.field(column)
+			if err := assignColumn(rval.Field(indexes[j]), row[field.column]); err != nil {
+				return nil, fmt.Errorf("assign column `%v` to %v.%v failed: %v", field.column, rtype, rtype.Field(indexes[j]).Name, err)
+			}
+		}
+	}
+	return rets, nil
+}
+
+// listCachedObjects 获取内存中符合条件的对象，模型未被全量列举时返回 false。
+// 会话内存中的对象优先于全局内存，结果按照条件的排序规则（未指定时按主键）排序并分页。
+func listCachedObjects(model IModel, meta *modelMeta, cond *Condition) ([]IModel, bool) {
+	var slisted bool
+	gid := goid.Get()
+	if getContext(gid) == nil { // 无会话上下文时仅读取全局内存
+		gid = 0
+	} else {
+		slisted = isSessionListed(gid, model)
+	}
+	glisted := meta.cache && isGlobalListed(model)
+	if !slisted && !glisted {
+		return nil, false
+	}
+
+	objs := make(map[string]IModel)
+	if glisted && !slisted {
+		if gcache := getGlobalCache(model); gcache != nil {
+			gcache.Range(func(key, value any) bool {
+				objs[key.(string)] = value.(IModel)
+				return true
+			})
+		}
+	}
+	if gid != 0 {
+		if scache := getSessionCache(gid, model); scache != nil {
+			scache.Range(func(key, value any) bool {
+				objs[key.(string)] = value.(*sessionObject).ptr
+				return true
+			})
+		}
+	}
+
+	rets := make([]IModel, 0, len(objs))
+	for _, obj := range objs {
+		if obj.IsValid() && obj.Matchs(cond) {
+			rets = append(rets, obj)
+		}
+	}
+
+	var orders []string
+	if cond != nil {
+		orders = cond.orderBy(meta)
+	}
+	if len(orders) == 0 && meta.fields.pk != nil {
+		orders = []string{meta.fields.pk.name}
+	}
+	slices.SortStableFunc(rets, func(a, b IModel) int { return compareOrders(meta, a, b, orders) })
+
+	return paginate(cond, rets), true
+}
+
+// listRemoteColumns 通过 beego/orm 的 ValuesList 从远端读取指定列的值。
+func listRemoteColumns(model IModel, meta *modelMeta, fields []*beegoFieldInfo, cond *Condition) (rets []orm.Params, err error) {
+	defer func() { // beego/orm 在数据库未注册、字段解析或值转换失败时会 panic
+		if r := recover(); r != nil {
+			rets = nil
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	ormer := orm.NewOrmUsingDB(model.AliasName())
+	if ormer == nil {
+		return nil, fmt.Errorf("failed to create orm instance of %v", model.AliasName())
+	}
+
+	query := ormer.QueryTable(model)
+	if cond != nil {
+		query = query.SetCond(model.OnQuery("ListColumns", cond.Base))
+		if cond.Offset > 0 {
+			query = query.Offset(cond.Offset)
+		}
+		if cond.Limit > 0 {
+			query = query.Limit(cond.Limit)
+		}
+		if orders := cond.orderBy(meta); len(orders) > 0 {
+			query = query.OrderBy(orders...)
+		}
+	} else if ncond := model.OnQuery("ListColumns", nil); ncond != nil {
+		query = query.SetCond(ncond)
+	}
+
+	exprs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		exprs = append(exprs, field.name)
+	}
+	var lists []orm.ParamsList
+	if _, err := query.ValuesList(&lists, exprs...); err != nil {
+		return nil, err
+	}
+	rets = make([]orm.Params, 0, len(lists))
+	for _, list := range lists {
+		row := make(orm.Params, len(fields))
+		for i, field := range fields {
+			if i < len(list) {
+				row[field.column] = list[i]
+			} else {
+				row[field.column] = nil
+			}
+		}
+		rets = append(rets, row)
+	}
+	return rets, nil
+}
+
+// columnValue 将对象的字段值转换为与 beego/orm 的 Values 结果一致的类型。
+func columnValue(value any) any {
+	val := reflect.ValueOf(value)
+	for val.IsValid() && val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if !val.IsValid() {
+		return nil
+	}
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return val.Uint()
+	case reflect.Float32, reflect.Float64:
+		return val.Float()
+	case reflect.Bool:
+		return val.Bool()
+	case reflect.String:
+		return val.String()
+	}
+	if t, ok := val.Interface().(time.Time); ok {
+		return t
+	}
+	return val.Interface()
+}
+
+// assignColumn 将列的值赋给结构体字段，空值将赋为零值，指针字段会自动分配。
+func assignColumn(dst reflect.Value, value any) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr {
+		elem := reflect.New(dst.Type().Elem())
+		if err := assignColumn(elem.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+	val := reflect.ValueOf(value)
+	switch {
+	case val.Type().AssignableTo(dst.Type()):
+		dst.Set(val)
+	case dst.Kind() == reflect.String && val.Kind() != reflect.String: // 避免整型转换为字符
+		return fmt.Errorf("cannot convert %T to %v", value, dst.Type())
+	case val.Type().ConvertibleTo(dst.Type()):
+		dst.Set(val.Convert(dst.Type()))
+	default:
+		return fmt.Errorf("cannot convert %T to %v", value, dst.Type())
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.UTIL/XObject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelColumn(t *testing.T) {
+	t.Run("Cache", func(t *testing.T) {
+		defer orm.ResetModelCache()
+		orm.ResetModelCache()
+		model := XObject.New[TestSQLModel]()
+		Meta(model, true, true)
+		defer globalCacheMap.Delete(model.ModelUnique())
+		defer globalListMap.Delete(model.ModelUnique())
+
+		birth := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		var objs []*TestSQLModel
+		for i := 1; i <= 5; i++ {
+			obj := XObject.New[TestSQLModel]()
+			obj.ID = i
+			obj.Name = fmt.Sprintf("name_%d", i)
+			obj.Score = float64(i%3) + 0.5
+			obj.Birth = birth.AddDate(0, 0, i)
+			obj.IsValid(true)
+			setGlobalCache(obj)
+			objs = append(objs, obj)
+		}
+
+		_, ok := listCachedObjects(model, getModelMeta(model), nil)
+		assert.False(t, ok, "模型未被全量列举时不应当读取内存。")
+		isGlobalListed(model, true)
+
+		rows, err := ListColumns(model, []string{"id", "Score"}, Cond("score > {0} && orderby = -score", 1))
+		assert.NoError(t, err, "ListColumns 不应当返回错误。")
+		assert.Equal(t, []orm.Params{
+			{"id": int64(2), "score": 2.5},
+			{"id": int64(5), "score": 2.5},
+			{"id": int64(1), "score": 1.5},
+			{"id": int64(4), "score": 1.5},
+		}, rows, "内存读取的结果应当按照排序规则排序，并以列名为键。")
+
+		rows, err = ListColumns(model, []string{"name"}, Cond("limit = {0} && offset = {1}", 2, 1))
+		assert.NoError(t, err, "分页的 ListColumns 不应当返回错误。")
+		assert.Equal(t, []orm.Params{{"name": "name_2"}, {"name": "name_3"}}, rows, "内存读取的结果应当按主键排序并分页。")
+
+		objs[2].IsValid(false)
+		rows, err = ListColumns(model, []string{"id"})
+		assert.NoError(t, err, "无条件的 ListColumns 不应当返回错误。")
+		assert.Equal(t, []orm.Params{{"id": int64(1)}, {"id": int64(2)}, {"id": int64(4)}, {"id": int64(5)}}, rows, "已被标记删除的对象应当被忽略。")
+
+		type item struct {
+			ID    int32      `orm:"column(id)"`
+			Score *float32   `orm:"column(score)"`
+			Name  string     // 按字段名对应
+			Birth *time.Time `orm:"column(birth)"`
+			Extra string     `orm:"-"`
+			local int
+		}
+		items, err := ListColumnsAs[item](model, Cond("id == {0}", 1))
+		assert.NoError(t, err, "ListColumnsAs 不应当返回错误。")
+		assert.Equal(t, 1, len(items), "ListColumnsAs 的结果数量应当为 1。")
+		assert.Equal(t, int32(1), items[0].ID, "整型字段应当转换为结构体字段的类型。")
+		assert.Equal(t, float32(1.5), *items[0].Score, "指针字段应当被分配并赋值。")
+		assert.Equal(t, "name_1", items[0].Name, "未指定列名的字段应当按照字段名对应。")
+		assert.Equal(t, birth.AddDate(0, 0, 1), *items[0].Birth, "时间字段应当和预期相等。")
+
+		type invalid struct {
+			Name int `orm:"column(name)"`
+		}
+		_, err = ListColumnsAs[invalid](model)
+		assert.Error(t, err, "类型无法转换时应当返回错误。")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		defer orm.ResetModelCache()
+		orm.ResetModelCache()
+		model := XObject.New[TestSQLModel]()
+
+		Meta(model, false, true)
+		_, err := ListColumns(model, nil)
+		assert.Error(t, err, "列名为空时应当返回错误。")
+
+		_, err = ListColumns(model, []string{"unknown"})
+		assert.Error(t, err, "未知的列名应当返回错误。")
+
+		_, err = ListColumns(model, []string{"id"})
+		assert.Error(t, err, "数据库未注册时应当返回错误。")
+
+		type unknown struct {
+			Level int
+		}
+		_, err = ListColumnsAs[unknown](model)
+		assert.Error(t, err, "结构体字段无法对应列时应当返回错误。")
+
+		_, err = ListColumnsAs[int](model)
+		assert.Error(t, err, "非结构体类型应当返回错误。")
+	})
+
+	SetupBaseTest()
+	defer ResetBaseTest()
+	model := NewTestBaseModel()
+	WriteBaseTest(5)
+
+	t.Run("Remote", func(t *testing.T) {
+		rows, err := ListColumns(model, []string{"id", "float_val", "BoolVal"}, Cond("int_val > {0} && orderby = -int_val && limit = {1}", 2, 2))
+		assert.NoError(t, err, "远端读取的 ListColumns 不应当返回错误。")
+		assert.Equal(t, "ListColumns", model.onQueryParams[0], "OnQuery 的类型应当为 ListColumns。")
+		assert.Equal(t, []orm.Params{
+			{"id": int64(5), "float_val": 5.5, "bool_val": false},
+			{"id": int64(4), "float_val": 4.5, "bool_val": true},
+		}, rows, "远端读取的结果应当和预期相等。")
+
+		type item struct {
+			ID        int    `orm:"column(id)"`
+			StringVal string `orm:"column(string_val)"`
+		}
+		items, err := ListColumnsAs[item](model, Cond("id == {0}", 3))
+		assert.NoError(t, err, "远端读取的 ListColumnsAs 不应当返回错误。")
+		assert.Equal(t, []item{{ID: 3, StringVal: "test_string_3"}}, items, "远端读取的结构体应当和预期相等。")
+	})
+
+	t.Run("OnQuery", func(t *testing.T) {
+		defer func() { model.onQueryFilter = nil }()
+		model.onQueryFilter = orm.NewCondition().And("bool_val", true)
+
+		rows, err := ListColumns(model, []string{"id"})
+		assert.NoError(t, err, "带有 OnQuery 条件的 ListColumns 不应当返回错误。")
+		assert.Equal(t, []orm.Params{{"id": int64(2)}, {"id": int64(4)}}, rows, "远端读取的结果应当受 OnQuery 的条件过滤。")
+
+		rows, err = ListColumns(model, []string{"id"}, Cond("int_val > {0}", 2))
+		assert.NoError(t, err, "带有 OnQuery 条件的 ListColumns 不应当返回错误。")
+		assert.Equal(t, []orm.Params{{"id": int64(4)}}, rows, "OnQuery 的条件应当与查询条件同时生效。")
+	})
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// EachBatch 是 Each 及 Iter 默认的分批读取数量。
+const EachBatch = 1000
+
+// EachOption 定义了遍历的选项。
+type EachOption struct {
+	Batch int  // 每批读取的数量，小于等于 0 时使用 EachBatch
+	Cache bool // 是否将遍历的数据写入全局内存，仅对启用缓存的模型生效，已存在的数据不会被覆盖
+}
+
+// Each 按主键分批遍历符合条件的记录，适用于数据规模较大的离线任务（如统计、迁移及赛季重置）。
+// cond 为可选的查询条件，排序规则仅支持按主键升序（默认）或降序（如 orderby = -id），limit 用于限定遍历的总数量，不支持 offset；
+// fn 为遍历的回调函数，返回 false 时停止遍历；opts 为可选的遍历选项。
+//
+// 与 List 不同，遍历的数据直接从远端读取且不写入会话内存，默认也不写入全局内存，故尚未提交的修改不会体现在遍历的数据中。
+// 每批查询前会调用模型的 OnQuery("Each", cond)，返回遍历过程中发生的错误。
+//
+// 使用示例：
+//
+//	err := XOrm.Each(NewUser(), XOrm.Cond("level > {0}", 10), func(user *User) bool {
+//		fmt.Println(user.ID)
+//		return true
+//	})
+func Each[T IModel](model T, cond *Condition, fn func(T) bool, opts ...EachOption) error {
+	if fn == nil {
+		return fmt.Errorf("nil each function")
+	}
+	return each(model, cond, fn, opts...)
+}
+
+// Iter 返回按主键分批遍历符合条件的记录的迭代器，遍历规则与 Each 相同，每批查询前同样会调用模型的 OnQuery("Each", cond)。
+// 发生错误时迭代器将返回该错误并停止遍历。
+//
+// 使用示例：
+//
+//	for user, err := range XOrm.Iter(NewUser(), nil, XOrm.EachOption{Batch: 500}) {
+//		if err != nil {
+//			break
+//		}
+//		fmt.Println(user.ID)
+//	}
+func Iter[T IModel](model T, cond *Condition, opts ...EachOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if err := each(model, cond, func(obj T) bool { return yield(obj, nil) }, opts...); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// each 按主键分批读取记录并依次调用 fn。
+func each[T IModel](model T, cond *Condition, fn func(T) bool, opts ...EachOption) error {
+	if any(model) == nil {
+		return fmt.Errorf("nil model instance")
+	}
+	meta := getModelMeta(model)
+	if meta == nil {
+		return fmt.Errorf("model of %v was not registered", model.TableName())
+	}
+	pk := meta.fields.pk
+	if pk == nil {
+		return fmt.Errorf("primary key of %v was not found", meta.table)
+	}
+
+	var opt EachOption
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Batch <= 0 {
+		opt.Batch = EachBatch
+	}
+
+	var base *orm.Condition
+	limit := 0
+	desc := false
+	if cond != nil {
+		if cond.Offset > 0 {
+			return fmt.Errorf("offset is not supported by each of %v", meta.table)
+		}
+		for i, order := range cond.Orders {
+			field := meta.field(strings.TrimPrefix(order, "-"))
+			if i > 0 || field != pk {
+				return fmt.Errorf("order `%v` is not supported by each of %v, only primary key is allowed", strings.Join(cond.Orders, ","), meta.table)
+			}
+			desc = strings.HasPrefix(order, "-")
+		}
+		base = cond.Base
+		limit = cond.Limit
+	}
+
+	var ormer orm.Ormer
+	if err := eachSafe(func() error {
+		if ormer = orm.NewOrmUsingDB(model.AliasName()); ormer == nil {
+			return fmt.Errorf("failed to create orm instance of %v", model.AliasName())
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	order, operator := pk.name, "__gt"
+	if desc {
+		order, operator = "-"+pk.name, "__lt"
+	}
+
+	var last any
+	count := 0
+	for {
+		batch := opt.Batch
+		if limit > 0 {
+			batch = min(batch, limit-count)
+		}
+		ncond := orm.NewCondition()
+		if qcond := model.OnQuery("Each", base); qcond != nil && !qcond.IsEmpty() {
+			ncond = ncond.AndCond(qcond)
+		}
+		if last != nil {
+			ncond = ncond.And(pk.column+operator, last)
+		}
+
+		var rets []T
+		if err := eachSafe(func() error {
+			_, err := ormer.QueryTable(model).SetCond(ncond).OrderBy(order).Limit(batch).All(&rets)
+			return err
+		}); err != nil {
+			if err == orm.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+		for _, obj := range rets {
+			obj.Ctor(obj)
+			obj.OnDecode()
+			obj.IsValid(true)
+		}
+		if len(rets) > 0 { // 在回调前记录游标，避免回调修改主键
+			last = rets[len(rets)-1].DataValue(pk.name)
+		}
+		for _, obj := range rets {
+			if opt.Cache && meta.cache {
+				if gcache := getGlobalCache(obj); gcache == nil {
+					setGlobalCache(obj.Clone())
+				} else if _, ok := gcache.Load(obj.DataUnique()); !ok {
+					setGlobalCache(obj.Clone())
+				}
+			}
+			count++
+			if !fn(obj) {
+				return nil
+			}
+		}
+		if len(rets) < batch || (limit > 0 && count >= limit) {
+			return nil
+		}
+	}
+}
+
+// eachSafe 执行 beego/orm 的操作，将其 panic（如数据库未注册）转换为错误，不影响回调函数中的 panic。
+func eachSafe(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return fn()
+}
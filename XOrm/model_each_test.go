@@ -0,0 +1,110 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"testing"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.UTIL/XObject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelEach(t *testing.T) {
+	t.Run("Error", func(t *testing.T) {
+		defer orm.ResetModelCache()
+		orm.ResetModelCache()
+		model := XObject.New[TestSQLModel]()
+		Meta(model, false, true)
+		fn := func(*TestSQLModel) bool { return true }
+
+		assert.Error(t, Each(model, nil, nil), "回调函数为空时应当返回错误。")
+		assert.Error(t, Each(model, Cond("offset = {0}", 1), fn), "不支持 offset。")
+		assert.Error(t, Each(model, Cond("orderby = name"), fn), "不支持按非主键排序。")
+		assert.Error(t, Each(model, Cond("orderby = id,name"), fn), "不支持多个排序规则。")
+		assert.Error(t, Each(model, nil, fn), "数据库未注册时应当返回错误。")
+	})
+
+	SetupBaseTest()
+	defer ResetBaseTest()
+	model := NewTestBaseModel()
+	WriteBaseTest(5)
+
+	collect := func(cond *Condition, opts ...EachOption) []int {
+		var ids []int
+		err := Each(model, cond, func(obj *TestBaseModel) bool {
+			ids = append(ids, obj.ID)
+			return true
+		}, opts...)
+		assert.NoError(t, err, "Each 不应当返回错误。")
+		return ids
+	}
+
+	t.Run("Each", func(t *testing.T) {
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, collect(nil, EachOption{Batch: 2}), "分批遍历的结果应当按主键升序排列。")
+		assert.Equal(t, "Each", model.onQueryParams[0], "OnQuery 的类型应当为 Each。")
+		assert.Equal(t, []int{2, 4}, collect(Cond("bool_val == {0}", true), EachOption{Batch: 1}), "带条件的遍历结果应当和预期相等。")
+		assert.Equal(t, []int{5, 4, 3}, collect(Cond("orderby = -id && limit = {0}", 3), EachOption{Batch: 2}), "降序遍历应当受 limit 限定。")
+		assert.Empty(t, collect(Cond("int_val > {0}", 10)), "无匹配记录时不应当调用回调函数。")
+
+		var ids []int
+		assert.NoError(t, Each(model, nil, func(obj *TestBaseModel) bool {
+			ids = append(ids, obj.ID)
+			return len(ids) < 3
+		}, EachOption{Batch: 2}), "提前停止的 Each 不应当返回错误。")
+		assert.Equal(t, []int{1, 2, 3}, ids, "回调函数返回 false 时应当停止遍历。")
+	})
+
+	t.Run("Iter", func(t *testing.T) {
+		var ids []int
+		for obj, err := range Iter(model, Cond("int_val >= {0}", 2), EachOption{Batch: 3}) {
+			assert.NoError(t, err, "Iter 不应当返回错误。")
+			ids = append(ids, obj.ID)
+			if len(ids) == 3 {
+				break
+			}
+		}
+		assert.Equal(t, []int{2, 3, 4}, ids, "迭代的结果应当和预期相等。")
+
+		for _, err := range Iter(model, Cond("offset = {0}", 1)) {
+			assert.Error(t, err, "迭代发生错误时应当返回错误。")
+		}
+	})
+
+	t.Run("OnQuery", func(t *testing.T) {
+		defer func() { model.onQueryFilter = nil }()
+		model.onQueryFilter = orm.NewCondition().And("bool_val", true)
+
+		assert.Equal(t, []int{2, 4}, collect(nil, EachOption{Batch: 1}), "分批遍历的结果应当受 OnQuery 的条件过滤。")
+		assert.Equal(t, []int{4}, collect(Cond("int_val > {0}", 2)), "OnQuery 的条件应当与查询条件同时生效。")
+
+		var ids []int
+		for obj, err := range Iter(model, nil, EachOption{Batch: 1}) {
+			assert.NoError(t, err, "Iter 不应当返回错误。")
+			ids = append(ids, obj.ID)
+		}
+		assert.Equal(t, []int{2, 4}, ids, "迭代的结果应当受 OnQuery 的条件过滤。")
+		assert.Equal(t, "Each", model.onQueryParams[0], "Iter 的 OnQuery 类型应当为 Each。")
+	})
+
+	t.Run("Cache", func(t *testing.T) {
+		defer globalCacheMap.Delete(model.ModelUnique())
+		globalCacheMap.Delete(model.ModelUnique())
+
+		collect(nil)
+		assert.Nil(t, getGlobalCache(model), "默认不应当写入全局内存。")
+
+		collect(Cond("int_val <= {0}", 2), EachOption{Cache: true})
+		gcache := getGlobalCache(model)
+		assert.NotNil(t, gcache, "指定 Cache 时应当写入全局内存。")
+		count := 0
+		gcache.Range(func(key, value any) bool {
+			count++
+			return true
+		})
+		assert.Equal(t, 2, count, "全局内存中的数据数量应当为 2。")
+		assert.False(t, isGlobalListed(model), "遍历不应当标记为全量列举。")
+	})
+}
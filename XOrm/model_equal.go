@@ -0,0 +1,246 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// equalFunc 定义了两个相同类型的值的比较函数。
+type equalFunc func(ctx *equalContext, a, b reflect.Value) bool
+
+// equalVisit 是已比较的引用对的键，包含两个值的地址及类型。
+type equalVisit struct {
+	a   unsafe.Pointer
+	b   unsafe.Pointer
+	typ reflect.Type
+}
+
+// equalContext 定义了单次比较的上下文，用于处理循环引用。
+type equalContext struct {
+	visited map[equalVisit]struct{} // 正在或已经比较的引用对
+}
+
+// visit 记录引用对，若已记录则返回 true，此时视为相等（与 reflect.DeepEqual 的处理一致）。
+func (ctx *equalContext) visit(a, b reflect.Value, typ reflect.Type) bool {
+	key := equalVisit{a: a.UnsafePointer(), b: b.UnsafePointer(), typ: typ}
+	if ctx.visited == nil {
+		ctx.visited = make(map[equalVisit]struct{})
+	} else if _, ok := ctx.visited[key]; ok {
+		return true
+	}
+	ctx.visited[key] = struct{}{}
+	return false
+}
+
+// equalField 定义了模型字段的比较计划。
+type equalField struct {
+	index []int     // 字段索引
+	equal equalFunc // 比较函数
+}
+
+var (
+	// equalTypeCache 存储类型的比较函数，键为 reflect.Type，值为 equalFunc。
+	equalTypeCache sync.Map
+
+	// equalPlanCache 存储模型的字段比较计划，键为模型的 reflect.Type，值为 []equalField。
+	equalPlanCache sync.Map
+
+	// equalCustomMap 存储自定义的比较函数，键为 reflect.Type，值为 equalFunc。
+	equalCustomMap sync.Map
+
+	// equalTimeType 是 time.Time 的类型。
+	equalTimeType = reflect.TypeOf(time.Time{})
+)
+
+// RegisterComparer 注册指定类型的字段比较函数。
+// fn 为比较函数，返回两个值是否相等。
+// 注册后 Equals 比较该类型的字段（包括嵌套在切片、字典、指针及结构体中的值）时将使用此函数，
+// 已缓存的比较计划会被清除并在下次比较时重新生成。
+//
+// 使用示例：
+//
+//	// 浮点数按精度比较
+//	XOrm.RegisterComparer(func(a, b float64) bool { return math.Abs(a-b) < 1e-6 })
+func RegisterComparer[T any](fn func(a, b T) bool) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if fn == nil {
+		equalCustomMap.Delete(typ)
+	} else {
+		equalCustomMap.Store(typ, equalFunc(func(ctx *equalContext, a, b reflect.Value) bool {
+			av, aok := valueInterface(a)
+			bv, bok := valueInterface(b)
+			if !aok || !bok {
+				return false
+			}
+			return fn(av.(T), bv.(T))
+		}))
+	}
+	equalTypeCache.Clear()
+	equalPlanCache.Clear()
+}
+
+// getEqualPlan 获取模型的字段比较计划。
+// 计划按模型类型缓存，仅包含数据库字段。
+func getEqualPlan(meta *modelMeta, typ reflect.Type) []equalField {
+	if tmp, ok := equalPlanCache.Load(typ); ok {
+		return tmp.([]equalField)
+	}
+
+	plan := make([]equalField, 0, len(meta.fields.fieldsDB))
+	for _, field := range meta.fields.fieldsDB {
+		sf := typ.FieldByIndex(field.fieldIndex)
+		plan = append(plan, equalField{index: field.fieldIndex, equal: getEqualFunc(sf.Type)})
+	}
+
+	actual, _ := equalPlanCache.LoadOrStore(typ, plan)
+	return actual.([]equalField)
+}
+
+// getEqualFunc 获取指定类型的比较函数。
+// 引用类型的元素比较函数在调用时获取，故递归类型无需递归构建。
+func getEqualFunc(typ reflect.Type) equalFunc {
+	if tmp, ok := equalTypeCache.Load(typ); ok {
+		return tmp.(equalFunc)
+	}
+
+	var fn equalFunc
+	if tmp, ok := equalCustomMap.Load(typ); ok {
+		fn = tmp.(equalFunc)
+	} else if typ == equalTimeType {
+		fn = equalTime
+	} else {
+		switch typ.Kind() {
+		case reflect.Bool:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool { return a.Bool() == b.Bool() }
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool { return a.Int() == b.Int() }
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool { return a.Uint() == b.Uint() }
+		case reflect.Float32, reflect.Float64:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool { return equalFloat(a.Float(), b.Float()) }
+		case reflect.Complex64, reflect.Complex128:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool {
+				ac, bc := a.Complex(), b.Complex()
+				return equalFloat(real(ac), real(bc)) && equalFloat(imag(ac), imag(bc))
+			}
+		case reflect.String:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool { return a.String() == b.String() }
+		case reflect.Ptr:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool {
+				if a.IsNil() || b.IsNil() {
+					return a.IsNil() == b.IsNil()
+				}
+				if a.Pointer() == b.Pointer() || ctx.visit(a, b, typ) {
+					return true
+				}
+				return getEqualFunc(typ.Elem())(ctx, a.Elem(), b.Elem())
+			}
+		case reflect.Interface:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool {
+				if a.IsNil() || b.IsNil() {
+					return a.IsNil() == b.IsNil()
+				}
+				ae, be := a.Elem(), b.Elem()
+				if ae.Type() != be.Type() {
+					return false
+				}
+				return getEqualFunc(ae.Type())(ctx, ae, be)
+			}
+		case reflect.Slice:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool {
+				if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+					return false
+				}
+				if a.Len() == 0 || a.Pointer() == b.Pointer() || ctx.visit(a, b, typ) {
+					return true
+				}
+				efn := getEqualFunc(typ.Elem())
+				for i := 0; i < a.Len(); i++ {
+					if !efn(ctx, a.Index(i), b.Index(i)) {
+						return false
+					}
+				}
+				return true
+			}
+		case reflect.Array:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool {
+				efn := getEqualFunc(typ.Elem())
+				for i := 0; i < a.Len(); i++ {
+					if !efn(ctx, a.Index(i), b.Index(i)) {
+						return false
+					}
+				}
+				return true
+			}
+		case reflect.Map:
+			fn = func(ctx *equalContext, a, b reflect.Value) bool {
+				if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+					return false
+				}
+				if a.Len() == 0 || a.Pointer() == b.Pointer() || ctx.visit(a, b, typ) {
+					return true
+				}
+				efn := getEqualFunc(typ.Elem())
+				iter := a.MapRange()
+				for iter.Next() {
+					bv := b.MapIndex(iter.Key())
+					if !bv.IsValid() || !efn(ctx, iter.Value(), bv) {
+						return false
+					}
+				}
+				return true
+			}
+		case reflect.Struct:
+			fields := make([]equalFunc, typ.NumField())
+			for i := range fields {
+				fields[i] = getEqualFunc(typ.Field(i).Type)
+			}
+			fn = func(ctx *equalContext, a, b reflect.Value) bool {
+				for i, efn := range fields {
+					if !efn(ctx, a.Field(i), b.Field(i)) {
+						return false
+					}
+				}
+				return true
+			}
+		default: // Func、Chan、UnsafePointer 按地址比较
+			fn = func(ctx *equalContext, a, b reflect.Value) bool { return a.Pointer() == b.Pointer() }
+		}
+	}
+
+	actual, _ := equalTypeCache.LoadOrStore(typ, fn)
+	return actual.(equalFunc)
+}
+
+// equalTime 比较两个时间是否表示同一时刻，忽略时区及单调时钟的差异。
+func equalTime(ctx *equalContext, a, b reflect.Value) bool {
+	av, aok := valueInterface(a)
+	bv, bok := valueInterface(b)
+	if !aok || !bok { // 无法访问的时间值按字段比较
+		return a.Field(0).Uint() == b.Field(0).Uint() && a.Field(1).Int() == b.Field(1).Int() && a.Field(2).Pointer() == b.Field(2).Pointer()
+	}
+	return av.(time.Time).Equal(bv.(time.Time))
+}
+
+// equalFloat 比较两个浮点数是否相等，NaN 视为相等以避免重复提交。
+func equalFloat(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+// valueInterface 获取反射值的接口值，对于未导出的可寻址字段会通过地址访问。
+func valueInterface(value reflect.Value) (any, bool) {
+	if value.CanInterface() {
+		return value.Interface(), true
+	}
+	if value.CanAddr() {
+		return reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem().Interface(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.UTIL/XObject"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEqualModel 是比较操作的测试模型。
+type TestEqualModel struct {
+	Model[TestEqualModel] `orm:"-" json:"-"`
+	ID                    int       `orm:"column(id);pk"`
+	Name                  string    `orm:"column(name)"`
+	Score                 float64   `orm:"column(score)"`
+	Level                 *int      `orm:"column(level);null"`
+	Time                  time.Time `orm:"column(time)"`
+	Extra                 []int     `orm:"-"` // 非数据库字段不参与比较
+}
+
+func (m *TestEqualModel) AliasName() string { return "equal_alias" }
+
+func (m *TestEqualModel) TableName() string { return "equal_table" }
+
+func TestModelEquals(t *testing.T) {
+	defer orm.ResetModelCache()
+	orm.ResetModelCache()
+	Meta(XObject.New[TestEqualModel](), false, true)
+
+	newModel := func() *TestEqualModel {
+		level := 10
+		model := XObject.New[TestEqualModel]()
+		model.ID = 1
+		model.Name = "test"
+		model.Score = 1.5
+		model.Level = &level
+		model.Time = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		return model
+	}
+
+	t.Run("Pointer", func(t *testing.T) {
+		model1 := newModel()
+		model2 := newModel()
+		assert.NotSame(t, model1.Level, model2.Level)
+		assert.True(t, model1.Equals(model2), "指针字段应当按指向的值比较。")
+
+		*model2.Level = 11
+		assert.False(t, model1.Equals(model2), "指针字段指向的值不同时应当不相等。")
+
+		model2.Level = nil
+		assert.False(t, model1.Equals(model2), "空指针与非空指针应当不相等。")
+
+		model1.Level = nil
+		assert.True(t, model1.Equals(model2), "两个空指针应当相等。")
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		model1 := newModel()
+		model2 := newModel()
+		model2.Time = model1.Time.In(time.FixedZone("UTC+8", 8*3600))
+		assert.True(t, model1.Equals(model2), "相同时刻不同时区的时间应当相等。")
+
+		model1.Time = time.Now()
+		model2.Time = model1.Time.Round(0) // 移除单调时钟
+		assert.True(t, model1.Equals(model2), "相同时刻的时间不应当受单调时钟影响。")
+
+		model2.Time = model1.Time.Add(time.Nanosecond)
+		assert.False(t, model1.Equals(model2), "不同时刻的时间应当不相等。")
+	})
+
+	t.Run("Float", func(t *testing.T) {
+		model1 := newModel()
+		model2 := newModel()
+		model1.Score = math.NaN()
+		model2.Score = math.NaN()
+		assert.True(t, model1.Equals(model2), "NaN 应当视为相等。")
+	})
+
+	t.Run("Ignore", func(t *testing.T) {
+		model1 := newModel()
+		model2 := newModel()
+		model1.Extra = []int{1}
+		model2.Extra = []int{2}
+		assert.True(t, model1.Equals(model2), "非数据库字段不应当参与比较。")
+	})
+
+	t.Run("RegisterComparer", func(t *testing.T) {
+		defer RegisterComparer[string](nil)
+		RegisterComparer(func(a, b string) bool { return strings.EqualFold(a, b) })
+
+		model1 := newModel()
+		model2 := newModel()
+		model2.Name = "TEST"
+		assert.True(t, model1.Equals(model2), "应当使用注册的比较函数。")
+
+		RegisterComparer[string](nil)
+		assert.False(t, model1.Equals(model2), "注销比较函数后应当使用默认的比较函数。")
+	})
+
+	t.Run("Uncomparable", func(t *testing.T) {
+		type item struct {
+			Tags  []string
+			Attrs map[string]any
+			Time  *time.Time
+		}
+		now := time.Now()
+		a := item{Tags: []string{"a"}, Attrs: map[string]any{"k": []int{1}}, Time: &now}
+		b := item{Tags: []string{"a"}, Attrs: map[string]any{"k": []int{1}}, Time: func() *time.Time { t := now.UTC(); return &t }()}
+
+		fn := getEqualFunc(reflect.TypeOf(a))
+		assert.True(t, fn(&equalContext{}, reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem()), "不可比较的类型应当按元素递归比较。")
+
+		b.Attrs["k"] = []int{2}
+		assert.False(t, fn(&equalContext{}, reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem()), "嵌套元素不同时应当不相等。")
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		type node struct {
+			Value int
+			Next  *node
+			Attrs map[string]any
+		}
+		a := &node{Value: 1}
+		a.Next = a
+		b := &node{Value: 1}
+		b.Next = b
+
+		fn := getEqualFunc(reflect.TypeOf(a))
+		assert.True(t, fn(&equalContext{}, reflect.ValueOf(a), reflect.ValueOf(b)), "结构相同的循环引用应当相等。")
+
+		a.Attrs = map[string]any{}
+		a.Attrs["self"] = a.Attrs
+		b.Attrs = map[string]any{}
+		b.Attrs["self"] = b.Attrs
+		assert.True(t, fn(&equalContext{}, reflect.ValueOf(a), reflect.ValueOf(b)), "结构相同的循环字典应当相等。")
+
+		b.Value = 2
+		assert.False(t, fn(&equalContext{}, reflect.ValueOf(a), reflect.ValueOf(b)), "循环引用中的值不同时应当不相等。")
+	})
+}
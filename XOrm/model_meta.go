@@ -6,6 +6,7 @@ package XOrm
 
 import (
 	"reflect"
+	"strings"
 	"sync"
 	"unsafe"
 
@@ -100,9 +101,10 @@ type beegoFieldInfo struct {
 
 // modelMeta 定义了模型的扩展信息。
 type modelMeta struct {
-	*beegoModelInfo      // 继承 beego/orm 的模型信息
-	cache           bool // 是否缓存
-	writable        bool // 是否可写
+	*beegoModelInfo        // 继承 beego/orm 的模型信息
+	cache           bool   // 是否缓存
+	writable        bool   // 是否可写
+	pairs           string // 二级缓存的 XPairs 别名，为空时不使用二级缓存
 }
 
 // modelMetaCache 存储所有已注册模型的信息。
@@ -121,12 +123,30 @@ func getModelMeta(model IModel) *modelMeta {
 	return nil
 }
 
+// field 获取指定名称的数据库字段信息。
+// name 可以是字段名、小写字段名或列名，与 beego/orm 的查找规则保持一致。
+// 返回字段信息，如果字段不存在或不是数据库字段则返回 nil。
+func (meta *modelMeta) field(name string) *beegoFieldInfo {
+	fields := meta.fields
+	if field, ok := fields.fields[name]; ok && field.dbCol {
+		return field
+	}
+	if field, ok := fields.fieldsLow[strings.ToLower(name)]; ok && field.dbCol {
+		return field
+	}
+	if field, ok := fields.columns[name]; ok && field.dbCol {
+		return field
+	}
+	return nil
+}
+
 // Meta 注册一个模型。
 // model 为模型实例。
 // cache 指定是否缓存。
 // writable 指定是否可写。
+// pairs 为可选的 XPairs 别名，指定后将使用该存储作为全局内存与远端数据之间的二级缓存。
 // 如果模型为 nil 或已注册，将触发 panic。
-func Meta(model IModel, cache bool, writable bool) {
+func Meta(model IModel, cache bool, writable bool, pairs ...string) {
 	if model == nil {
 		XLog.Panic("XOrm.Meta: nil model instance.")
 		return
@@ -142,5 +162,8 @@ func Meta(model IModel, cache bool, writable bool) {
 	id := model.TableName()
 	orm.RegisterModel(model)
 	meta := &modelMeta{beegoModelInfo: beegoModelCache.cache[id], cache: cache, writable: writable}
+	if len(pairs) > 0 {
+		meta.pairs = pairs[0]
+	}
 	modelMetaCache[id] = meta
 }
@@ -40,6 +40,20 @@ func (m *TestModelMeta2) TableName() string {
 	return "mytable2"
 }
 
+type TestModelMeta3 struct {
+	Model[TestModelMeta3]
+	Id   int    `orm:"column(id);pk"`
+	Name string `orm:"column(name)"`
+}
+
+func (m *TestModelMeta3) AliasName() string {
+	return "myalias3"
+}
+
+func (m *TestModelMeta3) TableName() string {
+	return "mytable3"
+}
+
 func TestModelMeta(t *testing.T) {
 	defer orm.ResetModelCache()
 	orm.ResetModelCache()
@@ -60,6 +74,14 @@ func TestModelMeta(t *testing.T) {
 	meta2 := getModelMeta(model2)
 	assert.Equal(t, true, meta2.cache, "注册模型的缓存标识应当和输入的一致。")
 	assert.Equal(t, false, meta2.writable, "注册模型的可写标识应当和输入的一致。")
+	assert.Equal(t, "", meta2.pairs, "未指定二级缓存时别名应当为空。")
+	assert.Equal(t, "", meta1.pairs, "未指定二级缓存时别名应当为空。")
+
+	// 测试指定二级缓存
+	model3 := XObject.New[TestModelMeta3]()
+	Meta(model3, false, true, "Cache")
+	meta3 := getModelMeta(model3)
+	assert.Equal(t, "Cache", meta3.pairs, "注册模型的二级缓存别名应当和输入的一致。")
 
 	// 测试重复注册同一个模型
 	defer func() {
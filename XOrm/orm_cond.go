@@ -5,9 +5,14 @@
 package XOrm
 
 import (
+	"cmp"
+	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 	"unsafe"
 
 	"github.com/beego/beego/v2/client/orm"
@@ -15,11 +20,12 @@ import (
 	"github.com/eframework-org/GO.UTIL/XObject"
 )
 
-// Condition 表示一个查询条件，包含基础条件和分页信息。
+// Condition 表示一个查询条件，包含基础条件、分页信息和排序规则。
 type Condition struct {
 	Base    *orm.Condition // 基础条件
 	Limit   int            // 分页限定
 	Offset  int            // 分页偏移
+	Orders  []string       // 排序规则，字段名或列名，前缀 "-" 表示降序，如 []string{"-exp", "id"}
 	context sync.Map       // 上下文信息
 }
 
@@ -34,6 +40,8 @@ func (c *Condition) Ctor(obj any) {
 // 1. Cond() - 创建空条件
 // 2. Cond(existingCond *orm.Cond) - 从现有条件创建
 // 3. Cond("a > {0} && b == {1}", 1, 2) - 从表达式和参数创建
+// 4. Cond("a > {0} && orderby = -b,c && limit = {1}", 1, 10) - 从表达式创建并指定排序和分页
+// 5. Cond("a > {min} && b == {name}", map[string]any{"min": 1, "name": "x"}) - 从表达式和命名参数创建，参数可以是 map 或结构体
 func Cond(condOrExprAndParams ...any) *Condition {
 	c := XObject.New[Condition]()
 	if len(condOrExprAndParams) == 0 {
@@ -50,10 +58,11 @@ func Cond(condOrExprAndParams ...any) *Condition {
 			return c
 		}
 		params := condOrExprAndParams[1:]
-		cond, limit, offset := exprCondition(expr, params)
+		cond, limit, offset, orders := exprCondition(expr, params)
 		c.Base = cond
 		c.Limit = limit
 		c.Offset = offset
+		c.Orders = orders
 		return c
 	}
 
@@ -61,18 +70,55 @@ func Cond(condOrExprAndParams ...any) *Condition {
 	return nil
 }
 
+// condPanicPrefix 是条件解析错误的 panic 信息前缀，CondE 仅恢复此类 panic。
+const condPanicPrefix = "XOrm.Cond:"
+
+// CondE 创建新的条件，参数与 Cond 相同。
+// 与 Cond 不同，表达式的语法或参数错误不会 panic，而是返回匹配 ErrInvalidCond 的错误；
+// 其他的 panic（如程序缺陷导致的运行时错误）将继续向上抛出。
+func CondE(condOrExprAndParams ...any) (cond *Condition, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			cond = nil
+			err = condError(r)
+		}
+	}()
+	return Cond(condOrExprAndParams...), nil
+}
+
+// condError 将条件解析的 panic 转换为匹配 ErrInvalidCond 的错误，非解析错误的 panic 将重新抛出。
+func condError(r any) error {
+	msg, ok := r.(string)
+	if !ok || !strings.HasPrefix(msg, condPanicPrefix) {
+		panic(r)
+	}
+	return fmt.Errorf("%w: %v", ErrInvalidCond, strings.TrimSpace(strings.TrimPrefix(msg, condPanicPrefix)))
+}
+
 // condOpMap 是条件操作符映射表。
 var condOpMap = map[string]string{
-	">":          "__gt",
-	">=":         "__gte",
-	"<":          "__lt",
-	"<=":         "__lte",
-	"==":         "__exact",
-	"!=":         "__ne",
-	"contains":   "__contains",
-	"startswith": "__startswith",
-	"endswith":   "__endswith",
-	"isnull":     "__isnull",
+	">":           "__gt",
+	">=":          "__gte",
+	"<":           "__lt",
+	"<=":          "__lte",
+	"==":          "__exact",
+	"!=":          "__ne",
+	"contains":    "__contains",
+	"startswith":  "__startswith",
+	"endswith":    "__endswith",
+	"isnull":      "__isnull",
+	"in":          "__in",
+	"notin":       "__in", // 取反的 in，见 condNotOpMap
+	"between":     "__between",
+	"iexact":      "__iexact",
+	"icontains":   "__icontains",
+	"istartswith": "__istartswith",
+	"iendswith":   "__iendswith",
+}
+
+// condNotOpMap 是需要对条件取反的操作符映射表。
+var condNotOpMap = map[string]bool{
+	"notin": true,
 }
 
 const (
@@ -86,6 +132,8 @@ const (
 	exprTokenTypeRBracket        // exprTokenTypeRBracket 是右括号类型。
 	exprTokenTypeLimit           // exprTokenTypeLimit 是限定关键字。
 	exprTokenTypeOffset          // exprTokenTypeOffset 是偏移关键字。
+	exprTokenTypeOrder           // exprTokenTypeOrder 是排序关键字。
+	exprTokenTypeComma           // exprTokenTypeComma 是逗号分隔符。
 )
 
 // exprToken 是表达式词法单元。
@@ -103,6 +151,10 @@ type exprParser struct {
 	cond   *orm.Condition // 条件实例
 	limit  int            // 分页限定
 	offset int            // 分页偏移
+	order  int            // 排序参数
+	orders []string       // 排序规则
+	names  []string       // 命名参数，索引为参数在绑定后的位置
+	index  bool           // 是否使用了位置参数
 }
 
 // exprParserCache 是表达式解析器缓存。
@@ -115,6 +167,10 @@ func (parser *exprParser) parse(expr string, tokens []exprToken) {
 	parser.pos = 0
 	parser.limit = -1
 	parser.offset = -1
+	parser.order = -1
+	parser.orders = nil
+	parser.names = nil
+	parser.index = false
 	parser.cond = parser.condition()
 }
 
@@ -184,7 +240,12 @@ func (parser *exprParser) condition() *orm.Condition {
 
 						// 构建条件
 						fieldWithOp := field + suffix
-						rightCond := orm.NewCondition().And(fieldWithOp, paramIdx)
+						rightCond := orm.NewCondition()
+						if condNotOpMap[operator] {
+							rightCond = rightCond.AndNot(fieldWithOp, paramIdx)
+						} else {
+							rightCond = rightCond.And(fieldWithOp, paramIdx)
+						}
 						cond = cond.AndNotCond(rightCond)
 					} else {
 						XLog.Panic("XOrm.Cond: parameter required after operator, expression: %v", parser.expr)
@@ -199,12 +260,8 @@ func (parser *exprParser) condition() *orm.Condition {
 	} else {
 		// 处理简单表达式
 		if parser.paging() {
-			// 如果是分页参数，直接返回
-			return cond
-		}
-
-		// 解析字段名
-		if parser.current() != nil && parser.current().typ == exprTokenTypeField {
+			// 分页参数，继续处理后续的逻辑操作符
+		} else if parser.current() != nil && parser.current().typ == exprTokenTypeField { // 解析字段名
 			field := parser.current().value
 			parser.next()
 
@@ -224,7 +281,11 @@ func (parser *exprParser) condition() *orm.Condition {
 
 					// 构建条件
 					fieldWithOp := field + suffix
-					cond = cond.And(fieldWithOp, paramIdx)
+					if condNotOpMap[operator] {
+						cond = cond.AndNot(fieldWithOp, paramIdx)
+					} else {
+						cond = cond.And(fieldWithOp, paramIdx)
+					}
 				} else {
 					XLog.Panic("XOrm.Cond: parameter required after operator, expression: %v", parser.expr)
 				}
@@ -293,7 +354,7 @@ func (parser *exprParser) condition() *orm.Condition {
 						// 构建条件
 						fieldWithOp := field + suffix
 						rightCond = orm.NewCondition()
-						if not {
+						if not != condNotOpMap[operator] {
 							rightCond = rightCond.AndNot(fieldWithOp, paramIdx)
 						} else {
 							rightCond = rightCond.And(fieldWithOp, paramIdx)
@@ -385,12 +446,58 @@ func (parser *exprParser) paging() bool {
 		}
 	}
 
+	// 处理 orderby
+	if parser.current() != nil && parser.current().typ == exprTokenTypeOrder {
+		parser.next()
+
+		// 检查是否有等号，必须有等号
+		if parser.current() != nil && parser.current().typ == exprTokenTypeAssign {
+			parser.next()
+		} else {
+			XLog.Panic("XOrm.Cond: assignment operator (=) required after orderby, expression: %v", parser.expr)
+		}
+
+		if parser.current() != nil && parser.current().typ == exprTokenTypeParam {
+			parser.order = parser.param(parser.current().value)
+			parser.next()
+			return true
+		}
+
+		// 解析以逗号分隔的字段列表
+		for {
+			if parser.current() != nil && parser.current().typ == exprTokenTypeField && strings.TrimPrefix(parser.current().value, "-") != "" {
+				parser.orders = append(parser.orders, parser.current().value)
+				parser.next()
+			} else {
+				XLog.Panic("XOrm.Cond: field name or parameter required after orderby, expression: %v", parser.expr)
+			}
+			if parser.current() != nil && parser.current().typ == exprTokenTypeComma {
+				parser.next()
+			} else {
+				return true
+			}
+		}
+	}
+
 	return false
 }
 
 // param 根据传入的索引字符串解析参数的索引。
+// 命名参数（如 {min}）将按照首次出现的顺序分配位置，同一表达式中不能混用位置参数和命名参数。
 func (parser *exprParser) param(index string) int {
 	str := index[1 : len(index)-1] // 去掉花括号
+	if exprParamName(str) {
+		if parser.index {
+			XLog.Panic("XOrm.Cond: named parameter {%s} cannot be mixed with positional parameters, expression: %v", str, parser.expr)
+		}
+		for idx, name := range parser.names {
+			if name == str {
+				return idx
+			}
+		}
+		parser.names = append(parser.names, str)
+		return len(parser.names) - 1
+	}
 	idx, err := strconv.Atoi(str)
 	if err != nil {
 		XLog.Panic("XOrm.Cond: invalid parameter index: %s, expression: %v", str, parser.expr)
@@ -398,11 +505,79 @@ func (parser *exprParser) param(index string) int {
 	if idx < 0 {
 		XLog.Panic("XOrm.Cond: parameter index cannot be negative: %d, expression: %v", idx, parser.expr)
 	}
+	if len(parser.names) > 0 {
+		XLog.Panic("XOrm.Cond: positional parameter {%d} cannot be mixed with named parameters, expression: %v", idx, parser.expr)
+	}
+	parser.index = true
 	return idx
 }
 
-// exprCondition 解析表达式并返回条件实例、分页限定和分页偏移。
-func exprCondition(expr string, params []any) (cond *orm.Condition, limit, offset int) {
+// exprParamName 判断参数是否为命名参数，命名参数由字母、数字及下划线组成且不以数字开头。
+func exprParamName(str string) bool {
+	if str == "" || (str[0] >= '0' && str[0] <= '9') {
+		return false
+	}
+	for _, c := range str {
+		if c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// exprNamedParams 将命名参数绑定为位置参数，params 必须为单个 map（键为字符串）或结构体（及其指针）。
+// 结构体字段按照 cond 标签或字段名（忽略大小写）与参数名对应。
+func exprNamedParams(parser *exprParser, params []any) []any {
+	if len(params) != 1 || params[0] == nil {
+		XLog.Panic("XOrm.Cond: named parameters require a single map or struct argument, expression: %v", parser.expr)
+	}
+	val := reflect.ValueOf(params[0])
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			XLog.Panic("XOrm.Cond: named parameters require a non-nil argument, expression: %v", parser.expr)
+		}
+		val = val.Elem()
+	}
+
+	nparams := make([]any, len(parser.names))
+	switch val.Kind() {
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			XLog.Panic("XOrm.Cond: named parameters map key must be string: %v, expression: %v", val.Type(), parser.expr)
+		}
+		for i, name := range parser.names {
+			value := val.MapIndex(reflect.ValueOf(name).Convert(val.Type().Key()))
+			if !value.IsValid() {
+				XLog.Panic("XOrm.Cond: named parameter {%s} was not found, expression: %v", name, parser.expr)
+			}
+			nparams[i] = value.Interface()
+		}
+	case reflect.Struct:
+		vtype := val.Type()
+		for i, name := range parser.names {
+			found := false
+			for j := 0; j < vtype.NumField() && !found; j++ {
+				field := vtype.Field(j)
+				if !field.IsExported() {
+					continue
+				}
+				if tag := field.Tag.Get("cond"); tag == name || (tag == "" && strings.EqualFold(field.Name, name)) {
+					nparams[i] = val.Field(j).Interface()
+					found = true
+				}
+			}
+			if !found {
+				XLog.Panic("XOrm.Cond: named parameter {%s} was not found in %v, expression: %v", name, vtype, parser.expr)
+			}
+		}
+	default:
+		XLog.Panic("XOrm.Cond: named parameters require a map or struct argument not %T, expression: %v", params[0], parser.expr)
+	}
+	return nparams
+}
+
+// exprCondition 解析表达式并返回条件实例、分页限定、分页偏移和排序规则。
+func exprCondition(expr string, params []any) (cond *orm.Condition, limit, offset int, orders []string) {
 	var parser *exprParser
 	if tmp, _ := exprParserCache.Load(expr); tmp != nil {
 		parser = tmp.(*exprParser)
@@ -413,25 +588,52 @@ func exprCondition(expr string, params []any) (cond *orm.Condition, limit, offse
 		exprParserCache.Store(expr, parser)
 	}
 
+	if len(parser.names) > 0 { // 命名参数与位置参数共享解析结果
+		params = exprNamedParams(parser, params)
+	}
 	cond = cloneCondition(parser.cond, expr, params)
 
 	if parser.limit != -1 {
 		if parser.limit >= len(params) || parser.limit < 0 {
 			XLog.Panic("XOrm.Cond: parameter limit index is out of range: %d, expression: %v", parser.limit, parser.expr)
+		} else if v, ok := params[parser.limit].(int); ok {
+			limit = v
 		} else {
-			limit = params[parser.limit].(int)
+			XLog.Panic("XOrm.Cond: parameter limit must be int: %T, expression: %v", params[parser.limit], parser.expr)
 		}
 	}
 
 	if parser.offset != -1 {
 		if parser.offset >= len(params) || parser.offset < 0 {
 			XLog.Panic("XOrm.Cond: parameter offset index is out of range: %d, expression: %v", parser.offset, parser.expr)
+		} else if v, ok := params[parser.offset].(int); ok {
+			offset = v
 		} else {
-			offset = params[parser.offset].(int)
+			XLog.Panic("XOrm.Cond: parameter offset must be int: %T, expression: %v", params[parser.offset], parser.expr)
+		}
+	}
+
+	if parser.order != -1 {
+		if parser.order >= len(params) || parser.order < 0 {
+			XLog.Panic("XOrm.Cond: parameter orderby index is out of range: %d, expression: %v", parser.order, parser.expr)
+		}
+		switch param := params[parser.order].(type) {
+		case string:
+			for _, order := range strings.Split(param, ",") {
+				if order = strings.TrimSpace(order); strings.TrimPrefix(order, "-") != "" {
+					orders = append(orders, order)
+				}
+			}
+		case []string:
+			orders = append(orders, param...)
+		default:
+			XLog.Panic("XOrm.Cond: parameter orderby must be string or []string: %T, expression: %v", param, parser.expr)
 		}
+	} else if len(parser.orders) > 0 {
+		orders = append(orders, parser.orders...)
 	}
 
-	return cond, limit, offset
+	return cond, limit, offset, orders
 }
 
 // exprTokenize 将表达式分解为词法单元。
@@ -482,6 +684,8 @@ func exprTokenize(expr string) (tokens []exprToken) {
 			} else {
 				result.WriteString(" < ")
 			}
+		case ',':
+			result.WriteString(" , ")
 		case '=':
 			if i+1 < len(expr) && expr[i+1] == '=' {
 				result.WriteString(" == ")
@@ -515,6 +719,10 @@ func exprTokenize(expr string) (tokens []exprToken) {
 			tokens = append(tokens, exprToken{typ: exprTokenTypeLimit, value: word, pos: pos})
 		case "offset":
 			tokens = append(tokens, exprToken{typ: exprTokenTypeOffset, value: word, pos: pos})
+		case "orderby":
+			tokens = append(tokens, exprToken{typ: exprTokenTypeOrder, value: word, pos: pos})
+		case ",":
+			tokens = append(tokens, exprToken{typ: exprTokenTypeComma, value: word, pos: pos})
 		case "=":
 			tokens = append(tokens, exprToken{typ: exprTokenTypeAssign, value: word, pos: pos})
 		default:
@@ -600,3 +808,171 @@ func getCondParams(cond *orm.Condition) []beegoCondValue {
 	ncond := (*beegoCondition)(unsafe.Pointer(cond))
 	return ncond.params
 }
+
+// sqlOperatorNames 存储 beego/orm 支持的操作符名称，用于从表达式中分离字段和操作符。
+var sqlOperatorNames = map[string]bool{
+	"exact": true, "iexact": true, "strictexact": true, "contains": true, "icontains": true,
+	"gt": true, "gte": true, "lt": true, "lte": true, "eq": true, "ne": true,
+	"startswith": true, "endswith": true, "istartswith": true, "iendswith": true,
+	"in": true, "between": true, "isnull": true,
+}
+
+// sqlFlatParams 展开条件参数，切片及数组（[]byte 除外）会被展开为多个参数，指针会被解引用。
+func sqlFlatParams(args []any) []any {
+	var params []any
+	for _, arg := range args {
+		if arg == nil {
+			params = append(params, nil)
+			continue
+		}
+		val := reflect.ValueOf(arg)
+		if val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				params = append(params, nil)
+				continue
+			}
+			val = val.Elem()
+			arg = val.Interface()
+		}
+		switch val.Kind() {
+		case reflect.Slice, reflect.Array:
+			if _, ok := arg.([]byte); ok {
+				params = append(params, arg)
+				continue
+			}
+			var nargs []any
+			for i := 0; i < val.Len(); i++ {
+				if elem := val.Index(i); elem.CanInterface() && elem.Interface() != nil {
+					nargs = append(nargs, elem.Interface())
+				}
+			}
+			params = append(params, sqlFlatParams(nargs)...)
+		default:
+			params = append(params, arg)
+		}
+	}
+	return params
+}
+
+// orderBy 返回条件的完整排序规则。
+// 指定了排序规则或分页参数时，若排序规则未包含主键则追加主键（升序），以确保排序及分页结果的稳定性；
+// 均未指定时返回 nil。
+func (c *Condition) orderBy(meta *modelMeta) []string {
+	if c == nil || (len(c.Orders) == 0 && c.Limit <= 0 && c.Offset <= 0) {
+		return nil
+	}
+	orders := make([]string, 0, len(c.Orders)+1)
+	orders = append(orders, c.Orders...)
+	if meta != nil && meta.fields.pk != nil {
+		for _, order := range c.Orders {
+			if meta.field(strings.TrimPrefix(order, "-")) == meta.fields.pk {
+				return orders
+			}
+		}
+		orders = append(orders, meta.fields.pk.name)
+	}
+	return orders
+}
+
+// unpaged 返回用于远端读取的条件，偏移量置为 0 且限定数量包含偏移的部分，
+// 以便与会话内存及全局内存中的数据合并排序后再分页；未指定分页参数时返回条件本身。
+// 远端读取的记录数为 offset + limit，故深分页的开销随偏移量线性增长；
+// 远端记录在内存中被标记删除或修改后不再匹配时将被移除，此时该页的结果可能少于 limit 条。
+func (c *Condition) unpaged() *Condition {
+	if c == nil || (c.Limit <= 0 && c.Offset <= 0) {
+		return c
+	}
+	nc := &Condition{Base: c.Base, Orders: c.Orders}
+	if c.Limit > 0 {
+		nc.Limit = c.Offset + c.Limit
+	}
+	return nc
+}
+
+// paginate 按照条件的偏移量及限定数量截取已排序的结果，条件为 nil 时返回原结果。
+func paginate[T any](c *Condition, rets []T) []T {
+	if c == nil {
+		return rets
+	}
+	if c.Offset > 0 {
+		rets = rets[min(c.Offset, len(rets)):]
+	}
+	if c.Limit > 0 && c.Limit < len(rets) {
+		rets = rets[:c.Limit]
+	}
+	return rets
+}
+
+// compareOrders 按照排序规则比较两个模型。
+// 返回负数、零或正数，分别表示 a 排在 b 之前、两者相同或 a 排在 b 之后。
+// 空值视为最小值（与 MySQL 的排序规则一致），字符串按字节序比较，未知的字段将被忽略。
+func compareOrders(meta *modelMeta, a, b IModel, orders []string) int {
+	for _, order := range orders {
+		name := strings.TrimPrefix(order, "-")
+		field := meta.field(name)
+		if field == nil {
+			continue
+		}
+		if ret := compareValue(a.DataValue(field.name), b.DataValue(field.name)); ret != 0 {
+			if len(name) != len(order) { // 降序
+				return -ret
+			}
+			return ret
+		}
+	}
+	return 0
+}
+
+// compareValue 比较两个相同类型的字段值，指针类型将比较其指向的值。
+func compareValue(a, b any) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for va.IsValid() && va.Kind() == reflect.Ptr {
+		if va.IsNil() {
+			va = reflect.Value{}
+		} else {
+			va = va.Elem()
+		}
+	}
+	for vb.IsValid() && vb.Kind() == reflect.Ptr {
+		if vb.IsNil() {
+			vb = reflect.Value{}
+		} else {
+			vb = vb.Elem()
+		}
+	}
+	switch {
+	case !va.IsValid() && !vb.IsValid():
+		return 0
+	case !va.IsValid():
+		return -1
+	case !vb.IsValid():
+		return 1
+	case va.Kind() != vb.Kind():
+		return strings.Compare(fmt.Sprint(va.Interface()), fmt.Sprint(vb.Interface()))
+	}
+
+	if ta, ok := va.Interface().(time.Time); ok {
+		if tb, ok := vb.Interface().(time.Time); ok {
+			return ta.Compare(tb)
+		}
+	}
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(va.Int(), vb.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(va.Uint(), vb.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(va.Float(), vb.Float())
+	case reflect.String:
+		return strings.Compare(va.String(), vb.String())
+	case reflect.Bool:
+		if va.Bool() == vb.Bool() {
+			return 0
+		} else if vb.Bool() {
+			return -1
+		}
+		return 1
+	default:
+		return strings.Compare(fmt.Sprint(va.Interface()), fmt.Sprint(vb.Interface()))
+	}
+}
@@ -6,13 +6,29 @@ package XOrm
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.UTIL/XObject"
 	"github.com/stretchr/testify/assert"
 )
 
+// TestSQLModel 是条件匹配及远端查询的测试模型。
+type TestSQLModel struct {
+	Model[TestSQLModel] `orm:"-" json:"-"`
+	ID                  int       `orm:"column(id);pk"`
+	Name                string    `orm:"column(name)"`
+	Score               float64   `orm:"column(score)"`
+	Birth               time.Time `orm:"column(birth);type(date)"`
+}
+
+func (m *TestSQLModel) AliasName() string { return "sql_alias" }
+
+func (m *TestSQLModel) TableName() string { return "sql_table" }
+
 func TestOrmCond(t *testing.T) {
 	t.Run("New", func(t *testing.T) {
 		defer exprParserCache.Clear()
@@ -79,6 +95,14 @@ func TestOrmCond(t *testing.T) {
 			{"name startswith {0}", []any{"test"}, false},
 			{"name endswith {0}", []any{"test"}, false},
 			{"active isnull {0}", []any{true}, false},
+			{"id in {0}", []any{[]int{1, 2}}, false},
+			{"id notin {0}", []any{[]int{1, 2}}, false},
+			{"score between {0}", []any{[]float64{1, 2}}, false},
+			{"name iexact {0}", []any{"Test"}, false},
+			{"name icontains {0}", []any{"Test"}, false},
+			{"name istartswith {0}", []any{"Test"}, false},
+			{"name iendswith {0}", []any{"Test"}, false},
+			{"!(id notin {0}) && name icontains {1}", []any{[]int{1, 2}, "test"}, false},
 
 			// 复合条件测试 - 正常情况
 			{"(age > {0} && name contains {1}) || (status == {2})", []any{18, "test", "active"}, false},
@@ -90,14 +114,28 @@ func TestOrmCond(t *testing.T) {
 			{"limit={0}", []any{1}, false},
 			{"offset = {0}", []any{1}, false},
 			{"offset={0}", []any{1}, false},
+			{"limit = {0} && offset = {1}", []any{1, 2}, false},
 			{"name == {0} && age > {1} && limit = {2} && offset = {3}", []any{"test", 10, 20, 30}, false},
 			{"age > {0} && limit = {1}", []any{18, 10}, false},
 			{"age > {0} && offset = {1}", []any{18, 5}, false},
 			{"age > {0} && limit = {1} && offset = {2}", []any{18, 10, 5}, false},
 
+			// 排序参数测试 - 正常情况
+			{"orderby = -exp", []any{}, false},
+			{"orderby=-exp,id", []any{}, false},
+			{"orderby = -exp, id", []any{}, false},
+			{"orderby = {0}", []any{"-exp,id"}, false},
+			{"orderby = {0}", []any{[]string{"-exp", "id"}}, false},
+			{"age > {0} && orderby = -exp,id && limit = {1}", []any{18, 10}, false},
+			{"age > {0} && orderby = {1} && offset = {2}", []any{18, "-exp", 5}, false},
+
 			// 语法错误测试
+			{"orderby -exp", []any{}, true},                                 // orderby 没有使用赋值符号
+			{"orderby = -exp,", []any{}, true},                              // orderby 以逗号结尾
+			{"orderby = -", []any{}, true},                                  // orderby 字段名为空
+			{"age > {0} && orderby = {1}", []any{18, 1}, true},              // orderby 参数类型错误
 			{"((a > {0})", []any{1}, true},                                  // 括号不匹配
-			{"a > {abc}", []any{1}, true},                                   // 参数索引格式错误
+			{"a > {1a}", []any{1}, true},                                    // 参数索引格式错误
 			{"a  b", []any{}, true},                                         // 无效的表达式
 			{"a > {0} limit {1}", []any{1, 1}, true},                        // limit 没有使用赋值符号和逻辑连接符
 			{"a > {0} offset {1}", []any{1, 1}, true},                       // offset 没有使用赋值符号和逻辑连接符
@@ -174,6 +212,181 @@ func TestOrmCond(t *testing.T) {
 		}
 	})
 
+	t.Run("Order", func(t *testing.T) {
+		defer exprParserCache.Clear()
+		exprParserCache.Clear()
+		defer orm.ResetModelCache()
+		orm.ResetModelCache()
+		Meta(XObject.New[TestSQLModel](), false, true)
+		meta := getModelMeta(XObject.New[TestSQLModel]())
+
+		cond := Cond("score > {0} && orderby = -score, name", 1)
+		assert.Equal(t, []string{"-score", "name"}, cond.Orders, "解析后的排序规则应当和表达式相等。")
+		assert.Equal(t, []string{"-score", "name", "ID"}, cond.orderBy(meta), "排序规则未包含主键时应当追加主键。")
+
+		cond = Cond("orderby = {0}", "name, -id")
+		assert.Equal(t, []string{"name", "-id"}, cond.Orders, "解析后的排序规则应当和参数相等。")
+		assert.Equal(t, []string{"name", "-id"}, cond.orderBy(meta), "排序规则包含主键时不应当追加主键。")
+
+		cond = Cond("orderby = -score && score > {0} && limit = {1}", 1, 10)
+		assert.Equal(t, []string{"-score"}, cond.Orders, "表达式以排序规则开头时应当正确解析。")
+		assert.False(t, cond.Base.IsEmpty(), "表达式以排序规则开头时不应当忽略后续的条件。")
+		assert.Equal(t, 10, cond.Limit, "表达式以排序规则开头时不应当忽略后续的分页参数。")
+
+		cond = Cond("limit = {0}", 10)
+		assert.Equal(t, []string{"ID"}, cond.orderBy(meta), "分页时应当默认按主键排序。")
+		assert.Nil(t, Cond("score > {0}", 1).orderBy(meta), "未指定排序及分页时不应当排序。")
+
+		cond = Cond("score > {0} && limit = {1} && offset = {2}", 1, 2, 3)
+		assert.Equal(t, 5, cond.unpaged().Limit, "远端读取的限定数量应当包含偏移的部分。")
+		assert.Equal(t, 0, cond.unpaged().Offset, "远端读取的偏移量应当为 0。")
+		assert.Equal(t, []string{"ID"}, cond.unpaged().orderBy(meta), "远端读取时应当保持按主键排序。")
+		assert.Equal(t, []int{4, 5}, paginate(cond, []int{1, 2, 3, 4, 5, 6}), "分页结果应当和预期相等。")
+		assert.Empty(t, paginate(cond, []int{1, 2}), "偏移量超出结果数量时应当返回空结果。")
+		assert.Equal(t, []int{1, 2}, paginate(nil, []int{1, 2}), "条件为 nil 时应当返回原结果。")
+		assert.Equal(t, 0, Cond("offset = {0}", 3).unpaged().Limit, "仅指定偏移量时远端读取不应当限定数量。")
+
+		assert.NoError(t, Cond("orderby = -Score,name,id").checkOrders(meta), "排序字段可以是字段名、小写字段名或列名。")
+		assert.NoError(t, (*Condition)(nil).checkOrders(meta), "条件为 nil 时不应当返回错误。")
+		err := Cond("orderby = -unknown").checkOrders(meta)
+		assert.ErrorIs(t, err, ErrInvalidCond, "未知的排序字段应当返回 ErrInvalidCond。")
+		_, err = ListColumns(XObject.New[TestSQLModel](), []string{"id"}, Cond("orderby = unknown"))
+		assert.ErrorIs(t, err, ErrInvalidCond, "ListColumns 的排序字段未知时应当返回 ErrInvalidCond 而非 panic。")
+
+		newModel := func(id int, name string, score float64) *TestSQLModel {
+			model := XObject.New[TestSQLModel]()
+			model.ID = id
+			model.Name = name
+			model.Score = score
+			return model
+		}
+		models := []*TestSQLModel{newModel(1, "b", 1), newModel(2, "a", 2), newModel(3, "c", 2), newModel(4, "a", 1)}
+		orders := Cond("orderby = -score,name").orderBy(meta)
+		slices.SortStableFunc(models, func(a, b *TestSQLModel) int { return compareOrders(meta, a, b, orders) })
+		ids := make([]int, 0, len(models))
+		for _, model := range models {
+			ids = append(ids, model.ID)
+		}
+		assert.Equal(t, []int{2, 3, 4, 1}, ids, "排序后的结果应当和预期相等。")
+
+		assert.Equal(t, -1, compareValue(nil, 1), "空值应当视为最小值。")
+		assert.Equal(t, 1, compareValue(time.Unix(2, 0), time.Unix(1, 0)), "时间应当按时刻比较。")
+		assert.Equal(t, -1, compareValue(false, true), "布尔值 false 应当小于 true。")
+	})
+
+	t.Run("Operator", func(t *testing.T) {
+		defer exprParserCache.Clear()
+		exprParserCache.Clear()
+		defer orm.ResetModelCache()
+		orm.ResetModelCache()
+		Meta(XObject.New[TestSQLModel](), false, true)
+
+		model := XObject.New[TestSQLModel]()
+		model.ID = 2
+		model.Name = "Test_Name"
+		model.Score = 1.5
+		model.Birth = time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		tests := []struct {
+			expr  string
+			args  []any
+			match bool
+		}{
+			{"id in {0}", []any{[]int{1, 2}}, true},
+			{"id in {0}", []any{[]int{3, 4}}, false},
+			{"id notin {0}", []any{[]int{1, 2}}, false},
+			{"id notin {0}", []any{[]int{3, 4}}, true},
+			{"!(id notin {0})", []any{[]int{1, 2}}, true},
+			{"id > {0} && id notin {1}", []any{1, []int{3}}, true},
+			{"score in {0}", []any{[]float64{1.5, 2.5}}, true},
+			{"score between {0}", []any{[]float64{1, 2}}, true},
+			{"score between {0}", []any{[]float64{2, 3}}, false},
+			{"id between {0}", []any{[]int{2, 3}}, true},
+			{"name between {0}", []any{[]string{"T", "U"}}, true},
+			{"birth between {0}", []any{[]time.Time{model.Birth, model.Birth.AddDate(0, 0, 1)}}, true},
+			{"score > {0}", []any{1.2}, true},
+			{"score <= {0}", []any{1}, false},
+			{"id < {0}", []any{2.5}, true},
+			{"name iexact {0}", []any{"test_name"}, true},
+			{"name iexact {0}", []any{"test"}, false},
+			{"name icontains {0}", []any{"T_N"}, true},
+			{"name istartswith {0}", []any{"TEST"}, true},
+			{"name iendswith {0}", []any{"NAME"}, true},
+			{"name iendswith {0}", []any{"test"}, false},
+			{"name contains {0}", []any{"t_n"}, false},
+		}
+
+		for _, test := range tests {
+			t.Run(fmt.Sprintf("%v%v", test.expr, test.args), func(t *testing.T) {
+				cond := Cond(append([]any{test.expr}, test.args...)...)
+				assert.Equal(t, test.match, model.Matchs(cond), "缓存的匹配结果应当和预期相等。")
+			})
+		}
+
+		// 内存匹配按字面值比较，远端的 LIKE 参数中 _ 未被转义，仍作为通配符
+		other := XObject.New[TestSQLModel]()
+		other.Name = "Test-Name"
+		cond := Cond("name iexact {0}", "test_name")
+		assert.False(t, other.Matchs(cond), "内存匹配时 iexact 参数中的 _ 应当按字面值比较。")
+		params := getCondParams(cond.Base)
+		assert.Equal(t, 1, len(params), "条件参数的数量应当为 1。")
+		assert.Equal(t, []any{"test_name"}, params[0].args, "远端查询时 iexact 参数中的 _ 不应当被转义。")
+	})
+
+	t.Run("Named", func(t *testing.T) {
+		defer exprParserCache.Clear()
+		exprParserCache.Clear()
+
+		var collect func(cond *orm.Condition) []any
+		collect = func(cond *orm.Condition) []any {
+			var args []any
+			for _, param := range getCondParams(cond) {
+				args = append(args, param.args...)
+				if param.isCond && param.cond != nil {
+					args = append(args, collect(param.cond)...)
+				}
+			}
+			return args
+		}
+
+		expr := "level >= {min} && (guild == {guild} || level >= {max}) && limit = {limit}"
+		cond := Cond(expr, map[string]any{"min": 10, "max": 50, "guild": "g1", "limit": 20})
+		assert.Equal(t, []any{10, "g1", 50}, collect(cond.Base), "命名参数应当按照名称绑定。")
+		assert.Equal(t, 20, cond.Limit, "命名的分页参数应当按照名称绑定。")
+
+		tmp, _ := exprParserCache.Load(expr)
+		assert.Equal(t, []string{"min", "guild", "max", "limit"}, tmp.(*exprParser).names, "命名参数应当按照首次出现的顺序记录。")
+
+		type filter struct {
+			Min   int
+			Guild string `cond:"guild"`
+			Max   int    `cond:"max"`
+			Limit int
+		}
+		cond = Cond(expr, &filter{Min: 1, Guild: "g2", Max: 5, Limit: 2})
+		assert.Equal(t, []any{1, "g2", 5}, collect(cond.Base), "结构体参数应当按照标签或字段名绑定。")
+		assert.Equal(t, 2, cond.Limit, "结构体的分页参数应当和预期相等。")
+
+		cond = Cond("id == {id} || id == {id}", map[string]any{"id": 3})
+		assert.Equal(t, []any{3, 3}, collect(cond.Base), "重复的命名参数应当绑定相同的值。")
+
+		panics := []struct {
+			expr string
+			args []any
+		}{
+			{"a > {min}", []any{1}},                                      // 参数不是 map 或结构体
+			{"a > {min}", []any{map[string]any{"max": 1}}},               // 参数不存在
+			{"a > {min}", []any{map[string]any{"min": 1}, 2}},            // 参数数量错误
+			{"a > {min}", []any{map[int]any{0: 1}}},                      // map 的键不是字符串
+			{"a > {min}", []any{struct{ Max int }{1}}},                   // 结构体字段不存在
+			{"a > {min} && b > {0}", []any{map[string]any{"min": 1}, 1}}, // 混用命名参数和位置参数
+			{"a > {0} && b > {min}", []any{1, map[string]any{"min": 1}}}, // 混用位置参数和命名参数
+		}
+		for _, test := range panics {
+			assert.Panics(t, func() { Cond(append([]any{test.expr}, test.args...)...) }, "错误的命名参数应当 panic：%v", test.expr)
+		}
+	})
+
 	t.Run("Cache", func(t *testing.T) {
 		defer exprParserCache.Clear()
 		exprParserCache.Clear()
@@ -0,0 +1,240 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// CondFor 创建指定模型的条件并校验，参数与 Cond 相同。
+// 与 CondE 相同，表达式的语法或参数错误不会 panic，而是作为错误返回；
+// 创建成功后将使用 Validate 校验条件中的字段、操作符及参数类型，T 不是结构体指针时同样返回错误，返回的错误均匹配 ErrInvalidCond。
+//
+// 使用示例：
+//
+//	cond, err := XOrm.CondFor[*User]("level >= {0} && name contains {1}", 10, "a")
+//	if err != nil {
+//		return err
+//	}
+func CondFor[T IModel](condOrExprAndParams ...any) (*Condition, error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct { // 如 IModel 接口类型，无法创建模型实例
+		return nil, fmt.Errorf("%w: model type %v must be a pointer to struct", ErrInvalidCond, typ)
+	}
+	model := reflect.New(typ.Elem()).Interface().(T)
+	model.Ctor(model)
+
+	cond, err := CondE(condOrExprAndParams...)
+	if err != nil {
+		return nil, err
+	}
+	if err := cond.Validate(model); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCond, err)
+	}
+	return cond, nil
+}
+
+// Validate 根据模型的注册信息校验条件。
+// 校验的内容包括：字段是否为模型的数据库字段、操作符与字段类型是否兼容（如字符串匹配仅支持字符串字段）、
+// 参数的类型及数量是否与字段和操作符匹配（如 in 需要切片参数，between 需要两个参数），以及排序规则中的字段是否存在。
+// 未通过校验的条件在远端查询时会返回错误，在内存匹配（Matchs）时会被视为不匹配，故建议对外部输入构建的条件进行校验。
+// 返回所有的校验错误，校验通过时返回 nil。
+func (c *Condition) Validate(model IModel) error {
+	if model == nil {
+		return fmt.Errorf("nil model instance")
+	}
+	meta := getModelMeta(model)
+	if meta == nil {
+		return fmt.Errorf("model of %v was not registered", model.TableName())
+	}
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	if c.Base != nil {
+		errs = validCondition(meta, c.Base, errs)
+	}
+	errs = c.validOrders(meta, errs)
+	return errors.Join(errs...)
+}
+
+// validOrders 校验排序规则中的字段是否为模型的数据库字段，返回追加后的错误列表。
+func (c *Condition) validOrders(meta *modelMeta, errs []error) []error {
+	if c == nil {
+		return errs
+	}
+	for _, order := range c.Orders {
+		if meta.field(strings.TrimPrefix(order, "-")) == nil {
+			errs = append(errs, fmt.Errorf("unknown order field `%v` of %v", order, meta.table))
+		}
+	}
+	return errs
+}
+
+// checkOrders 在查询前校验排序规则，存在未知的字段时返回匹配 ErrInvalidCond 的错误。
+// 远端查询时 beego/orm 对未知的排序字段会 panic，而内存排序时会忽略该字段，故统一在查询前校验以保持一致。
+func (c *Condition) checkOrders(meta *modelMeta) error {
+	if errs := c.validOrders(meta, nil); len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidCond, errors.Join(errs...))
+	}
+	return nil
+}
+
+// validCondition 递归地校验条件，返回追加后的错误列表。
+func validCondition(meta *modelMeta, cond *orm.Condition, errs []error) []error {
+	for _, param := range getCondParams(cond) {
+		if param.isCond {
+			if param.cond != nil {
+				errs = validCondition(meta, param.cond, errs)
+			}
+			continue
+		}
+
+		expr := strings.Join(param.exprs, orm.ExprSep)
+		exprs := param.exprs
+		operator := "exact"
+		if num := len(exprs) - 1; num >= 0 && sqlOperatorNames[exprs[num]] {
+			operator = exprs[num]
+			exprs = exprs[:num]
+		}
+		if len(exprs) != 1 {
+			errs = append(errs, fmt.Errorf("unsupported expression `%v` of %v", expr, meta.table))
+			continue
+		}
+		field := meta.field(exprs[0])
+		if field == nil {
+			errs = append(errs, fmt.Errorf("unknown field `%v` of %v", exprs[0], meta.table))
+			continue
+		}
+		if param.isRaw {
+			continue
+		}
+		if err := validOperator(field, operator, param.args); err != nil {
+			errs = append(errs, fmt.Errorf("invalid expression `%v` of %v: %v", expr, meta.table, err))
+		}
+	}
+	return errs
+}
+
+// validOperator 校验操作符与字段类型及参数的兼容性。
+func validOperator(field *beegoFieldInfo, operator string, args []any) error {
+	params := sqlFlatParams(args)
+	switch operator {
+	case "isnull":
+		if len(params) != 1 {
+			return fmt.Errorf("operator `%v` needs 1 arg not %v", operator, len(params))
+		}
+		if _, ok := params[0].(bool); !ok {
+			return fmt.Errorf("operator `%v` needs a bool arg not %T", operator, params[0])
+		}
+		return nil
+	case "in":
+		if len(params) == 0 {
+			return fmt.Errorf("operator `%v` needs at least 1 arg", operator)
+		}
+	case "between":
+		if len(params) != 2 {
+			return fmt.Errorf("operator `%v` needs 2 args not %v", operator, len(params))
+		}
+	default:
+		if len(params) != 1 {
+			return fmt.Errorf("operator `%v` needs 1 arg not %v", operator, len(params))
+		}
+	}
+
+	kind := validFieldKind(field)
+	switch operator {
+	case "contains", "startswith", "endswith", "iexact", "icontains", "istartswith", "iendswith":
+		if kind != reflect.String {
+			return fmt.Errorf("operator `%v` is only supported by string field", operator)
+		}
+	case "gt", "gte", "lt", "lte", "between":
+		if kind == reflect.Bool {
+			return fmt.Errorf("operator `%v` is not supported by bool field", operator)
+		}
+	}
+
+	for _, param := range params {
+		if param == nil {
+			if operator == "exact" || operator == "ne" {
+				continue
+			}
+			return fmt.Errorf("operator `%v` does not support nil arg", operator)
+		}
+		if !validArgKind(kind, param) {
+			return fmt.Errorf("arg type %T does not match field type", param)
+		}
+	}
+	return nil
+}
+
+// validFieldKind 返回字段的值分类：Int（整型及浮点）、String、Bool、Struct（时间），其他字段返回 Invalid 且不校验参数类型。
+// 时间字段的参数可以是 time.Time 或 beego/orm 可解析的日期、日期时间及时间字符串（如 "2025-01-02"）。
+func validFieldKind(field *beegoFieldInfo) reflect.Kind {
+	switch {
+	case field.fieldType&orm.IsIntegerField != 0, field.fieldType == orm.TypeFloatField, field.fieldType == orm.TypeDecimalField:
+		return reflect.Int
+	case field.fieldType == orm.TypeBooleanField:
+		return reflect.Bool
+	case field.fieldType == orm.TypeVarCharField, field.fieldType == orm.TypeCharField, field.fieldType == orm.TypeTextField:
+		return reflect.String
+	case field.fieldType == orm.TypeDateField, field.fieldType == orm.TypeDateTimeField, field.fieldType == orm.TypeTimeField:
+		return reflect.Struct
+	}
+	return reflect.Invalid
+}
+
+// validArgKind 校验参数是否与字段的值分类匹配。
+func validArgKind(kind reflect.Kind, arg any) bool {
+	val := reflect.ValueOf(arg)
+	switch kind {
+	case reflect.Int:
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return true
+		}
+		return false
+	case reflect.Bool:
+		return val.Kind() == reflect.Bool
+	case reflect.String:
+		return val.Kind() == reflect.String
+	case reflect.Struct:
+		switch v := arg.(type) {
+		case time.Time:
+			return true
+		case string:
+			_, ok := parseTimeParam(v)
+			return ok
+		}
+		return false
+	}
+	return true
+}
+
+// parseTimeParam 按照 beego/orm 解析时间字段字符串参数的规则解析 str：
+// 不少于 19 个字符时截取前 19 个字符按日期时间（2006-01-02 15:04:05）解析，不少于 10 个字符时截取前 10 个字符按日期（2006-01-02）解析，
+// 否则截取前 8 个字符按时间（15:04:05）解析，解析失败时返回 false。
+func parseTimeParam(str string) (time.Time, bool) {
+	layout := time.TimeOnly
+	switch {
+	case len(str) >= 19:
+		str, layout = str[:19], time.DateTime
+	case len(str) >= 10:
+		str, layout = str[:10], time.DateOnly
+	case len(str) > 8:
+		str = str[:8]
+	}
+	t, err := time.ParseInLocation(layout, str, orm.DefaultTimeLoc)
+	return t, err == nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.UTIL/XObject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrmCondValid(t *testing.T) {
+	defer exprParserCache.Clear()
+	defer orm.ResetModelCache()
+	orm.ResetModelCache()
+	model := XObject.New[TestSQLModel]()
+	Meta(model, false, true)
+	Meta(NewTestBaseModel(), false, true)
+
+	t.Run("Validate", func(t *testing.T) {
+		tests := []struct {
+			expr  string
+			args  []any
+			valid bool
+		}{
+			{"id > {0} && name contains {1}", []any{1, "a"}, true},
+			{"ID == {0} || Name == {1}", []any{1, "a"}, true},
+			{"score >= {0} && score < {1}", []any{1, 2.5}, true},
+			{"birth > {0}", []any{time.Now()}, true},
+			{"birth > {0}", []any{"2025-01-01"}, true},                                        // 时间字段支持日期字符串
+			{"birth between {0}", []any{[]string{"2025-01-01 00:00:00", "2025-01-02"}}, true}, // 时间字段支持日期时间字符串
+			{"id in {0} && score between {1}", []any{[]int{1, 2}, []float64{1, 2}}, true},
+			{"name isnull {0} && name == {1}", []any{false, nil}, true},
+			{"(id > {0} || !(name iexact {1})) && orderby = -score,id", []any{1, "a"}, true},
+
+			{"nmae == {0}", []any{"a"}, false},                                // 字段名错误
+			{"id > {0} && (score < {1} || lvl > {2})", []any{1, 2, 3}, false}, // 嵌套条件中的字段名错误
+			{"id contains {0}", []any{"1"}, false},                            // 字符串匹配不支持整型字段
+			{"id > {0}", []any{"1"}, false},                                   // 参数类型与字段类型不匹配
+			{"name == {0}", []any{1}, false},                                  // 参数类型与字段类型不匹配
+			{"birth > {0}", []any{"2025/01/01"}, false},                       // 时间字段的字符串参数需要符合 ORM 的时间格式
+			{"birth > {0}", []any{1}, false},                                  // 时间字段不支持数值参数
+			{"id in {0}", []any{[]int{}}, false},                              // in 的参数为空
+			{"score between {0}", []any{[]float64{1}}, false},                 // between 的参数数量错误
+			{"name isnull {0}", []any{"true"}, false},                         // isnull 需要布尔参数
+			{"id > {0}", []any{nil}, false},                                   // 比较操作符不支持空值
+			{"id > {0} && orderby = -level", []any{1}, false},                 // 排序字段不存在
+		}
+
+		for _, test := range tests {
+			t.Run(fmt.Sprintf("%v%v", test.expr, test.args), func(t *testing.T) {
+				err := Cond(append([]any{test.expr}, test.args...)...).Validate(model)
+				if test.valid {
+					assert.NoError(t, err, "正确的条件应当通过校验。")
+				} else {
+					assert.Error(t, err, "错误的条件不应当通过校验。")
+				}
+			})
+		}
+
+		err := Cond("nmae == {0} && id contains {1}", "a", "1").Validate(model)
+		assert.ErrorContains(t, err, "nmae", "校验错误应当包含错误的字段名。")
+		assert.ErrorContains(t, err, "id__contains", "校验应当返回所有的错误。")
+
+		assert.NoError(t, Cond().Validate(model), "空条件应当通过校验。")
+		assert.NoError(t, Cond(orm.NewCondition().Raw("id", "> 1")).Validate(model), "原生条件只校验字段名。")
+		assert.Error(t, Cond("bool_val > {0}", true).Validate(NewTestBaseModel()), "布尔字段不支持比较操作符。")
+		assert.NoError(t, Cond("bool_val == {0}", true).Validate(NewTestBaseModel()), "布尔字段支持等于操作符。")
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		tests := []struct {
+			arg    string
+			expect time.Time
+			valid  bool
+		}{
+			{"2025-01-02 03:04:05", time.Date(2025, 1, 2, 3, 4, 5, 0, orm.DefaultTimeLoc), true},
+			{"2025-01-02 03:04:05.123", time.Date(2025, 1, 2, 3, 4, 5, 0, orm.DefaultTimeLoc), true},
+			{"2025-01-02", time.Date(2025, 1, 2, 0, 0, 0, 0, orm.DefaultTimeLoc), true},
+			{"03:04:05", time.Date(0, 1, 1, 3, 4, 5, 0, orm.DefaultTimeLoc), true},
+			{"2025-13-01", time.Time{}, false},
+			{"invalid", time.Time{}, false},
+			{"", time.Time{}, false},
+		}
+		for _, test := range tests {
+			t.Run(test.arg, func(t *testing.T) {
+				val, ok := parseTimeParam(test.arg)
+				assert.Equal(t, test.valid, ok, "时间字符串的解析结果应当和预期相等。")
+				if test.valid {
+					assert.True(t, test.expect.Equal(val), "解析的时间应当和预期相等。")
+				}
+			})
+		}
+	})
+
+	t.Run("CondFor", func(t *testing.T) {
+		cond, err := CondFor[*TestSQLModel]("id > {0} && limit = {1}", 1, 10)
+		assert.NoError(t, err, "正确的条件不应当返回错误。")
+		assert.Equal(t, 10, cond.Limit, "创建的条件应当和预期相等。")
+
+		_, err = CondFor[*TestSQLModel]("((id > {0})", 1)
+		assert.Error(t, err, "语法错误应当作为错误返回。")
+
+		_, err = CondFor[*TestSQLModel]("id > {1}", 1)
+		assert.Error(t, err, "参数错误应当作为错误返回。")
+
+		_, err = CondFor[*TestSQLModel]("level > {0}", 1)
+		assert.Error(t, err, "字段错误应当作为错误返回。")
+
+		assert.NotPanics(t, func() {
+			cond, err = CondFor[IModel]("id > {0}", 1)
+		}, "非结构体指针的模型类型不应当 panic。")
+		assert.ErrorIs(t, err, ErrInvalidCond, "非结构体指针的模型类型应当返回 ErrInvalidCond。")
+		assert.Nil(t, cond, "发生错误时应当返回 nil。")
+	})
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"errors"
+
+	"github.com/eframework-org/GO.UTIL/XLog"
+)
+
+// 以下错误由带有 E 后缀的函数（如 ReadE、ListE、WriteE、CountE 及 CondE）返回，可以使用 errors.Is 判断，
+// 数据库驱动等其他错误将被原样或包装后返回。
+var (
+	// ErrNoContext 表示当前 goroutine 未通过 Watch 开始 CRUD 监控。
+	ErrNoContext = errors.New("context was not found")
+
+	// ErrNotRegistered 表示模型未通过 Meta 注册。
+	ErrNotRegistered = errors.New("model was not registered")
+
+	// ErrNotWritable 表示上下文或模型为只读。
+	ErrNotWritable = errors.New("context or model was not writable")
+
+	// ErrNotFound 表示未找到符合条件的数据。
+	ErrNotFound = errors.New("data was not found")
+
+	// ErrInvalidCond 表示条件表达式的语法或参数错误。
+	ErrInvalidCond = errors.New("invalid condition")
+)
+
+// logContextError 记录上下文操作函数（如 Read、Write）的错误。
+// 上下文未找到或模型未注册时记录为严重错误并附带调用者信息，其他错误记录为一般错误。
+func logContextError(source string, err error) {
+	if errors.Is(err, ErrNoContext) || errors.Is(err, ErrNotRegistered) {
+		XLog.Critical("%v: %v: %v", source, err, XLog.Caller(2, false))
+	} else {
+		XLog.Error("%v: %v", source, err)
+	}
+}
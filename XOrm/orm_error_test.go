@@ -0,0 +1,142 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XOrm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/eframework-org/GO.UTIL/XObject"
+	"github.com/petermattis/goid"
+	"github.com/stretchr/testify/assert"
+)
+
+type testErrorModel struct {
+	Model[testErrorModel] `orm:"-" json:"-"`
+	ID                    int `orm:"column(id);pk"`
+}
+
+func (m *testErrorModel) AliasName() string { return "error_alias" }
+
+func (m *testErrorModel) TableName() string { return "error_table" }
+
+func TestOrmError(t *testing.T) {
+	defer orm.ResetModelCache()
+	orm.ResetModelCache()
+	model := XObject.New[TestSQLModel]()
+	model.ID = 1
+	Meta(model, false, true)
+
+	t.Run("NoContext", func(t *testing.T) {
+		_, err := ReadE(model)
+		assert.ErrorIs(t, err, ErrNoContext, "未开始监控时 ReadE 应当返回 ErrNoContext。")
+		rets, err := ListE(model)
+		assert.ErrorIs(t, err, ErrNoContext, "未开始监控时 ListE 应当返回 ErrNoContext。")
+		assert.Empty(t, rets, "发生错误时 ListE 应当返回空切片。")
+		assert.ErrorIs(t, WriteE(model), ErrNoContext, "未开始监控时 WriteE 应当返回 ErrNoContext。")
+		assert.ErrorIs(t, DeleteE(model), ErrNoContext, "未开始监控时 DeleteE 应当返回 ErrNoContext。")
+		assert.ErrorIs(t, ClearE(model), ErrNoContext, "未开始监控时 ClearE 应当返回 ErrNoContext。")
+		index, err := IncreE(model)
+		assert.ErrorIs(t, err, ErrNoContext, "未开始监控时 IncreE 应当返回 ErrNoContext。")
+		assert.Equal(t, int64(-1), index, "发生错误时 IncreE 应当返回 -1。")
+	})
+
+	t.Run("NotRegistered", func(t *testing.T) {
+		Watch()
+		defer Defer()
+
+		emodel := XObject.New[testErrorModel]()
+		_, err := ReadE(emodel)
+		assert.ErrorIs(t, err, ErrNotRegistered, "未注册的模型应当返回 ErrNotRegistered。")
+		assert.ErrorContains(t, err, emodel.ModelUnique(), "错误信息应当包含模型标识。")
+		assert.ErrorIs(t, WriteE(emodel), ErrNotRegistered, "未注册的模型应当返回 ErrNotRegistered。")
+	})
+
+	t.Run("NotWritable", func(t *testing.T) {
+		func() {
+			Watch(false)
+			defer Defer()
+			assert.ErrorIs(t, WriteE(model), ErrNotWritable, "只读上下文中 WriteE 应当返回 ErrNotWritable。")
+			assert.ErrorIs(t, DeleteE(model), ErrNotWritable, "只读上下文中 DeleteE 应当返回 ErrNotWritable。")
+			assert.ErrorIs(t, ClearE(model), ErrNotWritable, "只读上下文中 ClearE 应当返回 ErrNotWritable。")
+			_, err := IncreE(model)
+			assert.ErrorIs(t, err, ErrNotWritable, "只读上下文中 IncreE 应当返回 ErrNotWritable。")
+		}()
+
+		meta := getModelMeta(model)
+		meta.writable = false
+		defer func() { meta.writable = true }()
+		func() {
+			Watch()
+			defer Defer()
+			assert.ErrorIs(t, WriteE(model), ErrNotWritable, "只读模型的 WriteE 应当返回 ErrNotWritable。")
+		}()
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		Watch()
+		defer Defer()
+		defer sessionCacheMap.Delete(goid.Get()) // 丢弃会话内存，避免提交至远端
+
+		_, err := ReadE(model, "writable")
+		assert.Error(t, err, "参数类型错误时 ReadE 应当返回错误。")
+		assert.False(t, errors.Is(err, ErrNotFound), "参数类型错误不应当匹配 ErrNotFound。")
+
+		obj := XObject.New[TestSQLModel]()
+		obj.ID = 2
+		obj.Name = "name_2"
+		assert.NoError(t, WriteE(obj), "WriteE 不应当返回错误。")
+		isSessionListed(goid.Get(), model, true) // 避免远端读取
+		ret, err := ReadE(XObject.New[TestSQLModel](), Cond("name == {0}", "name_2"))
+		assert.NoError(t, err, "会话内存中存在的数据不应当返回错误。")
+		assert.Equal(t, 2, ret.ID, "读取的数据应当和预期相等。")
+
+		ret = Read(XObject.New[TestSQLModel](), "writable", Cond("name == {0}", "name_2"))
+		assert.Equal(t, 2, ret.ID, "参数类型错误时 Read 应当忽略该参数并继续读取。")
+		assert.Len(t, List(model, "writable", Cond("name == {0}", "name_2")), 1, "参数类型错误时 List 应当忽略该参数并继续列举。")
+		rets, err := ListE(model, "writable", Cond("name == {0}", "name_2"))
+		assert.Error(t, err, "参数类型错误时 ListE 应当返回错误。")
+		assert.Empty(t, rets, "发生错误时 ListE 应当返回空切片。")
+
+		assert.NoError(t, DeleteE(obj), "DeleteE 不应当返回错误。")
+		_, err = ReadE(obj)
+		assert.ErrorIs(t, err, ErrNotFound, "已被标记删除的数据应当返回 ErrNotFound。")
+		_, err = ReadE(XObject.New[TestSQLModel](), Cond("name == {0}", "name_3"))
+		assert.ErrorIs(t, err, ErrNotFound, "未找到数据时应当返回 ErrNotFound。")
+	})
+
+	t.Run("IModelE", func(t *testing.T) {
+		var imodel IModel = model
+		_, ok := imodel.(IModelE)
+		assert.True(t, ok, "Model 应当实现 IModelE。")
+		imodel = struct{ IModel }{model} // 自行实现 IModel 的类型无需实现 IModelE
+		_, ok = imodel.(IModelE)
+		assert.False(t, ok, "仅实现 IModel 的类型不应当实现 IModelE。")
+	})
+
+	t.Run("CondE", func(t *testing.T) {
+		cond, err := CondE("id > {0} && limit = {1}", 1, 10)
+		assert.NoError(t, err, "正确的条件不应当返回错误。")
+		assert.Equal(t, 10, cond.Limit, "创建的条件应当和预期相等。")
+
+		cond, err = CondE("((id > {0})", 1)
+		assert.ErrorIs(t, err, ErrInvalidCond, "语法错误应当返回 ErrInvalidCond。")
+		assert.Nil(t, cond, "发生错误时应当返回 nil。")
+
+		_, err = CondE(1)
+		assert.ErrorIs(t, err, ErrInvalidCond, "参数类型错误应当返回 ErrInvalidCond。")
+
+		_, err = CondE("id > {0} && limit = {1}", 1, "10")
+		assert.ErrorIs(t, err, ErrInvalidCond, "分页参数类型错误应当返回 ErrInvalidCond。")
+		assert.ErrorContains(t, err, "limit must be int", "错误信息应当包含参数校验的原因。")
+
+		assert.ErrorIs(t, condError("XOrm.Cond: invalid expression"), ErrInvalidCond, "条件解析的 panic 应当转换为 ErrInvalidCond。")
+		assert.Panics(t, func() { condError(errors.New("runtime error")) }, "非条件解析的 panic 应当重新抛出。")
+
+		_, err = CondFor[*TestSQLModel]("level > {0}", 1)
+		assert.ErrorIs(t, err, ErrInvalidCond, "校验错误应当返回 ErrInvalidCond。")
+	})
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// CodecJSON 是 JSON 编解码器的名称，编码规则与 XOrm 数据模型的 Json 函数一致。
+	CodecJSON = "json"
+
+	// CodecMsgpack 是 MessagePack 编解码器的名称，编码结果较 JSON 更为紧凑。
+	CodecMsgpack = "msgpack"
+
+	// CodecRaw 是原始字节编解码器的名称，仅支持字符串及字节切片，且不添加信封。
+	CodecRaw = "raw"
+
+	// DefaultCodec 是未指定编解码器时使用的默认编解码器。
+	DefaultCodec = CodecJSON
+)
+
+// codecMagic 是编码信封的标识，用于区分带信封的值及历史的原始值。
+const codecMagic = "\x00XP"
+
+// codecEnvelope 是当前编码信封的格式版本。
+const codecEnvelope byte = 1
+
+// ICodec 是键值的编解码器接口，可以通过 RegisterCodec 注册自定义的实现。
+type ICodec interface {
+	// Name 返回编解码器的名称，写入编码信封中用于解码。
+	Name() string
+
+	// Marshal 将对象编码为字节数组。
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal 将字节数组解码至 v 指向的对象。
+	Unmarshal(data []byte, v any) error
+}
+
+// codecMap 存储已注册的编解码器，键为名称。
+var codecMap sync.Map
+
+// codecAliasMap 存储别名使用的编解码器名称，键为存储别名。
+var codecAliasMap sync.Map
+
+func init() {
+	RegisterCodec(jsonCodec{})
+	RegisterCodec(msgpackCodec{})
+	RegisterCodec(rawCodec{})
+}
+
+// RegisterCodec 注册编解码器，名称相同时替换原有的编解码器。
+func RegisterCodec(codec ICodec) {
+	codecMap.Store(codec.Name(), codec)
+}
+
+// CodecOf 返回指定名称的编解码器，未注册时返回 nil。
+func CodecOf(name string) ICodec {
+	if val, ok := codecMap.Load(name); ok {
+		return val.(ICodec)
+	}
+	return nil
+}
+
+// UseCodec 设置存储别名默认使用的编解码器，编解码器未注册时返回 ErrNotSupported。
+// 也可以通过首选项 Pairs/Source/<存储类型>/<存储别名> 的 Codec 参数设置。
+func UseCodec(alias, name string) error {
+	if CodecOf(name) == nil {
+		return fmt.Errorf("%w: codec %v", ErrNotSupported, name)
+	}
+	codecAliasMap.Store(alias, name)
+	return nil
+}
+
+// Encode 使用指定的编解码器将对象编码为字符串，未指定时使用 DefaultCodec。
+// 除 CodecRaw 外，编码结果包含版本化的信封（标识、格式版本及编解码器名称），用于解码时选择编解码器。
+func Encode(value any, codec ...string) (string, error) {
+	c, err := codecFor("", codec...)
+	if err != nil {
+		return "", err
+	}
+	data, err := c.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	if c.Name() == CodecRaw {
+		return string(data), nil
+	}
+	name := c.Name()
+	if len(name) > 0xff {
+		return "", fmt.Errorf("codec name %v is too long", name)
+	}
+	var buf bytes.Buffer
+	buf.Grow(len(codecMagic) + 2 + len(name) + len(data))
+	buf.WriteString(codecMagic)
+	buf.WriteByte(codecEnvelope)
+	buf.WriteByte(byte(len(name)))
+	buf.WriteString(name)
+	buf.Write(data)
+	return buf.String(), nil
+}
+
+// Decode 将字符串解码至 value 指向的对象，未指定编解码器时使用 DefaultCodec。
+// 若数据包含编码信封，则使用信封中记录的编解码器解码，以便在切换编解码器后仍能读取历史数据；
+// 否则（如迁移前写入的值）使用指定的编解码器解码，CodecRaw 总是按原始字节解码。
+func Decode(data string, value any, codec ...string) error {
+	c, err := codecFor("", codec...)
+	if err != nil {
+		return err
+	}
+	return decodeWith(c, data, value)
+}
+
+// Get 读取指定别名的存储中键的值，并解码为 T 类型。
+// codec 为本次调用使用的编解码器，未指定时依次使用 UseCodec 设置的别名编解码器及 DefaultCodec。
+// 别名未注册时返回 ErrNotRegistered，键不存在时返回 ErrNotFound。
+func Get[T any](alias, key string, codec ...string) (T, error) {
+	var value T
+	pairs, c, err := pairsFor(alias, codec...)
+	if err != nil {
+		return value, err
+	}
+	data, err := pairs.Get(key)
+	if err != nil {
+		return value, err
+	}
+	err = decodeWith(c, data, &value)
+	return value, err
+}
+
+// Set 将 T 类型的值编码后写入指定别名的存储，ttl 为过期时间，编解码器的选择规则与 Get 相同。
+func Set[T any](alias, key string, value T, ttl time.Duration, codec ...string) error {
+	pairs, c, err := pairsFor(alias, codec...)
+	if err != nil {
+		return err
+	}
+	data, err := Encode(value, c.Name())
+	if err != nil {
+		return err
+	}
+	return pairs.Set(key, data, ttl)
+}
+
+// pairsFor 返回别名对应的存储及本次调用使用的编解码器。
+func pairsFor(alias string, codec ...string) (IPairs, ICodec, error) {
+	pairs := Of(alias)
+	if pairs == nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrNotRegistered, alias)
+	}
+	c, err := codecFor(alias, codec...)
+	return pairs, c, err
+}
+
+// codecFor 按照调用参数、别名设置及默认值的顺序选择编解码器。
+func codecFor(alias string, codec ...string) (ICodec, error) {
+	name := DefaultCodec
+	if len(codec) > 0 && codec[0] != "" {
+		name = codec[0]
+	} else if val, ok := codecAliasMap.Load(alias); ok && alias != "" {
+		name = val.(string)
+	}
+	if c := CodecOf(name); c != nil {
+		return c, nil
+	}
+	return nil, fmt.Errorf("%w: codec %v", ErrNotSupported, name)
+}
+
+// decodeWith 解析编码信封并解码数据，无信封时使用 c 解码。
+func decodeWith(c ICodec, data string, value any) error {
+	if c.Name() == CodecRaw || len(data) < len(codecMagic)+2 || data[:len(codecMagic)] != codecMagic {
+		return c.Unmarshal([]byte(data), value)
+	}
+	offset := len(codecMagic)
+	if version := data[offset]; version != codecEnvelope {
+		return fmt.Errorf("%w: codec envelope version %v", ErrNotSupported, version)
+	}
+	size := int(data[offset+1])
+	offset += 2
+	if len(data) < offset+size {
+		return fmt.Errorf("invalid codec envelope")
+	}
+	name := data[offset : offset+size]
+	if c = CodecOf(name); c == nil {
+		return fmt.Errorf("%w: codec %v", ErrNotSupported, name)
+	}
+	return c.Unmarshal([]byte(data[offset+size:]), value)
+}
+
+// jsonCodec 是 JSON 编解码器，与 XObject.ToJson 相同不转义 HTML 字符，但字符串类型亦编码为 JSON 字符串。
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return CodecJSON }
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// rawCodec 是原始字节编解码器，仅支持字符串及字节切片。
+type rawCodec struct{}
+
+func (rawCodec) Name() string { return CodecRaw }
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	switch value := v.(type) {
+	case string:
+		return []byte(value), nil
+	case []byte:
+		return value, nil
+	case *string:
+		if value != nil {
+			return []byte(*value), nil
+		}
+	case *[]byte:
+		if value != nil {
+			return *value, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: raw codec of %T", ErrNotSupported, v)
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	switch value := v.(type) {
+	case *string:
+		*value = string(data)
+	case *[]byte:
+		*value = append([]byte(nil), data...)
+	default:
+		return fmt.Errorf("%w: raw codec of %T", ErrNotSupported, v)
+	}
+	return nil
+}
@@ -0,0 +1,710 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// msgpackCodec 是 MessagePack 编解码器。
+// 结构体编码为以字段名为键的映射，字段名及 omitempty 的规则与 JSON 的标签相同，匿名的结构体字段将被展开；
+// time.Time 编码为 MessagePack 的时间戳扩展类型；解码至 any 时，映射的键均为字符串时转换为 map[string]any，否则转换为 map[any]any。
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string { return CodecMsgpack }
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	return msgpackEncode(nil, reflect.ValueOf(v))
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("msgpack: unmarshal of non-pointer %T", v)
+	}
+	reader := &msgpackReader{data: data}
+	node, err := reader.read()
+	if err != nil {
+		return err
+	}
+	if reader.pos != len(data) {
+		return fmt.Errorf("msgpack: %v bytes remained after value", len(data)-reader.pos)
+	}
+	return msgpackAssign(rv.Elem(), node)
+}
+
+// msgpackTimeType 是 time.Time 的反射类型。
+var msgpackTimeType = reflect.TypeOf(time.Time{})
+
+// msgpackField 是结构体字段的编码信息。
+type msgpackField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// msgpackFieldMap 缓存结构体类型的字段信息。
+var msgpackFieldMap sync.Map
+
+// msgpackFields 返回结构体类型的可编码字段，规则与 encoding/json 相同。
+func msgpackFields(t reflect.Type) []msgpackField {
+	if val, ok := msgpackFieldMap.Load(t); ok {
+		return val.([]msgpackField)
+	}
+	fields := make([]msgpackField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			for _, field := range msgpackFields(sf.Type) {
+				field.index = append([]int{i}, field.index...)
+				fields = append(fields, field)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, msgpackField{name: name, index: []int{i}, omitEmpty: strings.Contains(opts, "omitempty")})
+	}
+	msgpackFieldMap.Store(t, fields)
+	return fields
+}
+
+// msgpackEncode 将反射值编码并追加至 buf。
+func msgpackEncode(buf []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return append(buf, 0xc0), nil
+	}
+	if v.Type() == msgpackTimeType {
+		t := v.Interface().(time.Time)
+		buf = append(buf, 0xc7, 12, 0xff)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(t.Nanosecond()))
+		return binary.BigEndian.AppendUint64(buf, uint64(t.Unix())), nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return append(buf, 0xc0), nil
+		}
+		return msgpackEncode(buf, v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 0xc3), nil
+		}
+		return append(buf, 0xc2), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return msgpackAppendInt(buf, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return msgpackAppendUint(buf, v.Uint()), nil
+	case reflect.Float32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xca), math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcb), math.Float64bits(v.Float())), nil
+	case reflect.String:
+		return msgpackAppendString(buf, v.String()), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return append(buf, 0xc0), nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return msgpackAppendBytes(buf, v.Bytes()), nil
+		}
+		fallthrough
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			return msgpackAppendBytes(buf, data), nil
+		}
+		buf = msgpackAppendHeader(buf, v.Len(), 0x90, 0xdc)
+		var err error
+		for i := 0; i < v.Len(); i++ {
+			if buf, err = msgpackEncode(buf, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return append(buf, 0xc0), nil
+		}
+		// 按照编码后的键排序，使相同的映射编码结果一致
+		pairs := make([][2][]byte, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := msgpackEncode(nil, iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			value, err := msgpackEncode(nil, iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, [2][]byte{key, value})
+		}
+		sort.Slice(pairs, func(i, j int) bool { return bytes.Compare(pairs[i][0], pairs[j][0]) < 0 })
+		buf = msgpackAppendHeader(buf, len(pairs), 0x80, 0xde)
+		for _, pair := range pairs {
+			buf = append(append(buf, pair[0]...), pair[1]...)
+		}
+		return buf, nil
+	case reflect.Struct:
+		fields := msgpackFields(v.Type())
+		values := make([]reflect.Value, 0, len(fields))
+		names := make([]string, 0, len(fields))
+		for _, field := range fields {
+			fv := v.FieldByIndex(field.index)
+			if field.omitEmpty && msgpackEmpty(fv) {
+				continue
+			}
+			values = append(values, fv)
+			names = append(names, field.name)
+		}
+		buf = msgpackAppendHeader(buf, len(values), 0x80, 0xde)
+		var err error
+		for i, fv := range values {
+			buf = msgpackAppendString(buf, names[i])
+			if buf, err = msgpackEncode(buf, fv); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type %v", v.Type())
+}
+
+// msgpackEmpty 判断字段是否为空值，规则与 encoding/json 的 omitempty 相同。
+func msgpackEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// msgpackAppendInt 追加有符号整数，使用最紧凑的格式。
+func msgpackAppendInt(buf []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return msgpackAppendUint(buf, uint64(i))
+	case i >= -32:
+		return append(buf, byte(int8(i)))
+	case i >= math.MinInt8:
+		return append(buf, 0xd0, byte(int8(i)))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(int16(i)))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(int32(i)))
+	}
+	return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(i))
+}
+
+// msgpackAppendUint 追加无符号整数，使用最紧凑的格式。
+func msgpackAppendUint(buf []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(buf, byte(u))
+	case u <= math.MaxUint8:
+		return append(buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(u))
+	}
+	return binary.BigEndian.AppendUint64(append(buf, 0xcf), u)
+}
+
+// msgpackAppendString 追加字符串。
+func msgpackAppendString(buf []byte, s string) []byte {
+	switch n := len(s); {
+	case n <= 31:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xda), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+// msgpackAppendBytes 追加二进制数据。
+func msgpackAppendBytes(buf []byte, b []byte) []byte {
+	switch n := len(b); {
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xc5), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xc6), uint32(n))
+	}
+	return append(buf, b...)
+}
+
+// msgpackAppendHeader 追加数组或映射的长度头，fix 为短格式的前缀，wide 为 16 位格式的前缀（32 位格式为 wide+1）。
+func msgpackAppendHeader(buf []byte, n int, fix, wide byte) []byte {
+	switch {
+	case n <= 15:
+		return append(buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, wide), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(buf, wide+1), uint32(n))
+}
+
+// msgpackMap 是解码后的映射，保留键值对的顺序及任意类型的键。
+type msgpackMap []msgpackPair
+
+// msgpackPair 是映射的键值对。
+type msgpackPair struct {
+	key   any
+	value any
+}
+
+// msgpackExt 是未识别的扩展类型。
+type msgpackExt struct {
+	typ  int8
+	data []byte
+}
+
+// msgpackReader 将 MessagePack 数据解析为中间值：nil、bool、int64、uint64、float32、float64、
+// string、[]byte、[]any、msgpackMap、time.Time 及 msgpackExt。
+type msgpackReader struct {
+	data []byte
+	pos  int
+}
+
+// next 读取 n 个字节。
+func (r *msgpackReader) next(n int) ([]byte, error) {
+	if n < 0 || len(r.data)-r.pos < n {
+		return nil, fmt.Errorf("msgpack: unexpected end of data")
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// size 读取 n 个字节的大端长度。
+func (r *msgpackReader) size(n int) (int, error) {
+	b, err := r.next(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return int(b[0]), nil
+	case 2:
+		return int(binary.BigEndian.Uint16(b)), nil
+	}
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+// read 读取一个值。
+func (r *msgpackReader) read() (any, error) {
+	b, err := r.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return r.readMap(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return r.readArray(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return r.readString(int(c & 0x1f))
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := r.size(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		data, err := r.next(n)
+		return append([]byte(nil), data...), err
+	case 0xc7, 0xc8, 0xc9:
+		n, err := r.size(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return r.readExt(n)
+	case 0xca:
+		b, err := r.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), nil
+	case 0xcb:
+		b, err := r.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		b, err := r.next(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		var u uint64
+		for _, v := range b {
+			u = u<<8 | uint64(v)
+		}
+		return u, nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (c - 0xd0)
+		b, err := r.next(n)
+		if err != nil {
+			return nil, err
+		}
+		var u uint64
+		for _, v := range b {
+			u = u<<8 | uint64(v)
+		}
+		shift := 64 - 8*n
+		return int64(u<<shift) >> shift, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return r.readExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := r.size(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return r.readString(n)
+	case 0xdc, 0xdd:
+		n, err := r.size(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return r.readArray(n)
+	case 0xde, 0xdf:
+		n, err := r.size(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return r.readMap(n)
+	}
+	return nil, fmt.Errorf("msgpack: invalid format 0x%x", c)
+}
+
+// readString 读取长度为 n 的字符串。
+func (r *msgpackReader) readString(n int) (any, error) {
+	b, err := r.next(n)
+	return string(b), err
+}
+
+// readArray 读取长度为 n 的数组。
+func (r *msgpackReader) readArray(n int) (any, error) {
+	if n > len(r.data)-r.pos {
+		return nil, fmt.Errorf("msgpack: unexpected end of data")
+	}
+	rets := make([]any, n)
+	for i := range rets {
+		var err error
+		if rets[i], err = r.read(); err != nil {
+			return nil, err
+		}
+	}
+	return rets, nil
+}
+
+// readMap 读取长度为 n 的映射。
+func (r *msgpackReader) readMap(n int) (any, error) {
+	if n > (len(r.data)-r.pos)/2 {
+		return nil, fmt.Errorf("msgpack: unexpected end of data")
+	}
+	rets := make(msgpackMap, n)
+	for i := range rets {
+		var err error
+		if rets[i].key, err = r.read(); err != nil {
+			return nil, err
+		}
+		if rets[i].value, err = r.read(); err != nil {
+			return nil, err
+		}
+	}
+	return rets, nil
+}
+
+// readExt 读取长度为 n 的扩展类型，类型 -1 解析为 time.Time。
+func (r *msgpackReader) readExt(n int) (any, error) {
+	b, err := r.next(1)
+	if err != nil {
+		return nil, err
+	}
+	typ := int8(b[0])
+	data, err := r.next(n)
+	if err != nil {
+		return nil, err
+	}
+	if typ != -1 {
+		return msgpackExt{typ: typ, data: append([]byte(nil), data...)}, nil
+	}
+	var t time.Time
+	switch n {
+	case 4:
+		t = time.Unix(int64(binary.BigEndian.Uint32(data)), 0)
+	case 8:
+		u := binary.BigEndian.Uint64(data)
+		t = time.Unix(int64(u&0x3ffffffff), int64(u>>34))
+	case 12:
+		t = time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data[:4])))
+	default:
+		return nil, fmt.Errorf("msgpack: invalid timestamp length %v", n)
+	}
+	if t.IsZero() { // 零值的时间解码为 time.Time{}，与编码前保持一致
+		return time.Time{}, nil
+	}
+	return t, nil
+}
+
+// msgpackAssign 将中间值赋值给反射值。
+func msgpackAssign(v reflect.Value, node any) error {
+	if node == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return msgpackAssign(v.Elem(), node)
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			break
+		}
+		v.Set(reflect.ValueOf(msgpackPlain(node)))
+		return nil
+	}
+	if v.Type() == msgpackTimeType {
+		if t, ok := node.(time.Time); ok {
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return msgpackMismatch(node, v.Type())
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if b, ok := node.(bool); ok {
+			v.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch n := node.(type) {
+		case int64:
+			i = n
+		case uint64:
+			if n > math.MaxInt64 {
+				return fmt.Errorf("msgpack: %v overflows %v", n, v.Type())
+			}
+			i = int64(n)
+		default:
+			return msgpackMismatch(node, v.Type())
+		}
+		if v.OverflowInt(i) {
+			return fmt.Errorf("msgpack: %v overflows %v", i, v.Type())
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		switch n := node.(type) {
+		case uint64:
+			u = n
+		case int64:
+			if n < 0 {
+				return fmt.Errorf("msgpack: %v overflows %v", n, v.Type())
+			}
+			u = uint64(n)
+		default:
+			return msgpackMismatch(node, v.Type())
+		}
+		if v.OverflowUint(u) {
+			return fmt.Errorf("msgpack: %v overflows %v", u, v.Type())
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch n := node.(type) {
+		case float32:
+			v.SetFloat(float64(n))
+		case float64:
+			v.SetFloat(n)
+		case int64:
+			v.SetFloat(float64(n))
+		case uint64:
+			v.SetFloat(float64(n))
+		default:
+			return msgpackMismatch(node, v.Type())
+		}
+		return nil
+	case reflect.String:
+		switch n := node.(type) {
+		case string:
+			v.SetString(n)
+			return nil
+		case []byte:
+			v.SetString(string(n))
+			return nil
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			switch n := node.(type) {
+			case []byte:
+				v.SetBytes(append([]byte(nil), n...))
+				return nil
+			case string:
+				v.SetBytes([]byte(n))
+				return nil
+			}
+		}
+		if items, ok := node.([]any); ok {
+			slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+			for i, item := range items {
+				if err := msgpackAssign(slice.Index(i), item); err != nil {
+					return err
+				}
+			}
+			v.Set(slice)
+			return nil
+		}
+	case reflect.Array:
+		var items []any
+		switch n := node.(type) {
+		case []any:
+			items = n
+		case []byte:
+			for _, b := range n {
+				items = append(items, uint64(b))
+			}
+		default:
+			return msgpackMismatch(node, v.Type())
+		}
+		v.Set(reflect.Zero(v.Type()))
+		for i := 0; i < len(items) && i < v.Len(); i++ {
+			if err := msgpackAssign(v.Index(i), items[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if pairs, ok := node.(msgpackMap); ok {
+			m := reflect.MakeMapWithSize(v.Type(), len(pairs))
+			for _, pair := range pairs {
+				key := reflect.New(v.Type().Key()).Elem()
+				if err := msgpackAssign(key, pair.key); err != nil {
+					return err
+				}
+				value := reflect.New(v.Type().Elem()).Elem()
+				if err := msgpackAssign(value, pair.value); err != nil {
+					return err
+				}
+				m.SetMapIndex(key, value)
+			}
+			v.Set(m)
+			return nil
+		}
+	case reflect.Struct:
+		if pairs, ok := node.(msgpackMap); ok {
+			fields := msgpackFields(v.Type())
+			for _, pair := range pairs {
+				name, ok := pair.key.(string)
+				if !ok {
+					continue
+				}
+				var found *msgpackField
+				for i := range fields {
+					if fields[i].name == name {
+						found = &fields[i]
+						break
+					} else if found == nil && strings.EqualFold(fields[i].name, name) {
+						found = &fields[i]
+					}
+				}
+				if found == nil {
+					continue
+				}
+				if err := msgpackAssign(v.FieldByIndex(found.index), pair.value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return msgpackMismatch(node, v.Type())
+}
+
+// msgpackMismatch 返回类型不匹配的错误。
+func msgpackMismatch(node any, t reflect.Type) error {
+	return fmt.Errorf("msgpack: cannot unmarshal %T into %v", node, t)
+}
+
+// msgpackPlain 将中间值转换为通用类型，映射的键均为字符串时转换为 map[string]any，否则转换为 map[any]any。
+func msgpackPlain(node any) any {
+	switch n := node.(type) {
+	case []any:
+		rets := make([]any, len(n))
+		for i, item := range n {
+			rets[i] = msgpackPlain(item)
+		}
+		return rets
+	case msgpackMap:
+		strs := make(map[string]any, len(n))
+		for _, pair := range n {
+			key, ok := pair.key.(string)
+			if !ok {
+				anys := make(map[any]any, len(n))
+				for _, pair := range n {
+					key := msgpackPlain(pair.key)
+					if b, ok := key.([]byte); ok {
+						key = string(b)
+					} else if key != nil && !reflect.TypeOf(key).Comparable() {
+						key = fmt.Sprint(key)
+					}
+					anys[key] = msgpackPlain(pair.value)
+				}
+				return anys
+			}
+			strs[key] = msgpackPlain(pair.value)
+		}
+		return strs
+	case msgpackExt:
+		return n.data
+	}
+	return node
+}
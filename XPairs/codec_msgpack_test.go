@@ -0,0 +1,149 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMsgpackBase 是测试匿名字段展开的基础结构体。
+type testMsgpackBase struct {
+	ID int64 `json:"id"`
+}
+
+// testMsgpackTicket 是编解码测试使用的匹配票据。
+type testMsgpackTicket struct {
+	testMsgpackBase
+	Mode     string         `json:"mode"`
+	Players  []int          `json:"players"`
+	Score    float64        `json:"score"`
+	Ratio    float32        `json:"ratio"`
+	Ready    bool           `json:"ready"`
+	Created  time.Time      `json:"created"`
+	Extra    map[string]any `json:"extra,omitempty"`
+	Parent   *testMsgpackTicket
+	Payload  []byte `json:"payload"`
+	Ignored  string `json:"-"`
+	internal string
+}
+
+func TestMsgpack(t *testing.T) {
+	codec := CodecOf(CodecMsgpack)
+
+	t.Run("Format", func(t *testing.T) {
+		tests := []struct {
+			value any
+			data  string
+		}{
+			{nil, "\xc0"},
+			{true, "\xc3"},
+			{false, "\xc2"},
+			{1, "\x01"},
+			{-1, "\xff"},
+			{-33, "\xd0\xdf"},
+			{200, "\xcc\xc8"},
+			{-200, "\xd1\xff\x38"},
+			{70000, "\xce\x00\x01\x11\x70"},
+			{int64(math.MinInt64), "\xd3\x80\x00\x00\x00\x00\x00\x00\x00"},
+			{uint64(math.MaxUint64), "\xcf\xff\xff\xff\xff\xff\xff\xff\xff"},
+			{float32(1.5), "\xca\x3f\xc0\x00\x00"},
+			{1.5, "\xcb\x3f\xf8\x00\x00\x00\x00\x00\x00"},
+			{"abc", "\xa3abc"},
+			{strings.Repeat("a", 32), "\xd9\x20" + strings.Repeat("a", 32)},
+			{[]byte{1, 2}, "\xc4\x02\x01\x02"},
+			{[]int{1, 2}, "\x92\x01\x02"},
+			{map[string]int{"b": 2, "a": 1}, "\x82\xa1a\x01\xa1b\x02"},
+			{time.Unix(1, 2), "\xc7\x0c\xff\x00\x00\x00\x02\x00\x00\x00\x00\x00\x00\x00\x01"},
+		}
+		for _, test := range tests {
+			data, err := codec.Marshal(test.value)
+			assert.NoError(t, err, "Marshal 不应当返回错误：%v", test.value)
+			assert.Equal(t, test.data, string(data), "编码结果应当符合 MessagePack 规范：%v", test.value)
+		}
+	})
+
+	t.Run("Struct", func(t *testing.T) {
+		ticket := testMsgpackTicket{
+			testMsgpackBase: testMsgpackBase{ID: 100},
+			Mode:            "rank",
+			Players:         []int{1, 2, 3},
+			Score:           1.25,
+			Ratio:           0.5,
+			Ready:           true,
+			Created:         time.Unix(1700000000, 123).UTC(),
+			Extra:           map[string]any{"region": "cn", "retry": int64(2)},
+			Parent:          &testMsgpackTicket{Mode: "casual"},
+			Payload:         []byte{0, 1, 2},
+			Ignored:         "ignored",
+			internal:        "internal",
+		}
+		data, err := codec.Marshal(ticket)
+		assert.NoError(t, err, "Marshal 不应当返回错误。")
+		assert.NotContains(t, string(data), "ignored", "标签为 - 的字段不应当被编码。")
+		assert.NotContains(t, string(data), "internal", "未导出的字段不应当被编码。")
+
+		var ret testMsgpackTicket
+		assert.NoError(t, codec.Unmarshal(data, &ret), "Unmarshal 不应当返回错误。")
+		assert.Equal(t, int64(100), ret.ID, "匿名字段应当被展开。")
+		assert.True(t, ticket.Created.Equal(ret.Created), "时间戳应当被完整地编解码。")
+		ret.Created = ticket.Created
+		ticket.Ignored, ticket.internal = "", ""
+		assert.Equal(t, ticket, ret, "解码的对象应当和编码的对象相等。")
+
+		ticket = testMsgpackTicket{}
+		data, _ = codec.Marshal(ticket)
+		assert.NotContains(t, string(data), "extra", "omitempty 的空字段不应当被编码。")
+
+		var plain any
+		data, _ = codec.Marshal(map[string]any{"Mode": "rank", "list": []any{int64(1), "a"}})
+		assert.NoError(t, codec.Unmarshal(data, &plain), "解码至 any 不应当返回错误。")
+		assert.Equal(t, map[string]any{"Mode": "rank", "list": []any{int64(1), "a"}}, plain, "解码至 any 的值应当和预期相等。")
+		data, _ = codec.Marshal(map[int]string{1: "a"})
+		assert.NoError(t, codec.Unmarshal(data, &plain), "解码至 any 不应当返回错误。")
+		assert.Equal(t, map[any]any{int64(1): "a"}, plain, "非字符串键的映射应当解码为 map[any]any。")
+
+		var mode struct{ MODE string }
+		data, _ = codec.Marshal(map[string]string{"mode": "rank"})
+		assert.NoError(t, codec.Unmarshal(data, &mode), "Unmarshal 不应当返回错误。")
+		assert.Equal(t, "rank", mode.MODE, "字段名应当不区分大小写地匹配。")
+	})
+
+	t.Run("Number", func(t *testing.T) {
+		for _, value := range []int64{0, 127, 128, 255, 256, 65535, 65536, math.MaxInt32, math.MaxInt64, -1, -32, -33, -128, -129, -32768, -32769, math.MinInt32, math.MinInt64} {
+			data, _ := codec.Marshal(value)
+			var ret int64
+			assert.NoError(t, codec.Unmarshal(data, &ret), "Unmarshal 不应当返回错误：%v", value)
+			assert.Equal(t, value, ret, "整数应当被完整地编解码：%v", value)
+		}
+
+		data, _ := codec.Marshal(300)
+		var i8 int8
+		assert.Error(t, codec.Unmarshal(data, &i8), "溢出的整数应当返回错误。")
+		data, _ = codec.Marshal(-1)
+		var u uint
+		assert.Error(t, codec.Unmarshal(data, &u), "负数解码为无符号整数应当返回错误。")
+		var f float64
+		assert.NoError(t, codec.Unmarshal(data, &f), "整数解码为浮点数不应当返回错误。")
+		assert.Equal(t, -1.0, f, "整数解码为浮点数的值应当和预期相等。")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := codec.Marshal(make(chan int))
+		assert.Error(t, err, "不支持的类型应当返回错误。")
+
+		var ret testMsgpackTicket
+		assert.Error(t, codec.Unmarshal([]byte{0x92, 0x01}, &ret), "不完整的数据应当返回错误。")
+		assert.Error(t, codec.Unmarshal([]byte{0xdd, 0xff, 0xff, 0xff, 0xff}, &ret), "长度超出数据的数组应当返回错误。")
+		assert.Error(t, codec.Unmarshal([]byte{0xc1}, &ret), "无效的格式应当返回错误。")
+		assert.Error(t, codec.Unmarshal([]byte{0x01, 0x02}, new(int)), "多余的数据应当返回错误。")
+		assert.Error(t, codec.Unmarshal([]byte{0xa1, 'a'}, &ret), "类型不匹配时应当返回错误。")
+		assert.Error(t, codec.Unmarshal([]byte{0x01}, ret), "解码至非指针应当返回错误。")
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCodecPlayer 是编解码测试使用的玩家快照。
+type testCodecPlayer struct {
+	ID    int               `json:"id"`
+	Name  string            `json:"name"`
+	Level int               `json:"level,omitempty"`
+	Tags  []string          `json:"tags"`
+	Attrs map[string]string `json:"attrs"`
+}
+
+// testUpperCodec 是测试使用的自定义编解码器，将字符串转换为大写。
+type testUpperCodec struct{}
+
+func (testUpperCodec) Name() string { return "upper" }
+
+func (testUpperCodec) Marshal(v any) ([]byte, error) {
+	return []byte(strings.ToUpper(v.(string))), nil
+}
+
+func (testUpperCodec) Unmarshal(data []byte, v any) error {
+	*(v.(*string)) = string(data)
+	return nil
+}
+
+func TestCodec(t *testing.T) {
+	player := testCodecPlayer{ID: 1, Name: "<Alice>", Tags: []string{"vip"}, Attrs: map[string]string{"city": "A"}}
+
+	t.Run("Encode", func(t *testing.T) {
+		for _, codec := range []string{"", CodecJSON, CodecMsgpack} {
+			data, err := Encode(player, codec)
+			assert.NoError(t, err, "Encode 不应当返回错误：%v", codec)
+			assert.True(t, strings.HasPrefix(data, codecMagic), "编码结果应当包含信封：%v", codec)
+
+			var ret testCodecPlayer
+			assert.NoError(t, Decode(data, &ret, codec), "Decode 不应当返回错误：%v", codec)
+			assert.Equal(t, player, ret, "解码的对象应当和编码的对象相等：%v", codec)
+		}
+
+		data, _ := Encode(player)
+		assert.Contains(t, data, `"name":"<Alice>"`, "JSON 编码不应当转义 HTML 字符。")
+		data, _ = Encode("text")
+		assert.Equal(t, codecMagic+"\x01\x04json"+`"text"`, data, "JSON 编码的字符串应当为 JSON 字符串。")
+
+		data, err := Encode("text", CodecRaw)
+		assert.NoError(t, err, "原始字节编码不应当返回错误。")
+		assert.Equal(t, "text", data, "原始字节编码不应当添加信封。")
+		var str string
+		assert.NoError(t, Decode(data, &str, CodecRaw), "原始字节解码不应当返回错误。")
+		assert.Equal(t, "text", str, "原始字节解码的值应当和预期相等。")
+		var buf []byte
+		assert.NoError(t, Decode("bytes", &buf, CodecRaw), "原始字节解码不应当返回错误。")
+		assert.Equal(t, []byte("bytes"), buf, "原始字节解码的值应当和预期相等。")
+
+		_, err = Encode(player, CodecRaw)
+		assert.ErrorIs(t, err, ErrNotSupported, "原始字节编码不支持的类型应当返回 ErrNotSupported。")
+		_, err = Encode(player, "missing")
+		assert.ErrorIs(t, err, ErrNotSupported, "未注册的编解码器应当返回 ErrNotSupported。")
+	})
+
+	t.Run("Migrate", func(t *testing.T) {
+		// 信封中记录了编解码器，切换编解码器后仍能读取历史数据
+		data, _ := Encode(player, CodecMsgpack)
+		var ret testCodecPlayer
+		assert.NoError(t, Decode(data, &ret, CodecJSON), "带信封的数据应当使用信封中的编解码器解码。")
+		assert.Equal(t, player, ret, "解码的对象应当和编码的对象相等。")
+
+		// 无信封的历史数据使用指定的编解码器解码
+		ret = testCodecPlayer{}
+		assert.NoError(t, Decode(`{"id":2,"name":"Bob"}`, &ret, CodecJSON), "无信封的数据应当使用指定的编解码器解码。")
+		assert.Equal(t, 2, ret.ID, "解码的对象应当和预期相等。")
+
+		err := Decode(codecMagic+"\x02\x04json{}", &ret)
+		assert.ErrorIs(t, err, ErrNotSupported, "未知的信封版本应当返回 ErrNotSupported。")
+		err = Decode(codecMagic+"\x01\x07missing{}", &ret)
+		assert.ErrorIs(t, err, ErrNotSupported, "信封中未注册的编解码器应当返回 ErrNotSupported。")
+		assert.Error(t, Decode(codecMagic+"\x01\x09json", &ret), "不完整的信封应当返回错误。")
+	})
+
+	t.Run("Register", func(t *testing.T) {
+		RegisterCodec(testUpperCodec{})
+		defer codecMap.Delete("upper")
+		assert.NotNil(t, CodecOf("upper"), "注册的编解码器应当可以获取。")
+		assert.Nil(t, CodecOf("missing"), "未注册的编解码器应当返回 nil。")
+
+		data, err := Encode("text", "upper")
+		assert.NoError(t, err, "自定义编解码器的 Encode 不应当返回错误。")
+		var str string
+		assert.NoError(t, Decode(data, &str), "自定义编解码器的 Decode 不应当返回错误。")
+		assert.Equal(t, "TEXT", str, "自定义编解码器的结果应当和预期相等。")
+	})
+
+	t.Run("Typed", func(t *testing.T) {
+		Register("codec_test", NewMemory())
+		defer func() {
+			Of("codec_test").Close()
+			pairsMap.Delete("codec_test")
+			codecAliasMap.Delete("codec_test")
+		}()
+
+		assert.NoError(t, Set("codec_test", "player:1", player, time.Minute), "Set 不应当返回错误。")
+		ret, err := Get[testCodecPlayer]("codec_test", "player:1")
+		assert.NoError(t, err, "Get 不应当返回错误。")
+		assert.Equal(t, player, ret, "读取的对象应当和写入的对象相等。")
+		data, _ := Of("codec_test").Get("player:1")
+		assert.Contains(t, data, codecMagic+"\x01\x04json", "未指定编解码器时应当使用 DefaultCodec。")
+
+		pret, err := Get[*testCodecPlayer]("codec_test", "player:1")
+		assert.NoError(t, err, "读取为指针类型不应当返回错误。")
+		assert.Equal(t, player, *pret, "读取的对象应当和写入的对象相等。")
+
+		assert.NoError(t, UseCodec("codec_test", CodecMsgpack), "UseCodec 不应当返回错误。")
+		assert.ErrorIs(t, UseCodec("codec_test", "missing"), ErrNotSupported, "未注册的编解码器应当返回 ErrNotSupported。")
+		Set("codec_test", "player:2", player, 0)
+		data, _ = Of("codec_test").Get("player:2")
+		assert.Contains(t, data, codecMagic+"\x01\x07msgpack", "应当使用别名设置的编解码器。")
+		ret, err = Get[testCodecPlayer]("codec_test", "player:1")
+		assert.NoError(t, err, "切换编解码器后读取历史数据不应当返回错误。")
+		assert.Equal(t, player, ret, "切换编解码器后读取的历史数据应当和预期相等。")
+
+		Set("codec_test", "player:3", player.Name, 0, CodecRaw)
+		data, _ = Of("codec_test").Get("player:3")
+		assert.Equal(t, "<Alice>", data, "应当使用本次调用指定的编解码器。")
+		name, _ := Get[string]("codec_test", "player:3", CodecRaw)
+		assert.Equal(t, "<Alice>", name, "应当使用本次调用指定的编解码器。")
+
+		_, err = Get[testCodecPlayer]("codec_test", "missing")
+		assert.ErrorIs(t, err, ErrNotFound, "不存在的键应当返回 ErrNotFound。")
+		_, err = Get[testCodecPlayer]("codec_missing", "player:1")
+		assert.ErrorIs(t, err, ErrNotRegistered, "未注册的别名应当返回 ErrNotRegistered。")
+		assert.ErrorIs(t, Set("codec_missing", "player:1", player, 0), ErrNotRegistered, "未注册的别名应当返回 ErrNotRegistered。")
+		assert.ErrorIs(t, Set("codec_test", "player:1", player, 0, "missing"), ErrNotSupported, "未注册的编解码器应当返回 ErrNotSupported。")
+	})
+}
@@ -3,3 +3,535 @@
 // license that can be found in the LICENSE file.
 
 package XPairs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// ConsulTimeout 是 Consul 请求的默认超时时间，阻塞查询的超时时间为该值与等待时间之和。
+	ConsulTimeout = 5 * time.Second
+
+	// ConsulWatchWait 是 Consul 监听键变更时阻塞查询的最长等待时间。
+	ConsulWatchWait = 5 * time.Minute
+
+	// ConsulRetry 是 Consul 监听的查询失败时的重试间隔，也是连接失败后首次重连的间隔，连续失败时加倍，直至 ConsulBackoff。
+	ConsulRetry = time.Second
+
+	// ConsulBackoff 是 Consul 连接失败后重连间隔的上限。
+	ConsulBackoff = 30 * time.Second
+)
+
+// ConsulPair 是 Consul 存储的键值对。
+type ConsulPair struct {
+	Key         string // 键名
+	Value       string // 键值
+	Flags       uint64 // 自定义标记
+	CreateIndex uint64 // 创建时的索引
+	ModifyIndex uint64 // 最后修改的索引，用于 CAS 操作
+	LockIndex   uint64 // 被锁定的次数
+	Session     string // 持有锁的会话 ID
+}
+
+// consulPair 是 Consul KV 接口返回的键值对，Value 为 base64 编码。
+type consulPair struct {
+	Key         string
+	Value       []byte
+	Flags       uint64
+	CreateIndex uint64
+	ModifyIndex uint64
+	LockIndex   uint64
+	Session     string
+}
+
+// ConsulClient 是基于 HTTP 接口的 Consul KV 客户端，实现了 IPairs 接口且是线程安全的。
+// 需要注意的是，Consul 的 KV 不支持过期时间，设置过期时间的操作将返回 ErrNotSupported。
+type ConsulClient struct {
+	health
+	addr   string        // 服务地址，格式为 scheme://host:port
+	token  string        // 访问令牌
+	dc     string        // 数据中心
+	wait   time.Duration // 监听时阻塞查询的等待时间
+	client *http.Client  // HTTP 客户端
+	opened atomic.Int64  // 已建立的连接数量
+	inuse  atomic.Int64  // 执行中的请求数量，包括监听的阻塞查询
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// consulConn 是 Consul 的单个连接，关闭时更新连接数量。
+type consulConn struct {
+	net.Conn
+	cc   *ConsulClient
+	once sync.Once
+}
+
+// Close 关闭连接。
+func (conn *consulConn) Close() error {
+	conn.once.Do(func() { conn.cc.opened.Add(-1) })
+	return conn.Conn.Close()
+}
+
+// NewConsul 创建 Consul 客户端。
+// addr 为服务地址，支持 host:port 或 http(s)://host:port[?token=<令牌>&dc=<数据中心>] 格式；
+// pool 为每个主机的空闲连接数量，conn 为每个主机的最大连接数，小于等于 0 时不限制。
+// 客户端实现了 IPairs 接口，返回创建的客户端及地址的解析错误。
+func NewConsul(addr string, pool, conn int) (*ConsulClient, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("empty consul addr")
+	}
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %v of consul addr", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("empty host of consul addr")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cc := &ConsulClient{
+		health: health{target: u.Scheme + "://" + u.Host, retry: ConsulRetry, backoff: ConsulBackoff},
+		addr:   u.Scheme + "://" + u.Host,
+		token:  u.Query().Get("token"),
+		dc:     u.Query().Get("dc"),
+		wait:   ConsulWatchWait,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = max(pool, 0)
+	transport.MaxConnsPerHost = max(conn, 0)
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		nconn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		cc.opened.Add(1)
+		return &consulConn{Conn: nconn, cc: cc}, nil
+	}
+	cc.client = &http.Client{Transport: transport}
+	return cc, nil
+}
+
+// RegisterConsul 创建并注册指定别名的 Consul 客户端，参数与 NewConsul 相同，注册规则与 Register 相同。
+func RegisterConsul(alias, addr string, pool, conn int) error {
+	cc, err := NewConsul(addr, pool, conn)
+	if err != nil {
+		return err
+	}
+	Register(alias, cc)
+	return nil
+}
+
+// ConsulOf 返回指定别名的 Consul 客户端，未注册或类型不匹配时返回 nil。
+func ConsulOf(alias string) *ConsulClient {
+	cc, _ := Of(alias).(*ConsulClient)
+	return cc
+}
+
+// Get 读取键的值，键不存在时返回 ErrNotFound。
+func (cc *ConsulClient) Get(key string) (string, error) {
+	pair, err := cc.GetPair(key)
+	if err != nil {
+		return "", err
+	}
+	return pair.Value, nil
+}
+
+// GetPair 读取键值对，包括 ModifyIndex 等元数据，键不存在时返回 ErrNotFound。
+func (cc *ConsulClient) GetPair(key string) (*ConsulPair, error) {
+	pair, _, err := cc.WaitGet(key, 0, 0)
+	if err == nil && pair == nil {
+		return nil, ErrNotFound
+	}
+	return pair, err
+}
+
+// WaitGet 使用阻塞查询读取键值对，用于长轮询监听键的变更。
+// index 为上次查询返回的索引，wait 为最长的等待时间，index 为 0 时立即返回；
+// 返回键值对（键不存在时为 nil）、本次查询的索引及发生的错误。
+// 需要注意的是，若返回的索引小于 index（如 Consul 重建了快照），调用者应当将 index 重置为 0。
+func (cc *ConsulClient) WaitGet(key string, index uint64, wait time.Duration) (*ConsulPair, uint64, error) {
+	pairs, nindex, err := cc.query(cc.ctx, key, nil, index, wait)
+	if err != nil || len(pairs) == 0 {
+		return nil, nindex, err
+	}
+	return pairs[0], nindex, nil
+}
+
+// List 递归地列举指定前缀的键值对，前缀为空时列举所有的键值对。
+func (cc *ConsulClient) List(prefix string) ([]*ConsulPair, error) {
+	pairs, _, err := cc.WaitList(prefix, 0, 0)
+	return pairs, err
+}
+
+// WaitList 使用阻塞查询递归地列举指定前缀的键值对，规则与 WaitGet 相同。
+func (cc *ConsulClient) WaitList(prefix string, index uint64, wait time.Duration) ([]*ConsulPair, uint64, error) {
+	return cc.waitList(cc.ctx, prefix, index, wait)
+}
+
+// Keys 列举指定前缀的键名，结果按字典序排列，前缀为空时列举所有的键。
+func (cc *ConsulClient) Keys(prefix string) ([]string, error) {
+	return cc.SubKeys(prefix, "")
+}
+
+// SubKeys 列举指定前缀的键名，separator 不为空时仅列举到该分隔符为止（如目录）。
+func (cc *ConsulClient) SubKeys(prefix, separator string) ([]string, error) {
+	query := url.Values{"keys": {"true"}}
+	if separator != "" {
+		query.Set("separator", separator)
+	}
+	keys := make([]string, 0)
+	_, found, err := cc.request(cc.ctx, http.MethodGet, prefix, query, nil, 0, &keys)
+	if err != nil || !found {
+		return make([]string, 0), err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Put 设置键的值。
+func (cc *ConsulClient) Put(key, value string) error {
+	ok, err := cc.put(key, value, nil)
+	if err == nil && !ok {
+		err = fmt.Errorf("put consul key %v failed", key)
+	}
+	return err
+}
+
+// Set 设置键的值，Consul 的 KV 不支持过期时间，ttl 大于 0 时返回 ErrNotSupported。
+func (cc *ConsulClient) Set(key, value string, ttl time.Duration) error {
+	if ttl > 0 {
+		return fmt.Errorf("%w: ttl of consul", ErrNotSupported)
+	}
+	return cc.Put(key, value)
+}
+
+// SetNX 仅在键不存在时设置键的值，返回是否设置成功，ttl 的规则与 Set 相同。
+func (cc *ConsulClient) SetNX(key, value string, ttl time.Duration) (bool, error) {
+	if ttl > 0 {
+		return false, fmt.Errorf("%w: ttl of consul", ErrNotSupported)
+	}
+	return cc.CAS(key, value, 0)
+}
+
+// CAS 基于 ModifyIndex 设置键的值（Check-And-Set），index 为 0 时仅在键不存在时设置。
+// 返回是否设置成功，索引不匹配时返回 false。
+func (cc *ConsulClient) CAS(key, value string, index uint64) (bool, error) {
+	return cc.put(key, value, url.Values{"cas": {strconv.FormatUint(index, 10)}})
+}
+
+// CompareAndSwap 仅在键存在且值等于 old 时将其设置为 value，返回是否设置成功，ttl 的规则与 Set 相同。
+// 基于读取时的 ModifyIndex 进行 CAS 操作，比较期间键被修改时返回 false。
+func (cc *ConsulClient) CompareAndSwap(key, old, value string, ttl time.Duration) (bool, error) {
+	if ttl > 0 {
+		return false, fmt.Errorf("%w: ttl of consul", ErrNotSupported)
+	}
+	pair, err := cc.GetPair(key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if pair.Value != old {
+		return false, nil
+	}
+	return cc.CAS(key, value, pair.ModifyIndex)
+}
+
+// Delete 删除一个或多个键，返回被删除的键数量。
+// 每个键基于读取时的 ModifyIndex 删除，以准确地统计被删除的数量。
+func (cc *ConsulClient) Delete(keys ...string) (int, error) {
+	count := 0
+	for _, key := range keys {
+		for {
+			pair, err := cc.GetPair(key)
+			if errors.Is(err, ErrNotFound) {
+				break
+			} else if err != nil {
+				return count, err
+			}
+			ok, err := cc.DeleteCAS(key, pair.ModifyIndex)
+			if err != nil {
+				return count, err
+			}
+			if ok {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+// CompareAndDelete 仅在键存在且值等于 old 时删除该键，返回是否删除成功。
+func (cc *ConsulClient) CompareAndDelete(key, old string) (bool, error) {
+	pair, err := cc.GetPair(key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if pair.Value != old {
+		return false, nil
+	}
+	return cc.DeleteCAS(key, pair.ModifyIndex)
+}
+
+// DeleteCAS 基于 ModifyIndex 删除键，返回是否删除成功，索引不匹配时返回 false。
+func (cc *ConsulClient) DeleteCAS(key string, index uint64) (bool, error) {
+	var ok bool
+	_, _, err := cc.request(cc.ctx, http.MethodDelete, key, url.Values{"cas": {strconv.FormatUint(index, 10)}}, nil, 0, &ok)
+	return ok, err
+}
+
+// DeleteTree 递归地删除指定前缀的所有键。
+func (cc *ConsulClient) DeleteTree(prefix string) error {
+	_, _, err := cc.request(cc.ctx, http.MethodDelete, prefix, url.Values{"recurse": {"true"}}, nil, 0, nil)
+	return err
+}
+
+// Exists 返回一个或多个键中存在的数量。
+func (cc *ConsulClient) Exists(keys ...string) (int, error) {
+	count := 0
+	for _, key := range keys {
+		if _, err := cc.GetPair(key); err == nil {
+			count++
+		} else if !errors.Is(err, ErrNotFound) {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// Expire 设置键的过期时间，Consul 的 KV 不支持过期时间，ttl 大于 0 时返回 ErrNotSupported，否则返回键是否存在。
+func (cc *ConsulClient) Expire(key string, ttl time.Duration) (bool, error) {
+	if ttl > 0 {
+		return false, fmt.Errorf("%w: ttl of consul", ErrNotSupported)
+	}
+	count, err := cc.Exists(key)
+	return count > 0, err
+}
+
+// TTL 返回键的剩余过期时间，Consul 的 KV 不支持过期时间，键存在时总是返回 -1，键不存在时返回 ErrNotFound。
+func (cc *ConsulClient) TTL(key string) (time.Duration, error) {
+	if _, err := cc.GetPair(key); err != nil {
+		return 0, err
+	}
+	return -1, nil
+}
+
+// Watch 使用阻塞查询监听指定前缀的键值，对比快照后回调变更事件，返回停止监听的函数。
+// 查询失败时以 ConsulRetry 为间隔重试，阻塞期间多次变更的键仅回调最终的变更。
+func (cc *ConsulClient) Watch(prefix string, handler func(event Event)) (func(), error) {
+	pairs, index, err := cc.WaitList(prefix, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := consulSnapshot(pairs)
+	w := newWatcher(prefix, handler)
+	ctx, cancel := context.WithCancel(cc.ctx)
+	go func() {
+		select {
+		case <-w.done:
+		case <-ctx.Done(): // 客户端被关闭
+			w.stop()
+		}
+		cancel()
+	}()
+
+	go func() {
+		for !w.stopped() {
+			pairs, nindex, err := cc.waitList(ctx, prefix, index, cc.wait)
+			if err != nil || nindex == 0 {
+				select {
+				case <-w.done:
+				case <-time.After(ConsulRetry):
+				}
+				continue
+			}
+			if nindex < index { // 索引回退时重置
+				nindex = 0
+			}
+			index = nindex
+			nsnapshot := consulSnapshot(pairs)
+			if events := diffPairs(snapshot, nsnapshot); len(events) > 0 {
+				w.push(events...)
+			}
+			snapshot = nsnapshot
+		}
+	}()
+	return w.stop, nil
+}
+
+// Stats 返回连接的统计信息，使用中的连接数量为执行中的请求数量。
+func (cc *ConsulClient) Stats() PoolStats {
+	size, inuse := int(cc.opened.Load()), int(cc.inuse.Load())
+	return PoolStats{Size: size, InUse: inuse, Idle: max(size-inuse, 0)}
+}
+
+// Close 关闭客户端的空闲连接，并停止所有的监听及阻塞查询，关闭后的操作将返回错误。
+func (cc *ConsulClient) Close() error {
+	cc.cancel()
+	cc.client.CloseIdleConnections()
+	return nil
+}
+
+// waitList 使用阻塞查询递归地列举指定前缀的键值对。
+func (cc *ConsulClient) waitList(ctx context.Context, prefix string, index uint64, wait time.Duration) ([]*ConsulPair, uint64, error) {
+	pairs, nindex, err := cc.query(ctx, prefix, url.Values{"recurse": {"true"}}, index, wait)
+	if pairs == nil && err == nil {
+		pairs = make([]*ConsulPair, 0)
+	}
+	return pairs, nindex, err
+}
+
+// consulSnapshot 将键值对转换为快照。
+func consulSnapshot(pairs []*ConsulPair) map[string]string {
+	snapshot := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		snapshot[pair.Key] = pair.Value
+	}
+	return snapshot
+}
+
+// put 执行写入请求，返回服务端的写入结果。
+func (cc *ConsulClient) put(key, value string, query url.Values) (bool, error) {
+	var ok bool
+	_, _, err := cc.request(cc.ctx, http.MethodPut, key, query, []byte(value), 0, &ok)
+	return ok, err
+}
+
+// query 执行读取请求并解码键值对，index 不为 0 时使用阻塞查询。
+func (cc *ConsulClient) query(ctx context.Context, key string, query url.Values, index uint64, wait time.Duration) ([]*ConsulPair, uint64, error) {
+	if index > 0 {
+		if query == nil {
+			query = url.Values{}
+		}
+		query.Set("index", strconv.FormatUint(index, 10))
+		if wait > 0 {
+			query.Set("wait", strconv.FormatInt(wait.Milliseconds(), 10)+"ms")
+		}
+	}
+	var raws []*consulPair
+	nindex, found, err := cc.request(ctx, http.MethodGet, key, query, nil, wait, &raws)
+	if err != nil || !found {
+		return nil, nindex, err
+	}
+	pairs := make([]*ConsulPair, 0, len(raws))
+	for _, raw := range raws {
+		pairs = append(pairs, &ConsulPair{
+			Key:         raw.Key,
+			Value:       string(raw.Value),
+			Flags:       raw.Flags,
+			CreateIndex: raw.CreateIndex,
+			ModifyIndex: raw.ModifyIndex,
+			LockIndex:   raw.LockIndex,
+			Session:     raw.Session,
+		})
+	}
+	return pairs, nindex, nil
+}
+
+// request 执行 KV 接口的请求，将应答解码至 ret 中。
+// 返回应答的 X-Consul-Index、键是否存在（404 时为 false）及发生的错误。
+func (cc *ConsulClient) request(ctx context.Context, method, key string, query url.Values, body []byte, wait time.Duration, ret any) (uint64, bool, error) {
+	return cc.do(ctx, method, "/v1/kv/"+consulEscape(key), query, body, wait, ret)
+}
+
+// do 执行 HTTP 接口的请求，path 为接口路径（如 /v1/kv/<key>），返回值与 request 相同。
+// 连接失败后等待重连期间立即返回 ErrUnavailable，wait 为 0 的请求将记录耗时。
+func (cc *ConsulClient) do(ctx context.Context, method, path string, query url.Values, body []byte, wait time.Duration, ret any) (index uint64, found bool, err error) {
+	if ctx.Err() != nil {
+		return 0, false, ErrClosed
+	}
+	if err := cc.allow(); err != nil {
+		cc.observe(time.Now(), true)
+		return 0, false, err
+	}
+	cc.inuse.Add(1)
+	defer cc.inuse.Add(-1)
+	start := time.Now()
+	defer func() {
+		if wait == 0 {
+			cc.observe(start, err != nil && !errors.Is(err, ErrClosed))
+		}
+	}()
+	if query == nil {
+		query = url.Values{}
+	}
+	if cc.dc != "" {
+		query.Set("dc", cc.dc)
+	}
+	target := cc.addr + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ConsulTimeout+wait)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	if cc.token != "" {
+		req.Header.Set("X-Consul-Token", cc.token)
+	}
+	resp, err := cc.client.Do(req)
+	if err != nil {
+		if cc.ctx.Err() != nil || errors.Is(ctx.Err(), context.Canceled) { // 客户端被关闭或请求被取消
+			return 0, false, err
+		}
+		return 0, false, cc.fail(err)
+	}
+	defer resp.Body.Close()
+	cc.succeed()
+
+	index, _ = strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	if resp.StatusCode == http.StatusNotFound {
+		io.Copy(io.Discard, resp.Body)
+		return index, false, nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return index, false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return index, false, fmt.Errorf("consul %v %v failed with status %v: %v", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if ret != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, ret); err != nil {
+			return index, false, err
+		}
+	}
+	return index, true, nil
+}
+
+// consulEscape 对键名的每一段进行路径转义，保留分隔符 /。
+func consulEscape(key string) string {
+	parts := strings.Split(strings.TrimPrefix(key, "/"), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
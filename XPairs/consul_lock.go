@@ -0,0 +1,102 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ConsulSessionMinTTL 是 Consul 会话允许的最小有效期，Lock 的 ttl 小于该值时将被提升至该值。
+const ConsulSessionMinTTL = 10 * time.Second
+
+// consulSession 是创建会话的请求参数。
+type consulSession struct {
+	Name      string
+	TTL       string
+	Behavior  string
+	LockDelay string
+}
+
+// Lock 基于 Consul 会话获取键的锁，ttl 为租约的有效期，锁已被其他持有者获取时返回 ErrLocked。
+// 会话的失效行为为 delete，即租约过期或被释放时删除该键；会话的 lock-delay 为 0，与其他存储的行为一致。
+// 防护令牌为获取锁后键的 ModifyIndex，在整个 KV 中单调递增。
+// 需要注意的是，Consul 可能在会话的有效期的 2 倍之后才使会话失效。
+func (cc *ConsulClient) Lock(key string, ttl time.Duration) (*Lease, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("invalid lock ttl %v", ttl)
+	}
+	ttl = max(ttl, ConsulSessionMinTTL)
+	start := time.Now()
+	session, err := cc.createSession(key, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if _, _, err = cc.request(cc.ctx, http.MethodPut, key, url.Values{"acquire": {session}}, []byte(session), 0, &ok); err != nil {
+		cc.destroySession(session)
+		return nil, err
+	}
+	pair, err := cc.GetPair(key)
+	if err == nil && (!ok || pair.Session != session) {
+		err = fmt.Errorf("%w: %v", ErrLocked, key)
+	} else if errors.Is(err, ErrNotFound) { // 获取后会话立即失效
+		err = fmt.Errorf("%w: %v", ErrLocked, key)
+	}
+	if err != nil {
+		cc.destroySession(session)
+		return nil, err
+	}
+
+	lease := newLease(key, pair.ModifyIndex, ttl, start)
+	lease.renew = func() error {
+		_, found, err := cc.do(cc.ctx, http.MethodPut, "/v1/session/renew/"+session, nil, nil, 0, nil)
+		if err == nil && !found {
+			err = fmt.Errorf("%w: %v", ErrLockLost, key)
+		}
+		return err
+	}
+	lease.unlock = func() error {
+		pair, err := cc.GetPair(key)
+		if errors.Is(err, ErrNotFound) || (err == nil && pair.Session != session) {
+			err = fmt.Errorf("%w: %v", ErrLockLost, key)
+		}
+		if derr := cc.destroySession(session); err == nil {
+			err = derr
+		}
+		return err
+	}
+	return lease, nil
+}
+
+// createSession 创建有效期为 ttl 的会话，返回会话的 ID。
+func (cc *ConsulClient) createSession(key string, ttl time.Duration) (string, error) {
+	body, _ := json.Marshal(consulSession{
+		Name:      "XPairs.Lock: " + key,
+		TTL:       fmt.Sprintf("%ds", int64(math.Ceil(ttl.Seconds()))),
+		Behavior:  "delete",
+		LockDelay: "0s",
+	})
+	var ret struct{ ID string }
+	if _, _, err := cc.do(cc.ctx, http.MethodPut, "/v1/session/create", nil, body, 0, &ret); err != nil {
+		return "", err
+	}
+	if ret.ID == "" {
+		return "", fmt.Errorf("create consul session failed")
+	}
+	return ret.ID, nil
+}
+
+// destroySession 销毁会话，会话持有的锁将按照失效行为被删除。
+func (cc *ConsulClient) destroySession(session string) error {
+	_, _, err := cc.do(cc.ctx, http.MethodPut, "/v1/session/destroy/"+session, nil, nil, 0, nil)
+	return err
+}
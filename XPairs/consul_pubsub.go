@@ -0,0 +1,85 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ConsulPubSubPrefix 是 Consul 模拟发布订阅时消息的键名前缀，消息的键名为前缀加上频道及消息的 ID。
+	ConsulPubSubPrefix = "xpairs/pubsub/"
+
+	// ConsulPubSubRetain 是 Consul 模拟发布订阅时消息的保留时间，超过该时间的消息将在发布新消息时被清理。
+	ConsulPubSubRetain = time.Minute
+)
+
+// Publish 将消息写入频道下以发布时间及随机数命名的键，并清理该频道中超过 ConsulPubSubRetain 的消息。
+// Consul 无法统计接收到消息的订阅者，故总是返回 0。
+func (cc *ConsulClient) Publish(channel, message string) (int, error) {
+	now := time.Now()
+	key := fmt.Sprintf("%v%v/%020d-%v", ConsulPubSubPrefix, channel, now.UnixNano(), lockNonce())
+	if err := cc.Put(key, message); err != nil {
+		return 0, err
+	}
+	cc.purge(ConsulPubSubPrefix+channel+"/", now.Add(-ConsulPubSubRetain))
+	return 0, nil
+}
+
+// Subscribe 基于 Watch 监听 ConsulPubSubPrefix 下新增的消息，返回接收消息的通道及取消订阅的函数。
+// 阻塞查询失败时将自动重试，订阅生效之前已存在的消息不会被投递。
+func (cc *ConsulClient) Subscribe(pattern string) (<-chan Message, func(), error) {
+	s := newSubscriber(pattern)
+	stop, err := cc.Watch(ConsulPubSubPrefix, func(event Event) {
+		if event.Deleted || event.Old != "" {
+			return
+		}
+		path := strings.TrimPrefix(event.Key, ConsulPubSubPrefix)
+		if index := strings.LastIndex(path, "/"); index > 0 {
+			s.push(path[:index], event.New)
+		}
+	})
+	if err != nil {
+		s.stop()
+		return nil, nil, err
+	}
+	go func() {
+		select {
+		case <-cc.ctx.Done(): // 客户端被关闭
+			stop()
+			s.stop()
+		case <-s.done:
+		}
+	}()
+	return s.out, func() {
+		stop()
+		s.stop()
+	}, nil
+}
+
+// purge 删除频道目录中发布时间早于 before 的消息，清理失败时将在下次发布时重试。
+func (cc *ConsulClient) purge(dir string, before time.Time) {
+	keys, err := cc.SubKeys(dir, "/")
+	if err != nil {
+		return
+	}
+	var expired []string
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, dir)
+		if strings.HasSuffix(name, "/") { // 子频道的目录
+			continue
+		}
+		stamp, _, _ := strings.Cut(name, "-")
+		if nano, err := strconv.ParseInt(stamp, 10, 64); err == nil && nano < before.UnixNano() {
+			expired = append(expired, key)
+		}
+	}
+	if len(expired) > 0 {
+		cc.Delete(expired...)
+	}
+}
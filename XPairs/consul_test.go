@@ -4,6 +4,396 @@
 
 package XPairs
 
-import "testing"
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
 
-func TestConsulKV(t *testing.T) {}
+	"github.com/stretchr/testify/assert"
+)
+
+// testConsulServer 是基于 httptest 的 Consul KV 测试服务端，仅实现了测试所需的接口。
+type testConsulServer struct {
+	*httptest.Server
+	token    string
+	data     map[string]*consulPair
+	sessions map[string]time.Time // 会话及其过期时间
+	index    uint64
+	notify   chan struct{} // 数据变更时关闭并替换，用于唤醒阻塞查询
+	dcs      []string
+	mutex    sync.Mutex
+}
+
+// newTestConsulServer 启动 Consul 测试服务端，测试结束时自动关闭。
+func newTestConsulServer(t *testing.T, token ...string) *testConsulServer {
+	server := &testConsulServer{data: make(map[string]*consulPair), sessions: make(map[string]time.Time), index: 1, notify: make(chan struct{})}
+	if len(token) > 0 {
+		server.token = token[0]
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Addr 返回测试服务端的地址，格式为 host:port。
+func (s *testConsulServer) Addr() string { return strings.TrimPrefix(s.URL, "http://") }
+
+// modify 递增索引并唤醒阻塞查询，调用者需持有锁。
+func (s *testConsulServer) modify() uint64 {
+	s.index++
+	close(s.notify)
+	s.notify = make(chan struct{})
+	return s.index
+}
+
+// invalidate 使会话失效并删除其持有的键（失效行为为 delete），调用者需持有锁。
+func (s *testConsulServer) invalidate(session string) {
+	delete(s.sessions, session)
+	for k, pair := range s.data {
+		if pair.Session == session {
+			delete(s.data, k)
+		}
+	}
+	s.modify()
+}
+
+// session 处理会话接口的请求，调用者需持有锁。
+func (s *testConsulServer) session(w http.ResponseWriter, r *http.Request, path string) {
+	switch {
+	case path == "create":
+		var req consulSession
+		json.NewDecoder(r.Body).Decode(&req)
+		ttl, _ := time.ParseDuration(req.TTL)
+		if req.Behavior != "delete" || ttl < ConsulSessionMinTTL {
+			http.Error(w, "invalid session", http.StatusBadRequest)
+			return
+		}
+		s.index++
+		id := "session-" + strconv.FormatUint(s.index, 10)
+		s.sessions[id] = time.Now().Add(ttl)
+		json.NewEncoder(w).Encode(map[string]string{"ID": id})
+	case strings.HasPrefix(path, "renew/"):
+		id := strings.TrimPrefix(path, "renew/")
+		if _, ok := s.sessions[id]; !ok {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		s.sessions[id] = time.Now().Add(ConsulSessionMinTTL)
+		io.WriteString(w, "[]")
+	case strings.HasPrefix(path, "destroy/"):
+		s.invalidate(strings.TrimPrefix(path, "destroy/"))
+		io.WriteString(w, "true")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handle 处理 KV 及会话接口的请求。
+func (s *testConsulServer) handle(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" && r.Header.Get("X-Consul-Token") != s.token {
+		http.Error(w, "ACL not found", http.StatusForbidden)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for id, expire := range s.sessions {
+		if !time.Now().Before(expire) {
+			s.invalidate(id)
+		}
+	}
+	if strings.HasPrefix(r.URL.Path, "/v1/session/") {
+		s.session(w, r, strings.TrimPrefix(r.URL.Path, "/v1/session/"))
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, "/v1/kv/") {
+		http.NotFound(w, r)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	query := r.URL.Query()
+	if dc := query.Get("dc"); dc != "" {
+		s.dcs = append(s.dcs, dc)
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if index, _ := strconv.ParseUint(query.Get("index"), 10, 64); index > 0 && index >= s.index {
+			wait, _ := time.ParseDuration(query.Get("wait"))
+			if wait <= 0 {
+				wait = time.Minute
+			}
+			notify := s.notify
+			s.mutex.Unlock()
+			select {
+			case <-notify:
+			case <-time.After(wait):
+			}
+			s.mutex.Lock()
+		}
+		w.Header().Set("X-Consul-Index", strconv.FormatUint(s.index, 10))
+		var keys []string
+		for k := range s.data {
+			if k == key || ((query.Has("recurse") || query.Has("keys")) && strings.HasPrefix(k, key)) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		if len(keys) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		if query.Has("keys") {
+			rets := make([]string, 0)
+			separator := query.Get("separator")
+			for _, k := range keys {
+				if separator != "" {
+					if i := strings.Index(k[len(key):], separator); i >= 0 {
+						k = k[:len(key)+i+len(separator)]
+					}
+				}
+				if len(rets) == 0 || rets[len(rets)-1] != k {
+					rets = append(rets, k)
+				}
+			}
+			json.NewEncoder(w).Encode(rets)
+			return
+		}
+		rets := make([]*consulPair, 0, len(keys))
+		for _, k := range keys {
+			rets = append(rets, s.data[k])
+		}
+		json.NewEncoder(w).Encode(rets)
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		pair := s.data[key]
+		session := query.Get("acquire")
+		if session != "" {
+			if _, ok := s.sessions[session]; !ok {
+				http.Error(w, "invalid session", http.StatusInternalServerError)
+				return
+			}
+			if pair != nil && pair.Session != "" && pair.Session != session {
+				io.WriteString(w, "false")
+				return
+			}
+		}
+		if query.Has("cas") {
+			cas, _ := strconv.ParseUint(query.Get("cas"), 10, 64)
+			if (cas == 0 && pair != nil) || (cas != 0 && (pair == nil || pair.ModifyIndex != cas)) {
+				io.WriteString(w, "false")
+				return
+			}
+		}
+		index := s.modify()
+		if pair == nil {
+			pair = &consulPair{Key: key, CreateIndex: index}
+			s.data[key] = pair
+		}
+		pair.Value = body
+		pair.ModifyIndex = index
+		if session != "" && pair.Session != session {
+			pair.Session = session
+			pair.LockIndex++
+		}
+		io.WriteString(w, "true")
+	case http.MethodDelete:
+		if query.Has("cas") {
+			cas, _ := strconv.ParseUint(query.Get("cas"), 10, 64)
+			if pair := s.data[key]; pair == nil || pair.ModifyIndex != cas {
+				io.WriteString(w, "false")
+				return
+			}
+		}
+		for k := range s.data {
+			if k == key || (query.Has("recurse") && strings.HasPrefix(k, key)) {
+				delete(s.data, k)
+			}
+		}
+		s.modify()
+		io.WriteString(w, "true")
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestConsulKV(t *testing.T) {
+	server := newTestConsulServer(t)
+	client, err := NewConsul(server.Addr(), 2, 4)
+	assert.NoError(t, err, "创建 Consul 客户端不应当返回错误。")
+	defer client.Close()
+
+	t.Run("Addr", func(t *testing.T) {
+		tests := []struct {
+			addr  string
+			host  string
+			token string
+			dc    string
+			valid bool
+		}{
+			{"127.0.0.1:8500", "http://127.0.0.1:8500", "", "", true},
+			{"https://consul.local:8501", "https://consul.local:8501", "", "", true},
+			{"http://127.0.0.1:8500?token=secret&dc=dc1", "http://127.0.0.1:8500", "secret", "dc1", true},
+			{"", "", "", "", false},
+			{"redis://127.0.0.1:8500", "", "", "", false},
+			{"http://", "", "", "", false},
+		}
+		for _, test := range tests {
+			cc, err := NewConsul(test.addr, 1, 1)
+			if !test.valid {
+				assert.Error(t, err, "错误的地址应当返回错误：%v", test.addr)
+				continue
+			}
+			assert.NoError(t, err, "正确的地址不应当返回错误：%v", test.addr)
+			assert.Equal(t, test.host, cc.addr, "解析的服务地址应当和预期相等。")
+			assert.Equal(t, test.token, cc.token, "解析的令牌应当和预期相等。")
+			assert.Equal(t, test.dc, cc.dc, "解析的数据中心应当和预期相等。")
+		}
+	})
+
+	t.Run("Basic", func(t *testing.T) {
+		assert.NoError(t, client.Put("config/game/level", "10"), "Put 不应当返回错误。")
+		pair, err := client.GetPair("config/game/level")
+		assert.NoError(t, err, "GetPair 不应当返回错误。")
+		assert.Equal(t, "config/game/level", pair.Key, "读取的键名应当和预期相等。")
+		assert.Equal(t, "10", pair.Value, "读取的值应当和写入的值相等。")
+		assert.True(t, pair.ModifyIndex > 0, "读取的 ModifyIndex 应当大于 0。")
+
+		value, err := client.Get("config/game/level")
+		assert.NoError(t, err, "Get 不应当返回错误。")
+		assert.Equal(t, "10", value, "读取的值应当和写入的值相等。")
+		_, err = client.Get("config/missing")
+		assert.ErrorIs(t, err, ErrNotFound, "不存在的键应当返回 ErrNotFound。")
+		_, err = client.GetPair("config/missing")
+		assert.ErrorIs(t, err, ErrNotFound, "不存在的键应当返回 ErrNotFound。")
+
+		assert.NoError(t, client.Put("config/game/name", "a b/c?"), "Put 不应当返回错误。")
+		pair, _ = client.GetPair("config/game/name")
+		assert.Equal(t, "a b/c?", pair.Value, "特殊字符的值应当被完整地读写。")
+
+		count, err := client.Delete("config/game/name", "config/missing")
+		assert.NoError(t, err, "Delete 不应当返回错误。")
+		assert.Equal(t, 1, count, "被删除的键数量应当为 1。")
+		_, err = client.Get("config/game/name")
+		assert.ErrorIs(t, err, ErrNotFound, "被删除的键应当返回 ErrNotFound。")
+		count, err = client.Delete("config/missing")
+		assert.NoError(t, err, "删除不存在的键不应当返回错误。")
+		assert.Equal(t, 0, count, "删除不存在的键时数量应当为 0。")
+	})
+
+	t.Run("List", func(t *testing.T) {
+		client.Put("list/a", "1")
+		client.Put("list/b/c", "2")
+		client.Put("list/b/d", "3")
+		client.Put("other", "4")
+
+		pairs, err := client.List("list/")
+		assert.NoError(t, err, "List 不应当返回错误。")
+		assert.Equal(t, 3, len(pairs), "列举的键值对数量应当为 3。")
+		assert.Equal(t, "list/b/d", pairs[2].Key, "列举的键值对应当和预期相等。")
+		assert.Equal(t, "3", pairs[2].Value, "列举的键值应当和预期相等。")
+
+		keys, err := client.Keys("list/")
+		assert.NoError(t, err, "Keys 不应当返回错误。")
+		assert.Equal(t, []string{"list/a", "list/b/c", "list/b/d"}, keys, "列举的键名应当和预期相等。")
+		keys, _ = client.SubKeys("list/", "/")
+		assert.Equal(t, []string{"list/a", "list/b/"}, keys, "指定分隔符时应当仅列举到分隔符为止。")
+
+		pairs, err = client.List("missing/")
+		assert.NoError(t, err, "列举不存在的前缀不应当返回错误。")
+		assert.Empty(t, pairs, "列举不存在的前缀应当返回空切片。")
+		keys, _ = client.Keys("missing/")
+		assert.Empty(t, keys, "列举不存在的前缀应当返回空切片。")
+
+		assert.NoError(t, client.DeleteTree("list/"), "DeleteTree 不应当返回错误。")
+		pairs, _ = client.List("list/")
+		assert.Empty(t, pairs, "递归删除后前缀下不应当有键值对。")
+		_, err = client.Get("other")
+		assert.NoError(t, err, "递归删除不应当影响其他前缀的键。")
+	})
+
+	t.Run("CAS", func(t *testing.T) {
+		ok, err := client.CAS("cas/key", "v1", 0)
+		assert.NoError(t, err, "CAS 不应当返回错误。")
+		assert.True(t, ok, "键不存在时索引为 0 的 CAS 应当成功。")
+		ok, _ = client.CAS("cas/key", "v2", 0)
+		assert.False(t, ok, "键已存在时索引为 0 的 CAS 应当失败。")
+
+		pair, _ := client.GetPair("cas/key")
+		ok, _ = client.CAS("cas/key", "v2", pair.ModifyIndex+100)
+		assert.False(t, ok, "索引不匹配时 CAS 应当失败。")
+		ok, _ = client.CAS("cas/key", "v2", pair.ModifyIndex)
+		assert.True(t, ok, "索引匹配时 CAS 应当成功。")
+		npair, _ := client.GetPair("cas/key")
+		assert.Equal(t, "v2", npair.Value, "CAS 成功后的值应当和预期相等。")
+
+		ok, _ = client.DeleteCAS("cas/key", pair.ModifyIndex)
+		assert.False(t, ok, "索引不匹配时 DeleteCAS 应当失败。")
+		ok, err = client.DeleteCAS("cas/key", npair.ModifyIndex)
+		assert.NoError(t, err, "DeleteCAS 不应当返回错误。")
+		assert.True(t, ok, "索引匹配时 DeleteCAS 应当成功。")
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		client.Put("wait/key", "v1")
+		pair, index, err := client.WaitGet("wait/key", 0, 0)
+		assert.NoError(t, err, "WaitGet 不应当返回错误。")
+		assert.Equal(t, "v1", pair.Value, "非阻塞查询应当立即返回。")
+
+		start := time.Now()
+		_, nindex, err := client.WaitGet("wait/key", index, 50*time.Millisecond)
+		assert.NoError(t, err, "超时的阻塞查询不应当返回错误。")
+		assert.Equal(t, index, nindex, "超时的阻塞查询应当返回相同的索引。")
+		assert.True(t, time.Since(start) >= 50*time.Millisecond, "阻塞查询应当等待至超时。")
+
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			client.Put("wait/key", "v2")
+		}()
+		pair, nindex, err = client.WaitGet("wait/key", index, 5*time.Second)
+		assert.NoError(t, err, "阻塞查询不应当返回错误。")
+		assert.Greater(t, nindex, index, "数据变更后应当返回新的索引。")
+		assert.Equal(t, "v2", pair.Value, "阻塞查询应当返回变更后的值。")
+
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			client.Delete("wait/key")
+		}()
+		pair, _, err = client.WaitGet("wait/key", nindex, 5*time.Second)
+		assert.NoError(t, err, "键被删除时阻塞查询不应当返回错误。")
+		assert.Nil(t, pair, "键被删除时应当返回 nil。")
+
+		client.Put("wait/dir/a", "1")
+		pairs, index, err := client.WaitList("wait/dir/", 0, 0)
+		assert.NoError(t, err, "WaitList 不应当返回错误。")
+		assert.Equal(t, 1, len(pairs), "列举的键值对数量应当为 1。")
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			client.Put("wait/dir/b", "2")
+		}()
+		pairs, _, err = client.WaitList("wait/dir/", index, 5*time.Second)
+		assert.NoError(t, err, "阻塞的 WaitList 不应当返回错误。")
+		assert.Equal(t, 2, len(pairs), "前缀变更后应当返回新的键值对。")
+	})
+
+	t.Run("Token", func(t *testing.T) {
+		tserver := newTestConsulServer(t, "secret")
+		cc, _ := NewConsul(tserver.URL+"?token=secret&dc=dc1", 1, 1)
+		assert.NoError(t, cc.Put("key", "value"), "令牌正确时 Put 不应当返回错误。")
+		tserver.mutex.Lock()
+		assert.Contains(t, tserver.dcs, "dc1", "请求应当包含数据中心参数。")
+		tserver.mutex.Unlock()
+
+		wrong, _ := NewConsul(tserver.URL, 1, 1)
+		assert.Error(t, wrong.Put("key", "value"), "令牌错误时应当返回错误。")
+		_, err := wrong.Get("key")
+		assert.Error(t, err, "令牌错误时应当返回错误。")
+		assert.NotErrorIs(t, err, ErrNotFound, "令牌错误不应当返回 ErrNotFound。")
+	})
+}
@@ -2,5 +2,422 @@
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
 
-// XPairs 提供了 NoSQL 的键值对存储功能，支持高性能的数据读写和缓存操作。
+/*
+XPairs 提供了 NoSQL 的键值对存储功能，支持高性能的数据读写和缓存操作。
+
+功能特性
+
+  - 多源配置：通过解析首选项中的配置自动初始化键值存储的连接
+  - 通用接口：通过 IPairs 接口统一不同存储的读写、CAS 及变更监听，支持按别名注册及获取
+  - 实时配置：基于变更监听将存储的前缀绑定为 XPrefs.IBase 的实时视图，配置变更无需重启即可生效
+  - 内存存储：基于进程内存实现 IPairs 接口，适用于单元测试及单节点部署
+  - 类型化读写：通过泛型函数读写结构化的数据，支持 JSON、MessagePack 及原始字节等编解码器，可按别名或单次调用选择
+  - 分布式锁：提供基于租约的锁及防护令牌，支持续约及自动续约，Redis 使用 SET NX PX，Consul 使用会话实现
+  - 发布订阅：支持按 glob 模式订阅频道，Redis 断线后自动重新订阅，Consul 基于 KV 模拟，内存存储在进程内投递
+  - 数据结构：提供哈希表、有序集合及列表的操作，Redis 使用原生命令，内存存储提供相同的语义以便离线测试
+  - 排行榜：基于有序集合实现，支持同分时按达成时间排序、分页、邻近排名查询及赛季归档
+  - 限流及计数：提供令牌桶、滑动窗口限流器及固定窗口计数器，Redis 使用 Lua 脚本保证原子性，支持拒绝请求的指标监控
+  - 健康检查：Redis 连接池定期 PING 并限制空闲及存活时间，连接失败后按指数退避重连并快速失败，提供就绪状态及 Prometheus 指标
+  - Redis 客户端：基于 RESP 协议实现，内置连接池，支持常用的键值操作、管道及乐观锁事务，支持 Sentinel 及集群模式
+  - Consul 客户端：基于 KV 的 HTTP 接口实现，支持前缀列举、CAS 操作及阻塞查询
+
+使用手册
+
+1. 多源配置
+
+配置说明：
+  - 配置键名：Pairs/Source/<存储类型>/<存储别名>
+  - 支持 Redis、Consul、Memory（内存存储无需配置参数）
+  - 配置参数：
+  - Addr：服务地址，支持环境变量求值（如 ${Env.REDIS_ADDR}）
+  - Pool：连接池大小（空闲连接数量）
+  - Conn：最大连接数，小于等于 0 时不限制
+  - Codec：类型化读写默认使用的编解码器（可选），如 json、msgpack、raw
+
+配置示例：
+
+	{
+	    "Pairs/Source/Redis/Main": {
+	        "Addr": "redis://:password@127.0.0.1:6379/0",
+	        "Pool": 10,
+	        "Conn": 100
+	    },
+	    "Pairs/Source/Redis/Cache": {
+	        "Addr": "127.0.0.1:6379",
+	        "Pool": 4,
+	        "Conn": 16,
+	        "Codec": "msgpack"
+	    },
+	    "Pairs/Source/Consul/Config": {
+	        "Addr": "http://127.0.0.1:8500?token=${Env.CONSUL_TOKEN}&dc=dc1",
+	        "Pool": 2,
+	        "Conn": 8
+	    },
+	    "Pairs/Source/Memory/Local": {}
+	}
+
+Redis 的服务地址支持 host:port 或 redis://[[username]:password@]host:port[/db][?ping=<间隔>&idle=<时长>&age=<时长>] 格式（指定用户名时使用 Redis 6.0 ACL 的 AUTH <username> <password> 认证），Sentinel 模式的格式为 redis+sentinel://[[username]:password@]host:port[,host:port...]/<主节点名称>[/db][?sentinel_username=<用户名>&sentinel_password=<密码>]，集群模式的格式为 redis+cluster://[[username]:password@]host:port[,host:port...]；Consul 的服务地址支持 host:port 或 http(s)://host:port[?token=<令牌>&dc=<数据中心>] 格式。
+
+2. 通用接口
+
+	// 获取配置的存储，Redis、Consul 及内存存储均实现了 IPairs 接口
+	var pairs XPairs.IPairs = XPairs.Of("Main")
+
+	// 也可以手动注册，别名已被注册时将关闭并替换原有的存储
+	XPairs.Register("Local", XPairs.NewMemory())
+	aliases := XPairs.Aliases()
+
+	// 读写、CAS 及过期时间
+	pairs.Set("user:1", "test", time.Minute)
+	ok, err := pairs.SetNX("lock:1", "owner", 10*time.Second)
+	ok, err = pairs.CompareAndSwap("user:1", "test", "test2", 0)
+	ok, err = pairs.CompareAndDelete("lock:1", "owner")
+	keys, err := pairs.Keys("user:")
+
+	// 监听前缀的变更，事件按照发生的顺序回调
+	stop, err := pairs.Watch("user:", func(event XPairs.Event) {
+	    if event.Deleted {
+	        // 键被删除或过期，event.Old 为删除前的值
+	    }
+	})
+	defer stop()
+
+	// 将前缀绑定为 XPrefs.IBase 的实时视图，现有的首选项读取代码无需重启即可读取到最新的配置
+	prefs, err := XPairs.NewPrefsView("Config", "config/game/", func(event XPairs.Event) {
+	    // 可选：变更被应用于视图之后回调
+	})
+	defer prefs.Close()
+	level := prefs.GetInt("MaxLevel", 60) // 读取键 config/game/MaxLevel
+	enabled := prefs.GetBool("Feature/Enabled")
+
+注意：
+1. Redis 在服务端开启键空间通知（notify-keyspace-events 包含 K 及 A 或 $gx，如 CONFIG SET notify-keyspace-events K$gx）时基于通知监听，并以 XPairs.RedisWatchResync 为间隔全量对比以弥补订阅断开期间丢失的通知，否则以 XPairs.RedisWatchInterval 为间隔轮询；Consul 使用阻塞查询监听，内存存储在写入时立即通知；轮询或阻塞期间多次变更的键仅回调最终的变更
+2. Consul 的 KV 不支持过期时间，ttl 大于 0 时返回 XPairs.ErrNotSupported，TTL 对存在的键总是返回 -1
+3. 内存存储在访问时及以 XPairs.MemorySweep 为间隔清理过期的键，Close 后将释放所有的数据
+4. 配置视图的键名为去除前缀后的键名，值为合法的 JSON 时按 JSON 解析（如 true、10、[1,2] 及对象），否则为原始的字符串；Set 及 Unset 仅修改本地的视图
+
+3. 类型化读写
+
+	type Player struct {
+	    ID   int    `json:"id"`
+	    Name string `json:"name"`
+	}
+
+	// 编码后写入指定别名的存储，未指定编解码器时使用别名的 Codec 配置或 XPairs.DefaultCodec（JSON）
+	err := XPairs.Set("Main", "player:1", Player{ID: 1, Name: "Alice"}, time.Hour)
+	player, err := XPairs.Get[Player]("Main", "player:1")
+
+	// 单次调用指定编解码器，或设置别名默认使用的编解码器
+	err = XPairs.Set("Main", "player:2", player, 0, XPairs.CodecMsgpack)
+	err = XPairs.UseCodec("Main", XPairs.CodecMsgpack)
+
+	// 不依赖存储的编解码，以及注册自定义的编解码器
+	data, err := XPairs.Encode(player, XPairs.CodecMsgpack)
+	err = XPairs.Decode(data, &player)
+	XPairs.RegisterCodec(myCodec) // 实现 XPairs.ICodec 接口
+
+编解码器：
+  - json：与 XOrm 数据模型的 Json 函数相同不转义 HTML 字符，字符串亦编码为 JSON 字符串
+  - msgpack：MessagePack 格式，结构体编码为以字段名为键的映射，字段名及 omitempty 遵循 json 标签，time.Time 编码为时间戳扩展类型
+  - raw：仅支持字符串及字节切片，按原样读写且不添加信封
+
+注意：
+1. 除 raw 外，编码结果包含版本化的信封（标识、格式版本及编解码器名称），解码时优先使用信封中记录的编解码器，因此切换编解码器后仍能读取历史数据；无信封的历史数据使用指定的编解码器解码
+2. 别名未注册时返回 XPairs.ErrNotRegistered，编解码器未注册或信封版本未知时返回 XPairs.ErrNotSupported
+
+4. 分布式锁
+
+	// 尝试获取锁，锁已被其他持有者获取时返回 XPairs.ErrLocked
+	lease, err := XPairs.Lock("Main", "lock:daily-reset", 30*time.Second)
+	if errors.Is(err, XPairs.ErrLocked) {
+	    return
+	}
+	defer lease.Unlock()
+
+	// 等待至获取锁或超时，也可以直接使用客户端的 Lock 方法，如 XPairs.RedisOf("Main").Lock(...)
+	lease, err = XPairs.WaitLock("Main", "lock:guild:1", 30*time.Second, 5*time.Second)
+
+	// 手动续约或在后台自动续约，租约失效时返回或回调 XPairs.ErrLockLost
+	err = lease.Renew()
+	lease.KeepAlive(func(err error) {
+	    // 租约已失效，应当立即停止访问受保护的资源
+	})
+
+	// 写入受保护的资源时携带防护令牌，资源方拒绝令牌小于已见令牌的写入
+	token := lease.Token()
+
+实现方式：
+  - Redis 及内存存储：基于 IPairs 的原语实现，使用 SET NX PX（SetNX）获取锁，使用 CompareAndSwap 及 CompareAndDelete 校验持有者后续约及释放；防护令牌存储于 <键名>:fence 中且不会过期，Redis 及内存存储使用 INCRBY（IncrBy）原子地递增，未实现 IncrBy 的存储使用 CompareAndSwap 递增
+  - Consul：基于会话实现，会话的失效行为为 delete，防护令牌为获取锁后键的 ModifyIndex；有效期小于 XPairs.ConsulSessionMinTTL（10 秒）时将被提升至该值
+  - 存储可以实现 XPairs.ILocker 接口以提供原生的锁，否则 Lock 使用基于 IPairs 原语的实现
+
+注意：
+1. 锁是基于有效期的租约，持有者因停顿等原因超过有效期后锁可能被他人获取，故需要使用防护令牌保护资源的写入
+2. Valid 仅判断租约在本地是否有效；Consul 可能在会话有效期的 2 倍之后才使会话失效
+3. Renew 及 Unlock 的网络请求在租约的互斥锁之外执行，期间 Valid 等调用不会被阻塞；续约期间租约被释放时 Renew 返回 ErrLockLost
+
+5. 发布订阅
+
+	// 订阅匹配模式的频道，模式支持 *、?、[a-z] 等 glob 风格的通配符
+	messages, cancel, err := XPairs.Subscribe("Main", "chat.*")
+	defer cancel()
+	go func() {
+	    // 取消订阅或存储被关闭后通道将被关闭
+	    for message := range messages {
+	        fmt.Println(message.Channel, message.Pattern, message.Payload)
+	    }
+	}()
+
+	// 向频道发布消息，返回接收到消息的订阅者数量
+	count, err := XPairs.Publish("Main", "chat.world", "hello")
+
+实现方式：
+  - Redis：使用 PUBLISH 及 PSUBSCRIBE 命令，所有的订阅共用一个独立于连接池的连接，连接断开时以 XPairs.RedisRetry 为初始间隔按指数退避重连，并重新订阅所有的模式
+  - Consul：基于 KV 模拟，消息写入 xpairs/pubsub/<频道>/<消息 ID> 并通过阻塞查询投递，超过 XPairs.ConsulPubSubRetain（1 分钟）的消息将在发布时被清理，Publish 总是返回 0
+  - 内存存储：在进程内直接投递至匹配的订阅者
+  - 存储需要实现 XPairs.IPubSub 接口，否则返回 XPairs.ErrNotSupported
+
+注意：
+1. 消息不会被持久化，断线期间发布的消息将会丢失，需要可靠投递的场景应当使用消息队列
+2. 每个订阅者使用无界队列按序投递消息，消费者应当及时读取通道以避免消息堆积
+
+6. 数据结构
+
+	// 获取存储的数据结构操作，存储未实现对应的接口时返回 XPairs.ErrNotSupported
+	hash, err := XPairs.Hash("Main")
+	zset, err := XPairs.SortedSet("Main")
+	list, err := XPairs.List("Main")
+
+	// 哈希表
+	count, err := hash.HSet("user:1", map[string]string{"name": "alice", "level": "1"})
+	name, err := hash.HGet("user:1", "name") // 字段不存在时返回 XPairs.ErrNotFound
+	level, err := hash.HIncrBy("user:1", "level", 1)
+	fields, err := hash.HGetAll("user:1")
+
+	// 有序集合：按分数升序排列，分数相同时按成员的字典序排列
+	count, err = zset.ZAdd("rank", map[string]float64{"alice": 100, "bob": 90})
+	score, err := zset.ZIncrBy("rank", "bob", 20)
+	top, err := zset.ZRevRange("rank", 0, 9) // 索引从 0 开始，负数表示从末尾倒数
+	rank, err := zset.ZRevRank("rank", "alice")
+
+	// 列表
+	length, err := list.LPush("queue", "job1", "job2")
+	job, err := list.RPop("queue") // 列表为空时返回 XPairs.ErrNotFound
+	jobs, err := list.LRange("queue", 0, -1)
+	key, job, err := list.BLPop(5*time.Second, "queue") // 阻塞等待，超时时返回 XPairs.ErrNotFound
+
+实现方式：
+  - Redis：使用 HSET、ZADD、LPUSH 等同名命令，BLPop 等待期间将独占一个连接
+  - 内存存储：以相同的语义实现，适用于离线的单元测试
+  - Consul：不支持数据结构，返回 XPairs.ErrNotSupported
+
+注意：
+1. 对类型不匹配的键执行操作时返回 XPairs.ErrWrongType，如对列表执行哈希表的操作；Set 将覆盖其他类型的键
+2. 数据结构的所有元素被删除后键亦被删除；Watch 仅监听字符串类型的键的变更
+
+7. 排行榜
+
+	// 创建排行榜，存储需要实现有序集合（Redis 或内存存储）
+	lb, err := XPairs.NewLeaderboard("Main", "rank:season1", XPairs.LeaderboardOptions{
+	    Ascending: false,                                        // 默认按积分降序排名，竞速等场景可按升序排名
+	    Epoch:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), // 达成时间的起点，默认为 XPairs.LeaderboardEpoch
+	    TimeBits:  25,                                           // 达成时间占用的位数，默认为 30，积分的绝对值需小于 2^(53-TimeBits)
+	})
+
+	// 设置或累加积分，积分相同时先达成者排名靠前
+	err = lb.Set("alice", 100)
+	points, err := lb.Incr("bob", 10) // 累加时保留首次的达成时间
+
+	// 查询排名，排名从 1 开始，成员不存在时返回 XPairs.ErrNotFound
+	entry, err := lb.Get("alice")                    // entry.Points、entry.Time、entry.Rank
+	top, err := lb.Top(0, 10)                        // 分页读取
+	around, err := lb.Around("alice", 5)             // 成员及其前后各 5 名
+	ranged, err := lb.RangeByPoints(100, 200, 0, -1) // 按积分范围读取
+
+	// 赛季结算：原子地将排行榜重命名为归档的键并重置
+	archive, err := lb.Archive("rank:season1:archive")
+	err = lb.Reset() // 直接清空排行榜
+
+实现方式：
+  - 积分及达成时间被编码为复合分数：积分 × 2^TimeBits + 时间部分，时间部分以秒为单位，可以被 float64 精确地表示
+  - 降序排名时时间部分为 2^TimeBits - 1 - 达成的秒数，升序排名时为达成的秒数，使得同分时先达成者排名靠前
+  - 累加积分使用 Lua 脚本（内存存储为等价的操作）原子地执行 ZADD NX 及 ZINCRBY，成员不存在时以当前时间为达成时间
+  - 归档使用 RENAME 命令（内存存储为等价的操作），存储需要实现 Rename 函数，否则返回 XPairs.ErrNotSupported
+
+注意：
+1. 同一排行榜的选项应当保持一致，否则复合分数将无法被正确地解码
+2. 早于 Epoch 的时间视为 Epoch，超出 TimeBits 可以表示的时间视为最大的时间；默认选项约可表示 34 年，积分的绝对值需小于 2^23
+
+8. 限流及计数
+
+	// 令牌桶：每个用户每秒补充 2 条，最多突发 10 条聊天消息
+	chat, err := XPairs.NewTokenBucket("Main", "limit:chat", 2, 10)
+	ok, err := chat.Allow(userID)
+	result, err := chat.AllowN(userID, 3) // result.Allowed、result.Remaining、result.RetryAfter
+
+	// 滑动窗口：每个账号 15 分钟内最多尝试登录 5 次
+	login, err := XPairs.NewSlidingWindow("Main", "limit:login", 5, 15*time.Minute)
+	if ok, _ := login.Allow(account); !ok {
+	    // 拒绝登录
+	}
+	err = login.Reset(account) // 登录成功后清除限流状态
+
+	// 固定窗口计数：按天统计抽卡次数，窗口在 UTC 零点切换
+	pulls, err := XPairs.NewWindowCounter("Main", "count:gacha", 24*time.Hour)
+	count, err := pulls.Incr(userID, 10)
+	count, err = pulls.Get(userID)
+
+实现方式：
+  - Redis：使用 Lua 脚本（XPairs.RedisScript）原子地执行判断及更新，优先使用 EVALSHA，服务端未缓存脚本时回退至 EVAL
+  - 内存存储：使用互斥锁以相同的语义实现
+  - 状态存储于 <名称>:<键> 中：令牌桶使用哈希表，滑动窗口使用有序集合（窗口内的每次请求一个成员），计数器使用 <名称>:<键>:<窗口序号> 的字符串，状态均会自动过期
+  - 存储需要实现 XPairs.ILimiter 接口，否则返回 XPairs.ErrNotSupported
+
+指标监控：
+
+	| 指标 | 类型 | 描述 |
+	|------|------|------|
+	| xpairs_limiter_allowed_total{limiter} | Counter | 限流器允许的请求数量，标签为限流器的名称 |
+	| xpairs_limiter_rejected_total{limiter} | Counter | 限流器拒绝的请求数量，标签为限流器的名称 |
+
+注意：
+1. 判断时使用调用方的本地时间，共享限流器的多个实例应当保持时钟同步
+2. 请求的数量超过令牌桶的容量或窗口的上限时总是被拒绝，此时 RetryAfter 为 -1
+
+9. 健康检查
+
+	// 就绪探针：别名未注册或服务端不可达时返回 false
+	if !XPairs.Ready("Main") {
+	    // 暂不接收流量
+	}
+
+	// 服务端不可达时快速失败，而不是等待至超时
+	value, err := XPairs.Of("Main").Get("user:1")
+	if errors.Is(err, XPairs.ErrUnavailable) {
+	    // 降级处理，如返回缓存的数据
+	}
+
+	// 连接池的统计信息
+	if health, ok := XPairs.Of("Main").(XPairs.IHealth); ok {
+	    stats := health.Stats() // stats.Size、stats.InUse、stats.Idle
+	}
+
+实现方式：
+  - 连接失败后以 XPairs.RedisRetry（Consul 为 XPairs.ConsulRetry）为初始间隔重连，连续失败时间隔加倍，直至 XPairs.RedisBackoff（Consul 为 XPairs.ConsulBackoff）；重连前的请求立即返回包装了最近错误的 XPairs.ErrUnavailable
+  - Redis 连接池以 XPairs.RedisPingInterval 为间隔进行健康检查：关闭超过 XPairs.RedisIdleTimeout 的空闲连接及超过 XPairs.RedisMaxAge 的连接，使用 PING 检查其余的空闲连接，并在不可达时尝试重连以恢复就绪状态
+  - 连接被重置等非超时的网络错误通常意味着服务端重启或故障转移，此时将同时关闭所有的空闲连接
+  - 以上参数可以通过服务地址的查询参数覆盖，如 redis://127.0.0.1:6379/0?ping=10s&idle=1m&age=10m，为 0 时不检查或不限制
+
+指标监控：
+
+	| 指标 | 类型 | 描述 |
+	|------|------|------|
+	| xpairs_request_duration_seconds{alias} | Histogram | 请求的耗时，不包括监听的阻塞查询 |
+	| xpairs_request_errors_total{alias} | Counter | 因网络等原因失败的请求数量，不包括服务端的错误应答 |
+	| xpairs_pool_connections{alias} | Gauge | 已建立的连接数量，包括使用中及空闲的连接 |
+	| xpairs_pool_in_use{alias} | Gauge | 使用中的连接数量，Consul 为执行中的请求数量 |
+	| xpairs_ready{alias} | Gauge | 存储是否就绪，就绪时为 1 |
+
+注意：
+1. 指标以注册的别名为标签，通过 XPairs.NewRedis 等函数创建但未注册的客户端不记录指标
+2. 就绪状态仅在连接失败后变化，初始时视为就绪；服务启动时可以调用 Ping 确认服务端可达
+3. 内存存储未实现 XPairs.IHealth 接口，总是就绪的
+
+10. Redis 客户端
+
+	// 获取配置的客户端，也可以使用 XPairs.NewRedis 或 XPairs.RegisterRedis 创建
+	client := XPairs.RedisOf("Main")
+
+	// 读写操作，ttl 小于等于 0 时不过期
+	client.Set("user:1", "test", time.Minute)
+	value, err := client.Get("user:1")
+	if errors.Is(err, XPairs.ErrNotFound) {
+	    // 键不存在
+	}
+
+	// 删除、检查及过期时间
+	count, err := client.Delete("user:1", "user:2")
+	count, err = client.Exists("user:1")
+	ok, err := client.Expire("user:1", time.Hour)
+	ttl, err := client.TTL("user:1")
+
+	// 批量操作
+	err = client.MSet(map[string]string{"k1": "v1", "k2": "v2"})
+	values, err := client.MGet("k1", "k2")
+
+	// 执行任意命令
+	reply, err := client.Do("INCRBY", "counter", 10)
+
+	// 管道：排队的命令在 Exec 时通过一次写入发送，结果在 Exec 之后可用
+	pipe := client.Pipeline()
+	get := pipe.Get("user:1")
+	incr := pipe.IncrBy("counter", 1)
+	pipe.Do("HSET", "user:2", "name", "test")
+	err = pipe.Exec() // 返回网络错误或第一条执行失败的命令的错误
+	value, err = get.Text()
+	count64, err := incr.Int()
+
+	// 事务：监视的键在提交之前被修改时自动重试，超过 XPairs.RedisTxRetry 次时返回 XPairs.ErrConflict
+	err = client.Transaction(func(tx *XPairs.RedisTx) error {
+	    gold, err := tx.Read("GET", "gold").Int() // 在监视的连接上立即读取
+	    if err != nil {
+	        return err // 返回错误时放弃事务
+	    }
+	    tx.Set("gold", strconv.FormatInt(gold-10, 10), 0) // 排队的命令以 MULTI/EXEC 原子地执行
+	    return nil
+	}, "gold")
+
+	// Lua 脚本：在服务端原子地执行，优先使用 EVALSHA，服务端未缓存脚本时回退至 EVAL
+	script := XPairs.NewRedisScript("return redis.call('INCRBY', KEYS[1], ARGV[1])")
+	reply, err = script.Run(client, []string{"counter"}, 1)
+
+	// 集群模式：使用相同哈希标签的键位于同一槽位，可以在多键命令、管道及事务中一起使用
+	bag, mail := XPairs.RedisHashTag("user:1", "bag"), XPairs.RedisHashTag("user:1", "mail") // {user:1}:bag 及 {user:1}:mail
+	values, err = client.MGet(bag, mail)
+
+注意：
+1. 客户端是线程安全的，命令执行时从连接池获取连接，连接数量达到上限时等待至超时（XPairs.RedisTimeout）
+2. 服务端的错误应答返回 XPairs.RedisError（WRONGTYPE 错误同时包装了 XPairs.ErrWrongType），不会关闭连接；网络错误将关闭并丢弃该连接
+3. 管道及事务不是线程安全的，管道不保证命令的原子性；事务中执行失败的命令不会回滚其他命令，与 Redis 的语义一致
+4. Sentinel 模式下依次询问各 Sentinel 以发现主节点，新建的连接使用 ROLE 校验角色；健康检查时重新发现主节点，故障转移后关闭连接至原主节点的连接，写入被降级的节点返回的 READONLY 错误亦会触发重新发现
+5. 集群模式下按键的槽位（XPairs.RedisSlot）将命令路由至所属的主节点，自动跟随 MOVED 及 ASK 重定向，网络错误时刷新槽位映射（间隔不小于 XPairs.RedisClusterRefresh）；每个节点使用独立的连接池，Stats 为所有节点之和，所有的主节点就绪时 Ready 返回 true
+6. 集群模式下多键命令、管道、事务、Lua 脚本及排行榜的归档（RENAME）的所有键必须位于同一槽位，否则返回 XPairs.ErrCrossSlot，可以使用 XPairs.RedisHashTag 生成键名；Keys 合并所有主节点的结果，键空间通知仅在键所在的节点上发布，故 Watch 总是以轮询的方式监听
+
+11. Consul 客户端
+
+	// 获取配置的客户端，也可以使用 XPairs.NewConsul 或 XPairs.RegisterConsul 创建
+	client := XPairs.ConsulOf("Config")
+
+	// 读写操作
+	client.Put("config/game/level", "10")
+	value, err := client.Get("config/game/level")
+	pair, err := client.GetPair("config/game/level") // pair.Value 为键值，pair.ModifyIndex 为修改索引
+	count, err := client.Delete("config/game/level")
+
+	// 前缀列举及递归删除
+	pairs, err := client.List("config/")
+	keys, err := client.SubKeys("config/", "/") // 指定分隔符时仅列举到分隔符为止
+	client.DeleteTree("config/")
+
+	// CAS 操作：基于 ModifyIndex 更新，索引为 0 时仅在键不存在时设置
+	ok, err := client.CAS("config/game/level", "11", pair.ModifyIndex)
+	ok, err = client.DeleteCAS("config/game/level", pair.ModifyIndex)
+
+	// 阻塞查询：等待至键变更或超时，用于长轮询监听
+	var index uint64
+	for {
+	    pair, nindex, err := client.WaitGet("config/game/level", index, time.Minute)
+	    if err != nil {
+	        time.Sleep(time.Second)
+	        continue
+	    }
+	    if nindex < index { // 索引回退时重置
+	        nindex = 0
+	    }
+	    index = nindex
+	    // 处理 pair，键不存在时为 nil
+	}
+
+更多信息请参考模块文档。
+*/
 package XPairs
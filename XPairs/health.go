@@ -0,0 +1,181 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eframework-org/GO.UTIL/XLog"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// IHealth 是存储的健康状态的接口，由 Redis 及 Consul 客户端实现。
+// 连接服务端失败后，客户端将以指数退避的间隔重试，重试前的请求将立即返回 ErrUnavailable 而不再等待超时。
+type IHealth interface {
+	// Ready 返回存储是否就绪，连接服务端失败后至重连成功之前返回 false。
+	Ready() bool
+
+	// Stats 返回连接池的统计信息。
+	Stats() PoolStats
+}
+
+// PoolStats 是连接池的统计信息。
+type PoolStats struct {
+	Size  int // 已建立的连接数量，包括使用中及空闲的连接
+	InUse int // 使用中的连接数量
+	Idle  int // 空闲的连接数量
+}
+
+// Ready 返回指定别名的存储是否就绪，可用于服务的就绪探针。
+// 未注册时返回 false，未实现 IHealth 的存储（如内存存储）总是就绪的。
+func Ready(alias string) bool {
+	pairs := Of(alias)
+	if pairs == nil {
+		return false
+	}
+	if health, ok := pairs.(IHealth); ok {
+		return health.Ready()
+	}
+	return true
+}
+
+var (
+	// pairsLatency 定义了存储请求耗时的直方图，按别名统计，不包括监听的阻塞查询。
+	pairsLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "xpairs_request_duration_seconds",
+		Help:    "The latency of requests to the pairs backend.",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"alias"})
+
+	// pairsErrors 定义了存储请求错误的计数器，按别名统计，服务端的错误应答（如 WRONGTYPE）不计入。
+	pairsErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "xpairs_request_errors_total",
+		Help: "The total number of failed requests to the pairs backend.",
+	}, []string{"alias"})
+
+	// pairsPoolSize 描述了连接池中已建立的连接数量。
+	pairsPoolSize = prometheus.NewDesc("xpairs_pool_connections", "The number of established connections of the pairs backend.", []string{"alias"}, nil)
+
+	// pairsPoolInUse 描述了连接池中使用中的连接数量。
+	pairsPoolInUse = prometheus.NewDesc("xpairs_pool_in_use", "The number of connections in use of the pairs backend.", []string{"alias"}, nil)
+
+	// pairsReady 描述了存储是否就绪，就绪时为 1。
+	pairsReady = prometheus.NewDesc("xpairs_ready", "Whether the pairs backend is ready.", []string{"alias"}, nil)
+)
+
+func init() { prometheus.MustRegister(pairsLatency, pairsErrors, healthCollector{}) }
+
+// healthCollector 在采集时遍历已注册的存储，输出实现了 IHealth 的存储的连接池及就绪状态。
+type healthCollector struct{}
+
+// Describe 输出指标的描述。
+func (healthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pairsPoolSize
+	ch <- pairsPoolInUse
+	ch <- pairsReady
+}
+
+// Collect 输出各别名的指标。
+func (healthCollector) Collect(ch chan<- prometheus.Metric) {
+	pairsMap.Range(func(key, value any) bool {
+		health, ok := value.(IHealth)
+		if !ok {
+			return true
+		}
+		alias := key.(string)
+		stats := health.Stats()
+		ready := 0.0
+		if health.Ready() {
+			ready = 1
+		}
+		ch <- prometheus.MustNewConstMetric(pairsPoolSize, prometheus.GaugeValue, float64(stats.Size), alias)
+		ch <- prometheus.MustNewConstMetric(pairsPoolInUse, prometheus.GaugeValue, float64(stats.InUse), alias)
+		ch <- prometheus.MustNewConstMetric(pairsReady, prometheus.GaugeValue, ready, alias)
+		return true
+	})
+}
+
+// health 是客户端的健康状态，记录连续的连接失败并计算重试的时间，由 Redis 及 Consul 客户端嵌入。
+type health struct {
+	target  string        // 服务地址，用于日志及错误信息
+	alias   string        // 注册的别名，用于指标的标签，未注册时不记录指标
+	retry   time.Duration // 首次重试的间隔，连续失败时加倍
+	backoff time.Duration // 重试间隔的上限
+	fails   int           // 连续失败的次数，为 0 时表示就绪
+	until   time.Time     // 下次重试的时间，在此之前的请求快速失败
+	err     error         // 最近一次连接失败的错误
+	mutex   sync.Mutex
+}
+
+// Ready 返回存储是否就绪，连接服务端失败后至重连成功之前返回 false。
+func (h *health) Ready() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.fails == 0
+}
+
+// bind 设置指标的别名，由 Register 调用。
+func (h *health) bind(alias string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.alias = alias
+}
+
+// allow 判断是否允许连接服务端，等待重试期间返回包装了最近错误的 ErrUnavailable。
+func (h *health) allow() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.fails > 0 && time.Now().Before(h.until) {
+		return fmt.Errorf("%w: %v, retry after %v: %w", ErrUnavailable, h.target, time.Until(h.until).Round(time.Millisecond), h.err)
+	}
+	return nil
+}
+
+// fail 记录连接失败并按指数退避计算下次重试的时间，返回包装了 err 的 ErrUnavailable。
+// 同一重试周期内并发的失败只计一次，以免重试间隔增长过快。
+func (h *health) fail(err error) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	now := time.Now()
+	h.err = err
+	if h.fails == 0 || !now.Before(h.until) {
+		h.fails++
+		delay := h.retry
+		for i := 1; i < h.fails && delay < h.backoff; i++ {
+			delay *= 2
+		}
+		h.until = now.Add(min(delay, h.backoff))
+		if h.fails == 1 {
+			XLog.Warn("XPairs.Health: %v is unavailable: %v", h.target, err)
+		}
+	}
+	return fmt.Errorf("%w: %v: %w", ErrUnavailable, h.target, err)
+}
+
+// succeed 记录连接成功，重置失败的次数。
+func (h *health) succeed() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.fails > 0 {
+		XLog.Notice("XPairs.Health: %v is recovered after %v failures.", h.target, h.fails)
+		h.fails, h.err = 0, nil
+	}
+}
+
+// observe 记录一次请求的耗时，failed 表示请求是否因网络等原因失败。
+func (h *health) observe(start time.Time, failed bool) {
+	h.mutex.Lock()
+	alias := h.alias
+	h.mutex.Unlock()
+	if alias == "" {
+		return
+	}
+	pairsLatency.WithLabelValues(alias).Observe(time.Since(start).Seconds())
+	if failed {
+		pairsErrors.WithLabelValues(alias).Inc()
+	}
+}
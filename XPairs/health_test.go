@@ -0,0 +1,153 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run("Backoff", func(t *testing.T) {
+		h := &health{target: "test", retry: 100 * time.Millisecond, backoff: 300 * time.Millisecond}
+		assert.True(t, h.Ready(), "初始状态应当为就绪。")
+		assert.NoError(t, h.allow(), "就绪时应当允许连接。")
+
+		cause := errors.New("connection refused")
+		err := h.fail(cause)
+		assert.ErrorIs(t, err, ErrUnavailable, "连接失败应当返回 ErrUnavailable。")
+		assert.ErrorIs(t, err, cause, "ErrUnavailable 应当包装原始的错误。")
+		assert.False(t, h.Ready(), "连接失败后不应当就绪。")
+		assert.ErrorIs(t, h.allow(), ErrUnavailable, "等待重试期间应当快速失败。")
+		assert.WithinDuration(t, time.Now().Add(100*time.Millisecond), h.until, 20*time.Millisecond, "首次重试的间隔应当为 retry。")
+
+		h.fail(cause)
+		assert.Equal(t, 1, h.fails, "同一重试周期内的失败应当只计一次。")
+
+		for _, expected := range []time.Duration{200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+			h.until = time.Now()
+			assert.NoError(t, h.allow(), "到达重试时间后应当允许连接。")
+			h.fail(cause)
+			assert.WithinDuration(t, time.Now().Add(expected), h.until, 20*time.Millisecond, "重试的间隔应当加倍且不超过 backoff。")
+		}
+
+		h.succeed()
+		assert.True(t, h.Ready(), "连接成功后应当恢复就绪。")
+		assert.NoError(t, h.allow(), "恢复就绪后应当允许连接。")
+		assert.Equal(t, 0, h.fails, "连接成功后应当重置失败的次数。")
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		server := newTestRedisServer(t)
+		rc, _ := NewRedis(server.Addr(), 2, 4)
+		rc.retry = 100 * time.Millisecond
+		rc.backoff = 200 * time.Millisecond
+		rc.ping = 30 * time.Millisecond
+		Register("health_redis", rc)
+		defer func() {
+			rc.Close()
+			pairsMap.Delete("health_redis")
+		}()
+		errs := testutil.ToFloat64(pairsErrors.WithLabelValues("health_redis"))
+		series := testutil.CollectAndCount(pairsLatency)
+
+		assert.NoError(t, rc.Ping(), "服务端可达时 Ping 不应当返回错误。")
+		assert.True(t, Ready("health_redis"), "服务端可达时应当就绪。")
+		assert.Equal(t, PoolStats{Size: 1, InUse: 0, Idle: 1}, rc.Stats(), "连接池的统计信息应当和预期相等。")
+		assert.Equal(t, series+1, testutil.CollectAndCount(pairsLatency), "请求的耗时应当按别名统计。")
+		assert.GreaterOrEqual(t, testutil.CollectAndCount(healthCollector{}), 3, "连接池及就绪状态应当按别名输出。")
+
+		server.down()
+		assert.Eventually(t, func() bool { return rc.Stats().Size == 0 }, time.Second, 10*time.Millisecond, "健康检查应当关闭断开的空闲连接。")
+		err := rc.Ping()
+		assert.ErrorIs(t, err, ErrUnavailable, "服务端不可达时应当返回 ErrUnavailable。")
+		assert.False(t, rc.Ready(), "服务端不可达时不应当就绪。")
+		assert.False(t, Ready("health_redis"), "服务端不可达时别名不应当就绪。")
+
+		start := time.Now()
+		err = rc.Ping()
+		assert.ErrorIs(t, err, ErrUnavailable, "等待重连期间应当返回 ErrUnavailable。")
+		assert.Less(t, time.Since(start), 50*time.Millisecond, "等待重连期间应当快速失败。")
+		assert.Contains(t, err.Error(), "retry after", "错误信息应当包含重试的时间。")
+		assert.GreaterOrEqual(t, testutil.ToFloat64(pairsErrors.WithLabelValues("health_redis"))-errs, 2.0, "失败的请求应当按别名统计。")
+
+		server.up(t)
+		assert.Eventually(t, rc.Ready, 2*time.Second, 10*time.Millisecond, "服务端恢复后健康检查应当自动重连。")
+		assert.NoError(t, rc.Ping(), "重连后 Ping 不应当返回错误。")
+	})
+
+	t.Run("Expire", func(t *testing.T) {
+		server := newTestRedisServer(t)
+		tests := []struct {
+			query string
+			wait  time.Duration
+		}{
+			{"?ping=0&idle=50ms", 80 * time.Millisecond},
+			{"?ping=0&idle=0&age=50ms", 80 * time.Millisecond},
+		}
+		for _, test := range tests {
+			rc, err := NewRedis("redis://"+server.Addr()+test.query, 1, 1)
+			assert.NoError(t, err, "NewRedis 不应当返回错误：%v", test.query)
+			assert.Equal(t, time.Duration(0), rc.ping, "ping 为 0 时不应当启动健康检查。")
+
+			rc.Ping()
+			first := <-rc.idles
+			rc.idles <- first
+			time.Sleep(test.wait)
+			rc.Ping()
+			second := <-rc.idles
+			rc.idles <- second
+			assert.NotSame(t, first, second, "过期的连接应当被关闭并重新建立：%v", test.query)
+			assert.Equal(t, 1, rc.Stats().Size, "过期的连接不应当被计入连接数量：%v", test.query)
+			rc.Close()
+			assert.Equal(t, 0, rc.Stats().Size, "关闭后连接数量应当为 0：%v", test.query)
+		}
+
+		for _, query := range []string{"?idle=abc", "?age=-1s", "?ping=1"} {
+			_, err := NewRedis("redis://"+server.Addr()+query, 1, 1)
+			assert.Error(t, err, "无效的时长应当返回错误：%v", query)
+		}
+	})
+
+	t.Run("Consul", func(t *testing.T) {
+		server := newTestConsulServer(t)
+		cc, _ := NewConsul(server.Addr(), 2, 4)
+		cc.retry = time.Minute
+		Register("health_consul", cc)
+		defer func() {
+			cc.Close()
+			pairsMap.Delete("health_consul")
+		}()
+
+		assert.NoError(t, cc.Set("health/key", "value", 0), "服务端可达时 Set 不应当返回错误。")
+		assert.True(t, Ready("health_consul"), "服务端可达时应当就绪。")
+		assert.Equal(t, 1, cc.Stats().Size, "连接数量应当和预期相等。")
+		assert.Equal(t, 0, cc.Stats().InUse, "请求完成后使用中的连接数量应当为 0。")
+
+		server.CloseClientConnections()
+		server.Close()
+		_, err := cc.Get("health/key")
+		assert.ErrorIs(t, err, ErrUnavailable, "服务端不可达时应当返回 ErrUnavailable。")
+		assert.False(t, Ready("health_consul"), "服务端不可达时不应当就绪。")
+		start := time.Now()
+		_, err = cc.Get("health/key")
+		assert.ErrorIs(t, err, ErrUnavailable, "等待重连期间应当返回 ErrUnavailable。")
+		assert.Less(t, time.Since(start), 50*time.Millisecond, "等待重连期间应当快速失败。")
+	})
+
+	t.Run("Ready", func(t *testing.T) {
+		assert.False(t, Ready("health_missing"), "未注册的别名不应当就绪。")
+		Register("health_memory", NewMemory())
+		defer func() {
+			Of("health_memory").Close()
+			pairsMap.Delete("health_memory")
+		}()
+		assert.True(t, Ready("health_memory"), "未实现 IHealth 的存储应当总是就绪。")
+	})
+}
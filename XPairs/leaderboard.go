@@ -0,0 +1,279 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// LeaderboardTimeBits 是排行榜的复合分数中达成时间默认占用的位数，以秒为单位约可表示 34 年，此时积分的绝对值需小于 2^23。
+	LeaderboardTimeBits = 30
+
+	// leaderboardScoreBits 是 float64 可以精确表示的整数的位数。
+	leaderboardScoreBits = 53
+)
+
+// LeaderboardEpoch 是排行榜达成时间的默认起点。
+var LeaderboardEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// LeaderboardOptions 是排行榜的选项。
+type LeaderboardOptions struct {
+	Ascending bool      // 是否按积分升序排名（如竞速的用时），默认按积分降序排名
+	Epoch     time.Time // 达成时间的起点，零值时使用 LeaderboardEpoch，早于起点的时间视为起点
+	TimeBits  uint      // 达成时间占用的位数，零值时使用 LeaderboardTimeBits，积分的绝对值需小于 2^(53-TimeBits)
+}
+
+// LeaderboardEntry 是排行榜的条目。
+type LeaderboardEntry struct {
+	Member string    // 成员
+	Points int64     // 积分
+	Time   time.Time // 达成时间，精确到秒
+	Rank   int       // 排名，从 1 开始
+}
+
+// Leaderboard 是基于有序集合的排行榜，积分相同时先达成者排名靠前。
+// 积分及达成时间被编码为复合分数：分数 = 积分 × 2^TimeBits + 时间部分，降序排名时时间部分为 2^TimeBits-1 减去达成的秒数，
+// 升序排名时为达成的秒数，从而使有序集合的排序与排行榜的排名一致；积分及时间部分均为整数，可以被 float64 精确地表示。
+type Leaderboard struct {
+	pairs   IPairs
+	zset    ISortedSet
+	key     string
+	options LeaderboardOptions
+}
+
+// NewLeaderboard 创建指定别名的存储中键名为 key 的排行榜，options 为排行榜的选项，同一排行榜的选项应当保持一致。
+// 别名未注册时返回 ErrNotRegistered，存储未实现 ISortedSet 时返回 ErrNotSupported。
+func NewLeaderboard(alias, key string, options ...LeaderboardOptions) (*Leaderboard, error) {
+	zset, err := SortedSet(alias)
+	if err != nil {
+		return nil, err
+	}
+	lb := &Leaderboard{pairs: Of(alias), zset: zset, key: key}
+	if len(options) > 0 {
+		lb.options = options[0]
+	}
+	if lb.options.Epoch.IsZero() {
+		lb.options.Epoch = LeaderboardEpoch
+	}
+	if lb.options.TimeBits == 0 {
+		lb.options.TimeBits = LeaderboardTimeBits
+	}
+	if lb.options.TimeBits >= leaderboardScoreBits {
+		return nil, fmt.Errorf("invalid time bits %v of leaderboard", lb.options.TimeBits)
+	}
+	return lb, nil
+}
+
+// Key 返回排行榜的键名。
+func (lb *Leaderboard) Key() string { return lb.key }
+
+// Set 以当前时间为达成时间设置成员的积分。
+func (lb *Leaderboard) Set(member string, points int64) error {
+	return lb.SetAt(member, points, time.Now())
+}
+
+// SetAt 设置成员的积分及达成时间，积分超出复合分数可以表示的范围时返回错误。
+func (lb *Leaderboard) SetAt(member string, points int64, at time.Time) error {
+	score, err := lb.encode(points, at)
+	if err != nil {
+		return err
+	}
+	_, err = lb.zset.ZAdd(lb.key, map[string]float64{member: score})
+	return err
+}
+
+// Incr 将成员的积分增加 delta 并返回增加后的积分，成员不存在时以当前时间为达成时间，已存在时保留其首次的达成时间。
+// 存储实现了 ZIncrByInit（Redis 及内存存储）时积分的增加是原子的，可以被多个客户端并发地调用；
+// 否则先读取成员是否存在再增加分数，多个客户端同时为新成员增加积分时其达成时间可能被重复计入。
+func (lb *Leaderboard) Incr(member string, delta int64) (int64, error) {
+	if _, err := lb.encode(delta, time.Time{}); err != nil {
+		return 0, err
+	}
+	tie := lb.tie(time.Now())
+	increment := float64(delta) * lb.scale()
+	var score float64
+	var err error
+	if incr, ok := lb.zset.(interface {
+		ZIncrByInit(key, member string, init, delta float64) (float64, error)
+	}); ok {
+		score, err = incr.ZIncrByInit(lb.key, member, tie, increment)
+	} else {
+		if _, err = lb.zset.ZScore(lb.key, member); errors.Is(err, ErrNotFound) { // 新上榜的成员，补充其达成时间
+			increment += tie
+		} else if err != nil {
+			return 0, err
+		}
+		score, err = lb.zset.ZIncrBy(lb.key, member, increment)
+	}
+	if err != nil {
+		return 0, err
+	}
+	points, _ := lb.decode(score)
+	return points, nil
+}
+
+// Remove 移除一个或多个成员，返回被移除的成员数量。
+func (lb *Leaderboard) Remove(members ...string) (int, error) {
+	return lb.zset.ZRem(lb.key, members...)
+}
+
+// Get 返回成员的条目，成员不存在时返回 ErrNotFound。
+func (lb *Leaderboard) Get(member string) (LeaderboardEntry, error) {
+	score, err := lb.zset.ZScore(lb.key, member)
+	if err != nil {
+		return LeaderboardEntry{}, err
+	}
+	rank, err := lb.rank(member)
+	if err != nil {
+		return LeaderboardEntry{}, err
+	}
+	points, at := lb.decode(score)
+	return LeaderboardEntry{Member: member, Points: points, Time: at, Rank: rank + 1}, nil
+}
+
+// Count 返回排行榜的成员数量。
+func (lb *Leaderboard) Count() (int, error) {
+	return lb.zset.ZCard(lb.key)
+}
+
+// Top 按排名返回跳过 offset 个成员后的最多 count 个条目，用于分页读取排行榜。
+func (lb *Leaderboard) Top(offset, count int) ([]LeaderboardEntry, error) {
+	if offset < 0 || count <= 0 {
+		return []LeaderboardEntry{}, nil
+	}
+	return lb.ranged(offset, offset+count-1)
+}
+
+// Around 返回成员及其前后各 radius 名的条目，成员不存在时返回 ErrNotFound。
+func (lb *Leaderboard) Around(member string, radius int) ([]LeaderboardEntry, error) {
+	rank, err := lb.rank(member)
+	if err != nil {
+		return nil, err
+	}
+	return lb.ranged(max(rank-radius, 0), rank+max(radius, 0))
+}
+
+// RangeByPoints 按排名返回积分在 [min, max] 之间的条目，跳过 offset 个条目后最多返回 count 个，count 小于 0 时返回所有。
+func (lb *Leaderboard) RangeByPoints(min, max int64, offset, count int) ([]LeaderboardEntry, error) {
+	for _, points := range []int64{min, max} {
+		if _, err := lb.encode(points, time.Time{}); err != nil {
+			return nil, err
+		}
+	}
+	low, high := float64(min)*lb.scale(), float64(max+1)*lb.scale()-1
+	var members []ZMember
+	var err error
+	if lb.options.Ascending {
+		members, err = lb.zset.ZRangeByScore(lb.key, low, high, offset, count)
+	} else {
+		members, err = lb.zset.ZRevRangeByScore(lb.key, low, high, offset, count)
+	}
+	if err != nil || len(members) == 0 {
+		return []LeaderboardEntry{}, err
+	}
+	first, err := lb.rank(members[0].Member)
+	if err != nil {
+		return nil, err
+	}
+	return lb.entries(members, first), nil
+}
+
+// Archive 将排行榜重命名为 archiveKey 以归档当前的赛季并重置排行榜，返回归档的排行榜，archiveKey 已存在时将被覆盖。
+// 重命名是原子的，归档期间写入的积分将被计入归档或新的赛季；排行榜为空时归档亦为空。
+// 存储未实现重命名时返回 ErrNotSupported。
+func (lb *Leaderboard) Archive(archiveKey string) (*Leaderboard, error) {
+	renamer, ok := lb.zset.(interface {
+		Rename(key, newkey string) error
+	})
+	if !ok {
+		return nil, fmt.Errorf("%w: rename of leaderboard", ErrNotSupported)
+	}
+	err := renamer.Rename(lb.key, archiveKey)
+	if errors.Is(err, ErrNotFound) { // 排行榜为空，清空已存在的归档
+		_, err = lb.pairs.Delete(archiveKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Leaderboard{pairs: lb.pairs, zset: lb.zset, key: archiveKey, options: lb.options}, nil
+}
+
+// Reset 删除排行榜的所有成员。
+func (lb *Leaderboard) Reset() error {
+	_, err := lb.pairs.Delete(lb.key)
+	return err
+}
+
+// scale 返回积分在复合分数中的倍数，即 2^TimeBits。
+func (lb *Leaderboard) scale() float64 {
+	return float64(uint64(1) << lb.options.TimeBits)
+}
+
+// tie 返回达成时间在复合分数中的时间部分。
+func (lb *Leaderboard) tie(at time.Time) float64 {
+	limit := int64(1)<<lb.options.TimeBits - 1
+	elapsed := min(max(int64(at.Sub(lb.options.Epoch)/time.Second), 0), limit)
+	if lb.options.Ascending {
+		return float64(elapsed)
+	}
+	return float64(limit - elapsed)
+}
+
+// encode 将积分及达成时间编码为复合分数，积分超出范围时返回错误。
+func (lb *Leaderboard) encode(points int64, at time.Time) (float64, error) {
+	limit := int64(1) << (leaderboardScoreBits - lb.options.TimeBits)
+	if points <= -limit || points >= limit {
+		return 0, fmt.Errorf("points %v of leaderboard out of range (-%v, %v)", points, limit, limit)
+	}
+	return float64(points)*lb.scale() + lb.tie(at), nil
+}
+
+// decode 将复合分数解码为积分及达成时间。
+func (lb *Leaderboard) decode(score float64) (int64, time.Time) {
+	scale := lb.scale()
+	points := math.Floor(score / scale)
+	elapsed := int64(score - points*scale)
+	if !lb.options.Ascending {
+		elapsed = int64(scale) - 1 - elapsed
+	}
+	return int64(points), lb.options.Epoch.Add(time.Duration(elapsed) * time.Second)
+}
+
+// rank 返回成员从 0 开始的排名，成员不存在时返回 ErrNotFound。
+func (lb *Leaderboard) rank(member string) (int, error) {
+	if lb.options.Ascending {
+		return lb.zset.ZRank(lb.key, member)
+	}
+	return lb.zset.ZRevRank(lb.key, member)
+}
+
+// ranged 返回从 0 开始的排名在 [start, stop] 之间的条目。
+func (lb *Leaderboard) ranged(start, stop int) ([]LeaderboardEntry, error) {
+	var members []ZMember
+	var err error
+	if lb.options.Ascending {
+		members, err = lb.zset.ZRange(lb.key, start, stop)
+	} else {
+		members, err = lb.zset.ZRevRange(lb.key, start, stop)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lb.entries(members, start), nil
+}
+
+// entries 将有序集合的成员转换为排行榜的条目，first 为第一个成员从 0 开始的排名。
+func (lb *Leaderboard) entries(members []ZMember, first int) []LeaderboardEntry {
+	rets := make([]LeaderboardEntry, len(members))
+	for i, member := range members {
+		points, at := lb.decode(member.Score)
+		rets[i] = LeaderboardEntry{Member: member.Member, Points: points, Time: at, Rank: first + i + 1}
+	}
+	return rets
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testLeaderboardMembers 返回条目的成员及排名，用于比较排行榜的排序。
+func testLeaderboardMembers(entries []LeaderboardEntry) [][2]any {
+	rets := make([][2]any, len(entries))
+	for i, entry := range entries {
+		rets[i] = [2]any{entry.Member, entry.Rank}
+	}
+	return rets
+}
+
+// testLeaderboardConformance 校验排行榜的基本行为，alias 为已注册的存储的别名。
+func testLeaderboardConformance(t *testing.T, alias string) {
+	epoch := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	lb, err := NewLeaderboard(alias, "lb:season1", LeaderboardOptions{Epoch: epoch})
+	assert.NoError(t, err, "NewLeaderboard 不应当返回错误。")
+	assert.Equal(t, "lb:season1", lb.Key(), "排行榜的键名应当和预期相等。")
+
+	lb.SetAt("alice", 100, epoch.Add(10*time.Second))
+	lb.SetAt("bob", 100, epoch.Add(5*time.Second)) // 与 alice 同分但更早达成
+	lb.SetAt("carol", 200, epoch.Add(20*time.Second))
+	lb.SetAt("dave", 50, epoch.Add(time.Second))
+	lb.SetAt("erin", -10, epoch)
+
+	entry, err := lb.Get("alice")
+	assert.NoError(t, err, "Get 不应当返回错误。")
+	assert.Equal(t, LeaderboardEntry{Member: "alice", Points: 100, Time: epoch.Add(10 * time.Second), Rank: 3}, entry, "条目应当包含积分、达成时间及排名。")
+	entry, _ = lb.Get("erin")
+	assert.Equal(t, int64(-10), entry.Points, "负数的积分应当被正确地解码。")
+	_, err = lb.Get("missing")
+	assert.ErrorIs(t, err, ErrNotFound, "不存在的成员应当返回 ErrNotFound。")
+
+	top, err := lb.Top(0, 3)
+	assert.NoError(t, err, "Top 不应当返回错误。")
+	assert.Equal(t, [][2]any{{"carol", 1}, {"bob", 2}, {"alice", 3}}, testLeaderboardMembers(top), "同分时先达成者的排名应当靠前。")
+	top, _ = lb.Top(3, 10)
+	assert.Equal(t, [][2]any{{"dave", 4}, {"erin", 5}}, testLeaderboardMembers(top), "分页读取的排名应当从 offset 开始。")
+	top, _ = lb.Top(0, 0)
+	assert.Empty(t, top, "count 为 0 时应当返回空的结果。")
+
+	around, err := lb.Around("alice", 1)
+	assert.NoError(t, err, "Around 不应当返回错误。")
+	assert.Equal(t, [][2]any{{"bob", 2}, {"alice", 3}, {"dave", 4}}, testLeaderboardMembers(around), "应当返回成员及其前后的条目。")
+	around, _ = lb.Around("carol", 2)
+	assert.Equal(t, [][2]any{{"carol", 1}, {"bob", 2}, {"alice", 3}}, testLeaderboardMembers(around), "排名靠前的成员不应当返回越界的条目。")
+	_, err = lb.Around("missing", 1)
+	assert.ErrorIs(t, err, ErrNotFound, "不存在的成员应当返回 ErrNotFound。")
+
+	ranged, err := lb.RangeByPoints(50, 100, 0, -1)
+	assert.NoError(t, err, "RangeByPoints 不应当返回错误。")
+	assert.Equal(t, [][2]any{{"bob", 2}, {"alice", 3}, {"dave", 4}}, testLeaderboardMembers(ranged), "应当按排名返回积分范围内的条目。")
+	ranged, _ = lb.RangeByPoints(50, 100, 1, 1)
+	assert.Equal(t, [][2]any{{"alice", 3}}, testLeaderboardMembers(ranged), "应当跳过 offset 个条目后最多返回 count 个。")
+	ranged, _ = lb.RangeByPoints(1000, 2000, 0, -1)
+	assert.Empty(t, ranged, "积分范围内没有成员时应当返回空的结果。")
+
+	points, err := lb.Incr("dave", 60)
+	assert.NoError(t, err, "Incr 不应当返回错误。")
+	assert.Equal(t, int64(110), points, "增加后的积分应当为 110。")
+	entry, _ = lb.Get("dave")
+	assert.Equal(t, 2, entry.Rank, "增加积分后的排名应当被更新。")
+	assert.Equal(t, epoch.Add(time.Second), entry.Time, "增加积分时应当保留首次的达成时间。")
+	points, _ = lb.Incr("frank", 30)
+	assert.Equal(t, int64(30), points, "新上榜的成员的积分应当为增加的值。")
+	entry, _ = lb.Get("frank")
+	assert.WithinDuration(t, time.Now(), entry.Time, 2*time.Second, "新上榜的成员应当以当前时间为达成时间。")
+
+	count, err := lb.Count()
+	assert.NoError(t, err, "Count 不应当返回错误。")
+	assert.Equal(t, 6, count, "成员数量应当为 6。")
+	removed, _ := lb.Remove("frank", "missing")
+	assert.Equal(t, 1, removed, "被移除的成员数量应当为 1。")
+
+	assert.Error(t, lb.Set("overflow", 1<<23), "超出范围的积分应当返回错误。")
+	_, err = lb.Incr("overflow", -(1 << 23))
+	assert.Error(t, err, "超出范围的增量应当返回错误。")
+
+	archive, err := lb.Archive("lb:season1:archive")
+	assert.NoError(t, err, "Archive 不应当返回错误。")
+	count, _ = lb.Count()
+	assert.Equal(t, 0, count, "归档后排行榜应当被重置。")
+	count, _ = archive.Count()
+	assert.Equal(t, 5, count, "归档的排行榜应当包含所有的成员。")
+	entry, _ = archive.Get("carol")
+	assert.Equal(t, 1, entry.Rank, "归档的排行榜应当保留排名。")
+	archive, err = lb.Archive("lb:season1:archive")
+	assert.NoError(t, err, "归档空的排行榜不应当返回错误。")
+	count, _ = archive.Count()
+	assert.Equal(t, 0, count, "归档空的排行榜应当覆盖已存在的归档。")
+
+	lb.Set("alice", 1)
+	assert.NoError(t, lb.Reset(), "Reset 不应当返回错误。")
+	count, _ = lb.Count()
+	assert.Equal(t, 0, count, "重置后排行榜应当为空。")
+
+	asc, _ := NewLeaderboard(alias, "lb:race", LeaderboardOptions{Ascending: true, Epoch: epoch, TimeBits: 20})
+	asc.SetAt("alice", 3200, epoch.Add(10*time.Second))
+	asc.SetAt("bob", 3200, epoch.Add(5*time.Second))
+	asc.SetAt("carol", 2900, epoch.Add(20*time.Second))
+	top, _ = asc.Top(0, 10)
+	assert.Equal(t, [][2]any{{"carol", 1}, {"bob", 2}, {"alice", 3}}, testLeaderboardMembers(top), "升序的排行榜应当按积分升序排名，同分时先达成者靠前。")
+	ranged, _ = asc.RangeByPoints(3000, 4000, 0, -1)
+	assert.Equal(t, [][2]any{{"bob", 2}, {"alice", 3}}, testLeaderboardMembers(ranged), "升序的排行榜应当按排名返回积分范围内的条目。")
+	assert.NoError(t, asc.Set("dave", 1<<32), "减少时间部分的位数后应当支持更大的积分。")
+
+	// 复合分数为 0 的成员（积分为 0 且在起点达成）不应当被视为新上榜的成员
+	asc.SetAt("zero", 0, epoch)
+	points, _ = asc.Incr("zero", 5)
+	assert.Equal(t, int64(5), points, "复合分数为 0 的成员增加后的积分应当为 5。")
+	entry, _ = asc.Get("zero")
+	assert.Equal(t, epoch, entry.Time, "复合分数为 0 的成员增加积分时应当保留首次的达成时间。")
+
+	// 存储未实现 ZIncrByInit 时先读取成员是否存在
+	plain := *asc
+	plain.zset = struct{ ISortedSet }{asc.zset}
+	points, _ = plain.Incr("zero", 5)
+	assert.Equal(t, int64(10), points, "未实现 ZIncrByInit 时增加后的积分应当为 10。")
+	entry, _ = plain.Get("zero")
+	assert.Equal(t, epoch, entry.Time, "未实现 ZIncrByInit 时应当保留首次的达成时间。")
+	plain.Incr("grace", 1)
+	entry, _ = plain.Get("grace")
+	assert.Equal(t, int64(1), entry.Points, "未实现 ZIncrByInit 时新上榜的成员的积分应当为增加的值。")
+	asc.Reset()
+}
+
+func TestLeaderboard(t *testing.T) {
+	t.Run("Encode", func(t *testing.T) {
+		Register("leaderboard_encode", NewMemory())
+		defer func() {
+			Of("leaderboard_encode").Close()
+			pairsMap.Delete("leaderboard_encode")
+		}()
+
+		epoch := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		for _, ascending := range []bool{false, true} {
+			lb, _ := NewLeaderboard("leaderboard_encode", "lb", LeaderboardOptions{Ascending: ascending, Epoch: epoch})
+			for _, points := range []int64{0, 1, -1, 12345, -(1<<23 - 1), 1<<23 - 1} {
+				for _, at := range []time.Time{epoch, epoch.Add(time.Hour), epoch.Add(-time.Hour), epoch.Add(1 << 31 * time.Second)} {
+					score, err := lb.encode(points, at)
+					assert.NoError(t, err, "编码不应当返回错误：%v %v", points, at)
+					dpoints, dat := lb.decode(score)
+					assert.Equal(t, points, dpoints, "解码的积分应当和编码的相等：%v %v", points, at)
+					expected := at
+					if at.Before(epoch) {
+						expected = epoch
+					} else if limit := epoch.Add((1<<30 - 1) * time.Second); at.After(limit) {
+						expected = limit
+					}
+					assert.Equal(t, expected, dat, "解码的时间应当被限制在可以表示的范围内：%v %v", points, at)
+				}
+			}
+		}
+		_, err := NewLeaderboard("leaderboard_encode", "lb", LeaderboardOptions{TimeBits: 53})
+		assert.Error(t, err, "时间部分的位数过大时应当返回错误。")
+	})
+
+	t.Run("Memory", func(t *testing.T) {
+		Register("leaderboard_memory", NewMemory())
+		defer func() {
+			Of("leaderboard_memory").Close()
+			pairsMap.Delete("leaderboard_memory")
+		}()
+		testLeaderboardConformance(t, "leaderboard_memory")
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		server := newTestRedisServer(t)
+		rc, _ := NewRedis(server.Addr(), 2, 8)
+		Register("leaderboard_redis", rc)
+		defer func() {
+			rc.Close()
+			pairsMap.Delete("leaderboard_redis")
+		}()
+		testLeaderboardConformance(t, "leaderboard_redis")
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := NewLeaderboard("leaderboard_missing", "lb")
+		assert.ErrorIs(t, err, ErrNotRegistered, "未注册的别名应当返回 ErrNotRegistered。")
+		Register("leaderboard_consul", &ConsulClient{})
+		defer pairsMap.Delete("leaderboard_consul")
+		_, err = NewLeaderboard("leaderboard_consul", "lb")
+		assert.ErrorIs(t, err, ErrNotSupported, "存储未实现 ISortedSet 时应当返回 ErrNotSupported。")
+	})
+}
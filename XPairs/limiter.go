@@ -0,0 +1,232 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ILimiter 是限流及计数的原子操作的接口，Redis 使用 Lua 脚本实现，内存存储使用互斥锁实现。
+// 限流器的状态存储于普通的键中：令牌桶使用哈希表，滑动窗口使用有序集合，计数器使用字符串，状态在不再需要时自动过期。
+// now 为调用方的当前时间，多个实例共享限流器时应当保持时钟同步。
+type ILimiter interface {
+	// TakeToken 从键对应的令牌桶中取出 n 个令牌，桶的容量为 burst，每秒补充 rate 个令牌，令牌不足时不取出。
+	// n 大于 burst 时总是被拒绝，且 RetryAfter 为 -1。
+	TakeToken(key string, rate float64, burst, n int, now time.Time) (LimitResult, error)
+
+	// TakeWindow 在键对应的滑动窗口中记录 n 次请求，窗口内最多允许 limit 次请求，超出时不记录。
+	// n 大于 limit 时总是被拒绝，且 RetryAfter 为 -1。
+	TakeWindow(key string, limit int, window time.Duration, n int, now time.Time) (LimitResult, error)
+
+	// IncrExpire 将键的整数值增加 delta 并返回增加后的值，键不存在时视为 0，键未设置过期时间时将其设置为 ttl。
+	IncrExpire(key string, delta int64, ttl time.Duration) (int64, error)
+}
+
+// LimitResult 是限流的判断结果。
+type LimitResult struct {
+	Allowed    bool          // 是否允许请求
+	Remaining  int           // 判断后剩余可用的配额
+	RetryAfter time.Duration // 被拒绝时距离配额足够的等待时间，请求的数量超过容量时为 -1
+}
+
+var (
+	// limiterAllowed 定义了限流器的计数器，用于统计各限流器允许的请求数量。
+	limiterAllowed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "xpairs_limiter_allowed_total",
+		Help: "The total number of requests allowed by the limiter.",
+	}, []string{"limiter"})
+
+	// limiterRejected 定义了限流器的计数器，用于统计各限流器拒绝的请求数量。
+	limiterRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "xpairs_limiter_rejected_total",
+		Help: "The total number of requests rejected by the limiter.",
+	}, []string{"limiter"})
+)
+
+func init() { prometheus.MustRegister(limiterAllowed, limiterRejected) }
+
+// limiterFor 返回指定别名的存储及其实现的 ILimiter。
+func limiterFor(alias string) (IPairs, ILimiter, error) {
+	limiter, err := structFor[ILimiter](alias, "limiter")
+	if err != nil {
+		return nil, nil, err
+	}
+	return Of(alias), limiter, nil
+}
+
+// limiterBase 是限流器的公共部分。
+type limiterBase struct {
+	pairs   IPairs
+	limiter ILimiter
+	name    string
+	now     func() time.Time
+}
+
+// newLimiterBase 创建限流器的公共部分。
+func newLimiterBase(alias, name string) (limiterBase, error) {
+	pairs, limiter, err := limiterFor(alias)
+	if err != nil {
+		return limiterBase{}, err
+	}
+	return limiterBase{pairs: pairs, limiter: limiter, name: name, now: time.Now}, nil
+}
+
+// Name 返回限流器的名称。
+func (lb *limiterBase) Name() string { return lb.name }
+
+// Reset 清除键的限流状态。
+func (lb *limiterBase) Reset(key string) error {
+	_, err := lb.pairs.Delete(lb.key(key))
+	return err
+}
+
+// key 返回键在存储中的键名，即限流器的名称加上键。
+func (lb *limiterBase) key(key string) string { return lb.name + ":" + key }
+
+// take 校验请求的数量并执行限流的判断，统计允许及拒绝的请求数量。
+func (lb *limiterBase) take(key string, n int, fn func(key string, now time.Time) (LimitResult, error)) (LimitResult, error) {
+	if n <= 0 {
+		return LimitResult{}, fmt.Errorf("invalid count %v of limiter %v", n, lb.name)
+	}
+	result, err := fn(lb.key(key), lb.now())
+	if err != nil {
+		return result, err
+	}
+	if result.Allowed {
+		limiterAllowed.WithLabelValues(lb.name).Add(float64(n))
+	} else {
+		limiterRejected.WithLabelValues(lb.name).Add(float64(n))
+	}
+	return result, nil
+}
+
+// TokenBucket 是基于令牌桶的限流器，允许短时间的突发请求，长期的速率不超过每秒补充的令牌数量，适用于聊天消息等场景。
+// 多个实例使用同一存储及名称的限流器时共享配额；每个键（如用户 ID）拥有独立的令牌桶。
+type TokenBucket struct {
+	limiterBase
+	rate  float64
+	burst int
+}
+
+// NewTokenBucket 创建令牌桶限流器，name 为限流器的名称（同时作为键名的前缀及指标的标签），
+// rate 为每秒补充的令牌数量，burst 为桶的容量，新的令牌桶是满的。
+// 别名未注册时返回 ErrNotRegistered，存储未实现 ILimiter 时返回 ErrNotSupported。
+func NewTokenBucket(alias, name string, rate float64, burst int) (*TokenBucket, error) {
+	if rate <= 0 || burst <= 0 {
+		return nil, fmt.Errorf("invalid rate %v or burst %v of token bucket", rate, burst)
+	}
+	base, err := newLimiterBase(alias, name)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenBucket{limiterBase: base, rate: rate, burst: burst}, nil
+}
+
+// Allow 判断键的一次请求是否被允许。
+func (tb *TokenBucket) Allow(key string) (bool, error) {
+	result, err := tb.AllowN(key, 1)
+	return result.Allowed, err
+}
+
+// AllowN 判断键的 n 次请求是否被允许，允许时取出 n 个令牌，n 必须大于 0。
+func (tb *TokenBucket) AllowN(key string, n int) (LimitResult, error) {
+	return tb.take(key, n, func(key string, now time.Time) (LimitResult, error) {
+		return tb.limiter.TakeToken(key, tb.rate, tb.burst, n, now)
+	})
+}
+
+// SlidingWindow 是基于滑动窗口的限流器，任意长度为 window 的时间段内最多允许 limit 次请求，适用于登录尝试等需要精确计数的场景。
+// 窗口内的每次请求均被记录，因此 limit 不宜过大；多个实例使用同一存储及名称的限流器时共享配额。
+type SlidingWindow struct {
+	limiterBase
+	limit  int
+	window time.Duration
+}
+
+// NewSlidingWindow 创建滑动窗口限流器，name 为限流器的名称（同时作为键名的前缀及指标的标签），
+// limit 为窗口内允许的请求数量，window 为窗口的长度，精确到毫秒。
+// 别名未注册时返回 ErrNotRegistered，存储未实现 ILimiter 时返回 ErrNotSupported。
+func NewSlidingWindow(alias, name string, limit int, window time.Duration) (*SlidingWindow, error) {
+	if limit <= 0 || window < time.Millisecond {
+		return nil, fmt.Errorf("invalid limit %v or window %v of sliding window", limit, window)
+	}
+	base, err := newLimiterBase(alias, name)
+	if err != nil {
+		return nil, err
+	}
+	return &SlidingWindow{limiterBase: base, limit: limit, window: window}, nil
+}
+
+// Allow 判断键的一次请求是否被允许。
+func (sw *SlidingWindow) Allow(key string) (bool, error) {
+	result, err := sw.AllowN(key, 1)
+	return result.Allowed, err
+}
+
+// AllowN 判断键的 n 次请求是否被允许，允许时记录 n 次请求，n 必须大于 0。
+func (sw *SlidingWindow) AllowN(key string, n int) (LimitResult, error) {
+	return sw.take(key, n, func(key string, now time.Time) (LimitResult, error) {
+		return sw.limiter.TakeWindow(key, sw.limit, sw.window, n, now)
+	})
+}
+
+// WindowCounter 是基于固定窗口的计数器，窗口按 Unix 时间对齐（如按天的窗口在 UTC 零点切换），窗口结束后计数自动过期。
+// 适用于每日抽卡次数等按周期统计的场景，计数的增加是原子的，可以被多个实例并发地调用。
+type WindowCounter struct {
+	limiterBase
+	window time.Duration
+}
+
+// NewWindowCounter 创建固定窗口计数器，name 为计数器的名称（同时作为键名的前缀），window 为窗口的长度，精确到毫秒。
+// 别名未注册时返回 ErrNotRegistered，存储未实现 ILimiter 时返回 ErrNotSupported。
+func NewWindowCounter(alias, name string, window time.Duration) (*WindowCounter, error) {
+	if window < time.Millisecond {
+		return nil, fmt.Errorf("invalid window %v of window counter", window)
+	}
+	base, err := newLimiterBase(alias, name)
+	if err != nil {
+		return nil, err
+	}
+	return &WindowCounter{limiterBase: base, window: window}, nil
+}
+
+// Incr 将键在当前窗口的计数增加 delta，返回增加后的计数。
+func (wc *WindowCounter) Incr(key string, delta int64) (int64, error) {
+	wkey, ttl := wc.current(key)
+	return wc.limiter.IncrExpire(wkey, delta, ttl)
+}
+
+// Get 返回键在当前窗口的计数，未计数时返回 0。
+func (wc *WindowCounter) Get(key string) (int64, error) {
+	wkey, _ := wc.current(key)
+	value, err := wc.pairs.Get(wkey)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// Reset 清除键在当前窗口的计数。
+func (wc *WindowCounter) Reset(key string) error {
+	wkey, _ := wc.current(key)
+	_, err := wc.pairs.Delete(wkey)
+	return err
+}
+
+// current 返回键在当前窗口的键名及窗口的剩余时间，键名为计数器的名称、键及窗口的序号。
+func (wc *WindowCounter) current(key string) (string, time.Duration) {
+	window := wc.window.Milliseconds()
+	now := wc.now().UnixMilli()
+	index := now / window
+	ttl := time.Duration(window-now%window) * time.Millisecond
+	return wc.key(key) + ":" + strconv.FormatInt(index, 10), ttl
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRedisTokenBucket 是令牌桶脚本的等价实现。
+func testRedisTokenBucket(s *testRedisServer, keys, args []string) any {
+	rate, _ := strconv.ParseFloat(args[0], 64)
+	burst, _ := strconv.ParseFloat(args[1], 64)
+	n, _ := strconv.ParseFloat(args[2], 64)
+	now, _ := strconv.ParseFloat(args[3], 64)
+	reply := s.exec("HGET", []string{keys[0], "tokens"})
+	if rerr, ok := reply.(RedisError); ok {
+		return rerr
+	}
+	str, _ := reply.(string)
+	tokens, terr := strconv.ParseFloat(str, 64)
+	str, _ = s.exec("HGET", []string{keys[0], "time"}).(string)
+	last, lerr := strconv.ParseFloat(str, 64)
+	if terr != nil || lerr != nil {
+		tokens, last = burst, now
+	}
+	if now > last {
+		tokens = min(burst, tokens+(now-last)*rate/1000)
+		last = now
+	}
+	allowed, retry := 0, 0.0
+	if tokens >= n {
+		tokens -= n
+		allowed = 1
+	} else if n > burst {
+		retry = -1
+	} else {
+		retry = math.Ceil((n - tokens) * 1000 / rate)
+	}
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	s.exec("HSET", []string{keys[0], "tokens", format(tokens), "time", format(last)})
+	s.exec("PEXPIRE", []string{keys[0], format(max(math.Ceil((burst-tokens)*1000/rate), 1))})
+	return []any{allowed, int(math.Floor(tokens)), int(retry)}
+}
+
+// testRedisSlidingWindow 是滑动窗口脚本的等价实现。
+func testRedisSlidingWindow(s *testRedisServer, keys, args []string) any {
+	limit, _ := strconv.Atoi(args[0])
+	window, _ := strconv.Atoi(args[1])
+	n, _ := strconv.Atoi(args[2])
+	now, _ := strconv.Atoi(args[3])
+	if rerr, ok := s.exec("ZREMRANGEBYSCORE", []string{keys[0], "-inf", strconv.Itoa(now - window)}).(RedisError); ok {
+		return rerr
+	}
+	count := s.exec("ZCARD", keys[:1]).(int)
+	if count+n <= limit {
+		for i := 1; i <= n; i++ {
+			s.exec("ZADD", []string{keys[0], strconv.Itoa(now), args[4] + ":" + strconv.Itoa(i)})
+		}
+		s.exec("PEXPIRE", []string{keys[0], strconv.Itoa(window)})
+		return []any{1, limit - count - n, 0}
+	}
+	if n > limit {
+		return []any{0, max(limit-count, 0), -1}
+	}
+	index := strconv.Itoa(count + n - limit - 1)
+	oldest := s.exec("ZRANGE", []string{keys[0], index, index, "WITHSCORES"}).([]any)
+	score, _ := strconv.Atoi(oldest[1].(string))
+	return []any{0, max(limit-count, 0), score + window - now}
+}
+
+// testRedisIncrExpire 是增加计数并设置过期时间的脚本的等价实现。
+func testRedisIncrExpire(s *testRedisServer, keys, args []string) any {
+	reply := s.exec("INCRBY", []string{keys[0], args[0]})
+	if _, ok := reply.(RedisError); ok {
+		return reply
+	}
+	if s.exec("PTTL", keys[:1]) == -1 {
+		s.exec("PEXPIRE", []string{keys[0], args[1]})
+	}
+	return reply
+}
+
+// testLimiterConformance 校验限流器及计数器的基本行为，alias 为已注册的存储的别名。
+func testLimiterConformance(t *testing.T, alias string) {
+	pairs := Of(alias)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("TokenBucket", func(t *testing.T) {
+		tb, err := NewTokenBucket(alias, alias+":chat", 10, 5)
+		assert.NoError(t, err, "NewTokenBucket 不应当返回错误。")
+		assert.Equal(t, alias+":chat", tb.Name(), "限流器的名称应当和预期相等。")
+		tb.now = clock
+		allowed := testutil.ToFloat64(limiterAllowed.WithLabelValues(tb.Name()))
+		rejected := testutil.ToFloat64(limiterRejected.WithLabelValues(tb.Name()))
+
+		for i := 0; i < 5; i++ {
+			ok, err := tb.Allow("alice")
+			assert.NoError(t, err, "Allow 不应当返回错误。")
+			assert.True(t, ok, "新的令牌桶应当允许 burst 次突发的请求：%v", i)
+		}
+		result, err := tb.AllowN("alice", 1)
+		assert.NoError(t, err, "AllowN 不应当返回错误。")
+		assert.Equal(t, LimitResult{Allowed: false, Remaining: 0, RetryAfter: 100 * time.Millisecond}, result, "令牌耗尽时应当被拒绝并返回补充令牌的等待时间。")
+		ok, _ := tb.Allow("bob")
+		assert.True(t, ok, "不同的键应当拥有独立的令牌桶。")
+		ttl, err := pairs.TTL(alias + ":chat:alice")
+		assert.NoError(t, err, "令牌桶的状态应当存储于限流器名称加上键的键中。")
+		assert.Greater(t, ttl, time.Duration(0), "令牌桶的状态应当设置过期时间。")
+
+		now = now.Add(250 * time.Millisecond) // 补充 2.5 个令牌
+		result, _ = tb.AllowN("alice", 3)
+		assert.Equal(t, LimitResult{Allowed: false, Remaining: 2, RetryAfter: 50 * time.Millisecond}, result, "令牌不足时不应当取出令牌。")
+		result, _ = tb.AllowN("alice", 2)
+		assert.Equal(t, LimitResult{Allowed: true, Remaining: 0}, result, "令牌足够时应当取出令牌。")
+		now = now.Add(time.Hour)
+		result, _ = tb.AllowN("alice", 1)
+		assert.Equal(t, LimitResult{Allowed: true, Remaining: 4}, result, "补充的令牌不应当超过桶的容量。")
+		result, _ = tb.AllowN("alice", 6)
+		assert.Equal(t, LimitResult{Allowed: false, Remaining: 4, RetryAfter: -1}, result, "请求的数量超过容量时应当总是被拒绝。")
+		_, err = tb.AllowN("alice", 0)
+		assert.Error(t, err, "请求的数量小于等于 0 时应当返回错误。")
+
+		assert.NoError(t, tb.Reset("alice"), "Reset 不应当返回错误。")
+		result, _ = tb.AllowN("alice", 5)
+		assert.True(t, result.Allowed, "重置后令牌桶应当是满的。")
+
+		assert.Equal(t, allowed+14, testutil.ToFloat64(limiterAllowed.WithLabelValues(tb.Name())), "允许的请求数量应当被统计。")
+		assert.Equal(t, rejected+10, testutil.ToFloat64(limiterRejected.WithLabelValues(tb.Name())), "拒绝的请求数量应当被统计。")
+
+		pairs.Set(alias+":chat:str", "v", 0)
+		_, err = tb.Allow("str")
+		assert.ErrorIs(t, err, ErrWrongType, "键的类型不匹配时应当返回 ErrWrongType。")
+
+		var count atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if ok, _ := tb.Allow("concurrent"); ok {
+					count.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(5), count.Load(), "并发的请求应当只有 burst 次被允许。")
+	})
+
+	t.Run("SlidingWindow", func(t *testing.T) {
+		sw, err := NewSlidingWindow(alias, alias+":login", 3, time.Second)
+		assert.NoError(t, err, "NewSlidingWindow 不应当返回错误。")
+		sw.now = clock
+		rejected := testutil.ToFloat64(limiterRejected.WithLabelValues(sw.Name()))
+
+		start := now
+		result, err := sw.AllowN("alice", 2)
+		assert.NoError(t, err, "AllowN 不应当返回错误。")
+		assert.Equal(t, LimitResult{Allowed: true, Remaining: 1}, result, "窗口内的请求数量未超过上限时应当被允许。")
+		now = start.Add(400 * time.Millisecond)
+		ok, _ := sw.Allow("alice")
+		assert.True(t, ok, "窗口内的请求数量未超过上限时应当被允许。")
+		now = start.Add(500 * time.Millisecond)
+		result, _ = sw.AllowN("alice", 1)
+		assert.Equal(t, LimitResult{Allowed: false, Remaining: 0, RetryAfter: 500 * time.Millisecond}, result, "超过上限时应当返回最早的请求离开窗口的等待时间。")
+		result, _ = sw.AllowN("alice", 3)
+		assert.Equal(t, LimitResult{Allowed: false, Remaining: 0, RetryAfter: 900 * time.Millisecond}, result, "等待时间应当足够容纳请求的数量。")
+		ok, _ = sw.Allow("bob")
+		assert.True(t, ok, "不同的键应当拥有独立的窗口。")
+
+		now = start.Add(time.Second)
+		result, _ = sw.AllowN("alice", 2)
+		assert.Equal(t, LimitResult{Allowed: true, Remaining: 0}, result, "离开窗口的请求不应当被计数。")
+		result, _ = sw.AllowN("alice", 4)
+		assert.Equal(t, LimitResult{Allowed: false, Remaining: 0, RetryAfter: -1}, result, "请求的数量超过上限时应当总是被拒绝。")
+		assert.Equal(t, rejected+8, testutil.ToFloat64(limiterRejected.WithLabelValues(sw.Name())), "拒绝的请求数量应当被统计。")
+
+		assert.NoError(t, sw.Reset("alice"), "Reset 不应当返回错误。")
+		ok, _ = sw.Allow("alice")
+		assert.True(t, ok, "重置后窗口应当为空。")
+	})
+
+	t.Run("WindowCounter", func(t *testing.T) {
+		wc, err := NewWindowCounter(alias, alias+":gacha", time.Hour)
+		assert.NoError(t, err, "NewWindowCounter 不应当返回错误。")
+		wc.now = clock
+		now = time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC)
+
+		value, err := wc.Incr("alice", 3)
+		assert.NoError(t, err, "Incr 不应当返回错误。")
+		assert.Equal(t, int64(3), value, "增加后的计数应当为 3。")
+		value, _ = wc.Incr("alice", 2)
+		assert.Equal(t, int64(5), value, "增加后的计数应当为 5。")
+		value, err = wc.Get("alice")
+		assert.NoError(t, err, "Get 不应当返回错误。")
+		assert.Equal(t, int64(5), value, "当前窗口的计数应当为 5。")
+		key := alias + ":gacha:alice:" + strconv.FormatInt(now.UnixMilli()/time.Hour.Milliseconds(), 10)
+		ttl, _ := pairs.TTL(key)
+		assert.InDelta(t, float64(30*time.Minute), float64(ttl), float64(time.Second), "计数应当在窗口结束时过期。")
+		value, _ = wc.Get("bob")
+		assert.Equal(t, int64(0), value, "未计数的键应当返回 0。")
+
+		now = now.Add(30 * time.Minute)
+		value, _ = wc.Get("alice")
+		assert.Equal(t, int64(0), value, "新的窗口的计数应当从 0 开始。")
+		value, _ = wc.Incr("alice", 1)
+		assert.Equal(t, int64(1), value, "新的窗口的计数应当从 0 开始。")
+		assert.NoError(t, wc.Reset("alice"), "Reset 不应当返回错误。")
+		value, _ = wc.Get("alice")
+		assert.Equal(t, int64(0), value, "重置后的计数应当为 0。")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				wc.Incr("concurrent", 1)
+			}()
+		}
+		wg.Wait()
+		value, _ = wc.Get("concurrent")
+		assert.Equal(t, int64(20), value, "并发的增加不应当丢失计数。")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewTokenBucket(alias, "invalid", 0, 1)
+		assert.Error(t, err, "速率小于等于 0 时应当返回错误。")
+		_, err = NewTokenBucket(alias, "invalid", 1, 0)
+		assert.Error(t, err, "容量小于等于 0 时应当返回错误。")
+		_, err = NewSlidingWindow(alias, "invalid", 0, time.Second)
+		assert.Error(t, err, "上限小于等于 0 时应当返回错误。")
+		_, err = NewSlidingWindow(alias, "invalid", 1, time.Microsecond)
+		assert.Error(t, err, "窗口小于 1 毫秒时应当返回错误。")
+		_, err = NewWindowCounter(alias, "invalid", 0)
+		assert.Error(t, err, "窗口小于 1 毫秒时应当返回错误。")
+	})
+}
+
+func TestLimiter(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		Register("limiter_memory", NewMemory())
+		defer func() {
+			Of("limiter_memory").Close()
+			pairsMap.Delete("limiter_memory")
+		}()
+		testLimiterConformance(t, "limiter_memory")
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		server := newTestRedisServer(t)
+		rc, _ := NewRedis(server.Addr(), 2, 8)
+		Register("limiter_redis", rc)
+		defer func() {
+			rc.Close()
+			pairsMap.Delete("limiter_redis")
+		}()
+
+		server.mutex.Lock()
+		server.commands = nil
+		server.mutex.Unlock()
+		rc.IncrExpire("script", 1, time.Minute)
+		rc.IncrExpire("script", 1, time.Minute)
+		server.mutex.Lock()
+		assert.Equal(t, []string{"EVALSHA", "EVAL", "EVALSHA"}, server.commands, "服务端未缓存脚本时应当回退至 EVAL，之后使用 EVALSHA。")
+		server.mutex.Unlock()
+
+		testLimiterConformance(t, "limiter_redis")
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := NewTokenBucket("limiter_missing", "lb", 1, 1)
+		assert.ErrorIs(t, err, ErrNotRegistered, "未注册的别名应当返回 ErrNotRegistered。")
+		Register("limiter_consul", &ConsulClient{})
+		defer pairsMap.Delete("limiter_consul")
+		_, err = NewWindowCounter("limiter_consul", "counter", time.Hour)
+		assert.ErrorIs(t, err, ErrNotSupported, "存储未实现 ILimiter 时应当返回 ErrNotSupported。")
+	})
+}
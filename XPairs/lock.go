@@ -0,0 +1,285 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// LockRetry 是 WaitLock 等待锁时的重试间隔。
+	LockRetry = 100 * time.Millisecond
+
+	// LockFenceSuffix 是防护令牌计数器的键名后缀，计数器存储于锁的键名加上该后缀的键中，且不会过期。
+	LockFenceSuffix = ":fence"
+
+	// lockFenceRetry 是存储不支持原子递增时，递增防护令牌的 CAS 操作的最大重试次数。
+	lockFenceRetry = 100
+)
+
+// ILocker 是分布式锁的接口，存储实现该接口时 Lock 及 WaitLock 将使用其原生的锁（如 Consul 的会话）。
+type ILocker interface {
+	// Lock 尝试获取键的锁，ttl 为租约的有效期，锁已被其他持有者获取时返回 ErrLocked。
+	Lock(key string, ttl time.Duration) (*Lease, error)
+}
+
+// Lease 是分布式锁的租约，持有者需要在有效期内调用 Renew 续约，或者使用 KeepAlive 自动续约。
+// 租约包含单调递增的防护令牌（Fencing Token），持有者写入受保护的资源时应当携带该令牌，
+// 资源方拒绝令牌小于已见令牌的写入，以避免租约过期后的旧持有者覆盖新持有者的数据。
+type Lease struct {
+	key      string
+	token    uint64
+	ttl      time.Duration
+	expire   time.Time
+	released bool // 是否已失效
+	unlocked bool // 是否已被持有者主动释放
+	renew    func() error
+	unlock   func() error
+	keep     chan struct{}
+	mutex    sync.Mutex
+}
+
+// newLease 创建租约，start 为获取锁之前的时间，用于保守地计算本地的有效期。
+func newLease(key string, token uint64, ttl time.Duration, start time.Time) *Lease {
+	return &Lease{key: key, token: token, ttl: ttl, expire: start.Add(ttl)}
+}
+
+// Key 返回锁的键名。
+func (l *Lease) Key() string { return l.key }
+
+// Token 返回防护令牌，同一个键后获取的租约的令牌总是更大。
+func (l *Lease) Token() uint64 { return l.token }
+
+// TTL 返回租约的有效期。
+func (l *Lease) TTL() time.Duration { return l.ttl }
+
+// Valid 判断租约在本地是否仍然有效，即未被释放且未超过有效期。
+// 需要注意的是，本地有效不代表存储中的锁仍被持有，如键被手动删除的情况。
+func (l *Lease) Valid() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return !l.released && time.Now().Before(l.expire)
+}
+
+// Renew 续约并重置有效期，租约已失效时返回 ErrLockLost。
+// 续约的网络请求在租约的互斥锁之外执行，期间 Valid 等调用不会被阻塞，续约期间租约被释放时同样返回 ErrLockLost。
+func (l *Lease) Renew() error {
+	l.mutex.Lock()
+	if l.released {
+		l.mutex.Unlock()
+		return ErrLockLost
+	}
+	renew := l.renew
+	l.mutex.Unlock()
+
+	start := time.Now()
+	err := renew()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if errors.Is(err, ErrLockLost) {
+		l.release()
+		return err
+	} else if err != nil {
+		return err
+	}
+	if l.released {
+		return ErrLockLost
+	}
+	if expire := start.Add(l.ttl); expire.After(l.expire) { // 并发的续约以较晚的有效期为准
+		l.expire = expire
+	}
+	return nil
+}
+
+// Unlock 释放锁并停止自动续约，租约已失效时返回 ErrLockLost。
+// 租约在本地被标记为失效后再执行释放的网络请求，请求失败时租约亦不再有效。
+func (l *Lease) Unlock() error {
+	l.mutex.Lock()
+	if l.released {
+		l.mutex.Unlock()
+		return ErrLockLost
+	}
+	l.release()
+	l.unlocked = true
+	unlock := l.unlock
+	l.mutex.Unlock()
+	return unlock()
+}
+
+// KeepAlive 在后台以有效期的 1/3 为间隔自动续约，直至调用 Unlock 或租约失效，重复调用将被忽略。
+// lost 在租约失效时被回调（可以为 nil），此时持有者应当立即停止访问受保护的资源；续约的网络错误将在下次间隔时重试。
+func (l *Lease) KeepAlive(lost func(err error)) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.keep != nil || l.released {
+		return
+	}
+	l.keep = make(chan struct{})
+	keep := l.keep
+	go func() {
+		ticker := time.NewTicker(max(l.ttl/3, time.Millisecond))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-keep:
+				return
+			case <-ticker.C:
+			}
+			err := l.Renew()
+			if err == nil {
+				continue
+			}
+			if errors.Is(err, ErrLockLost) || !l.Valid() {
+				l.mutex.Lock()
+				l.release()
+				unlocked := l.unlocked
+				l.mutex.Unlock()
+				if !unlocked && lost != nil {
+					lost(err)
+				}
+				return
+			}
+		}
+	}()
+}
+
+// release 将租约标记为失效并停止自动续约，调用者需持有锁。
+func (l *Lease) release() {
+	l.released = true
+	if l.keep != nil {
+		select {
+		case <-l.keep:
+		default:
+			close(l.keep)
+		}
+	}
+}
+
+// Lock 尝试获取指定别名的存储中键的锁，ttl 为租约的有效期。
+// 存储实现了 ILocker 时使用其原生的锁，否则基于 IPairs 的 SetNX 及 CAS 操作实现。
+// 别名未注册时返回 ErrNotRegistered，锁已被其他持有者获取时返回 ErrLocked。
+func Lock(alias, key string, ttl time.Duration) (*Lease, error) {
+	pairs := Of(alias)
+	if pairs == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRegistered, alias)
+	}
+	if locker, ok := pairs.(ILocker); ok {
+		return locker.Lock(key, ttl)
+	}
+	return lockPairs(pairs, key, ttl)
+}
+
+// WaitLock 获取指定别名的存储中键的锁，锁已被其他持有者获取时以 LockRetry 为间隔重试，
+// 直至超过 timeout 后返回 ErrLocked，其他规则与 Lock 相同。
+func WaitLock(alias, key string, ttl, timeout time.Duration) (*Lease, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		lease, err := Lock(alias, key, ttl)
+		if !errors.Is(err, ErrLocked) || !time.Now().Before(deadline) {
+			return lease, err
+		}
+		time.Sleep(min(LockRetry, time.Until(deadline)))
+	}
+}
+
+// Lock 尝试获取键的锁，基于 SET NX PX 命令实现，ttl 为租约的有效期，锁已被其他持有者获取时返回 ErrLocked。
+func (rc *RedisClient) Lock(key string, ttl time.Duration) (*Lease, error) {
+	return lockPairs(rc, key, ttl)
+}
+
+// Lock 尝试获取键的锁，ttl 为租约的有效期，锁已被其他持有者获取时返回 ErrLocked。
+func (mc *MemoryClient) Lock(key string, ttl time.Duration) (*Lease, error) {
+	return lockPairs(mc, key, ttl)
+}
+
+// lockPairs 基于 IPairs 的原语实现锁：先递增防护令牌，再以 SetNX 写入令牌及随机数组成的持有者标识，
+// 续约及释放时使用 CompareAndSwap 及 CompareAndDelete 校验持有者标识。
+func lockPairs(pairs IPairs, key string, ttl time.Duration) (*Lease, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("invalid lock ttl %v", ttl)
+	}
+	start := time.Now()
+	token, err := lockFence(pairs, key+LockFenceSuffix)
+	if err != nil {
+		return nil, err
+	}
+	owner := strconv.FormatUint(token, 10) + ":" + lockNonce()
+	ok, err := pairs.SetNX(key, owner, ttl)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrLocked, key)
+	}
+
+	lease := newLease(key, token, ttl, start)
+	lease.renew = func() error {
+		ok, err := pairs.CompareAndSwap(key, owner, owner, ttl)
+		if err == nil && !ok {
+			err = fmt.Errorf("%w: %v", ErrLockLost, key)
+		}
+		return err
+	}
+	lease.unlock = func() error {
+		ok, err := pairs.CompareAndDelete(key, owner)
+		if err == nil && !ok {
+			err = fmt.Errorf("%w: %v", ErrLockLost, key)
+		}
+		return err
+	}
+	return lease, nil
+}
+
+// lockFence 递增防护令牌的计数器，返回递增后的值。
+// 存储支持原子递增（如 Redis 的 INCRBY）时直接递增，否则使用 Get 及 CompareAndSwap 递增，竞争激烈时可能在重试 lockFenceRetry 次后失败。
+func lockFence(pairs IPairs, key string) (uint64, error) {
+	if incr, ok := pairs.(interface {
+		IncrBy(key string, delta int64) (int64, error)
+	}); ok {
+		token, err := incr.IncrBy(key, 1)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(token), nil
+	}
+	for i := 0; i < lockFenceRetry; i++ {
+		value, err := pairs.Get(key)
+		if errors.Is(err, ErrNotFound) {
+			ok, err := pairs.SetNX(key, "1", 0)
+			if err != nil {
+				return 0, err
+			} else if ok {
+				return 1, nil
+			}
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		token, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid lock fence %v of %v", value, key)
+		}
+		ok, err := pairs.CompareAndSwap(key, value, strconv.FormatUint(token+1, 10), 0)
+		if err != nil {
+			return 0, err
+		} else if ok {
+			return token + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("increase lock fence of %v failed after %v retries", key, lockFenceRetry)
+}
+
+// lockNonce 返回随机的持有者标识。
+func lockNonce() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testLockConformance 校验锁的基本行为，expire 用于使租约在存储中失效（如删除键或销毁会话）。
+func testLockConformance(t *testing.T, locker ILocker, ttl time.Duration, expire func(key string)) {
+	t.Run("Basic", func(t *testing.T) {
+		lease, err := locker.Lock("lock/basic", ttl)
+		assert.NoError(t, err, "Lock 不应当返回错误。")
+		assert.Equal(t, "lock/basic", lease.Key(), "租约的键名应当和预期相等。")
+		assert.True(t, lease.Token() > 0, "防护令牌应当大于 0。")
+		assert.True(t, lease.Valid(), "获取的租约应当有效。")
+
+		_, err = locker.Lock("lock/basic", ttl)
+		assert.ErrorIs(t, err, ErrLocked, "锁已被获取时应当返回 ErrLocked。")
+
+		assert.NoError(t, lease.Renew(), "Renew 不应当返回错误。")
+		assert.NoError(t, lease.Unlock(), "Unlock 不应当返回错误。")
+		assert.False(t, lease.Valid(), "释放后的租约应当无效。")
+		assert.ErrorIs(t, lease.Unlock(), ErrLockLost, "重复的 Unlock 应当返回 ErrLockLost。")
+		assert.ErrorIs(t, lease.Renew(), ErrLockLost, "释放后的 Renew 应当返回 ErrLockLost。")
+
+		next, err := locker.Lock("lock/basic", ttl)
+		assert.NoError(t, err, "释放后 Lock 不应当返回错误。")
+		assert.Greater(t, next.Token(), lease.Token(), "后获取的租约的防护令牌应当更大。")
+		next.Unlock()
+
+		_, err = locker.Lock("lock/basic", 0)
+		assert.Error(t, err, "无效的有效期应当返回错误。")
+	})
+
+	t.Run("Lost", func(t *testing.T) {
+		lease, _ := locker.Lock("lock/lost", ttl)
+		expire("lock/lost")
+		next, err := locker.Lock("lock/lost", ttl)
+		assert.NoError(t, err, "租约失效后 Lock 不应当返回错误。")
+		assert.Greater(t, next.Token(), lease.Token(), "后获取的租约的防护令牌应当更大。")
+		assert.ErrorIs(t, lease.Renew(), ErrLockLost, "失效的租约 Renew 应当返回 ErrLockLost。")
+		assert.False(t, lease.Valid(), "失效的租约应当无效。")
+		assert.NoError(t, next.Unlock(), "新的租约 Unlock 不应当返回错误。")
+
+		lease, _ = locker.Lock("lock/lost", ttl)
+		expire("lock/lost")
+		assert.ErrorIs(t, lease.Unlock(), ErrLockLost, "失效的租约 Unlock 应当返回 ErrLockLost。")
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var count atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := locker.Lock("lock/concurrent", ttl); err == nil {
+					count.Add(1)
+				} else {
+					assert.ErrorIs(t, err, ErrLocked, "并发的 Lock 失败时应当返回 ErrLocked。")
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), count.Load(), "并发的 Lock 应当仅有一个成功。")
+	})
+}
+
+func TestLock(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		mc := NewMemory()
+		defer mc.Close()
+		testLockConformance(t, mc, time.Minute, func(key string) { mc.Delete(key) })
+
+		lease, _ := mc.Lock("lock/ttl", 50*time.Millisecond)
+		time.Sleep(80 * time.Millisecond)
+		assert.False(t, lease.Valid(), "超过有效期的租约应当无效。")
+		_, err := mc.Lock("lock/ttl", time.Minute)
+		assert.NoError(t, err, "租约过期后 Lock 不应当返回错误。")
+		assert.ErrorIs(t, lease.Renew(), ErrLockLost, "过期的租约 Renew 应当返回 ErrLockLost。")
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		server := newTestRedisServer(t)
+		rc, _ := NewRedis(server.Addr(), 2, 8)
+		defer rc.Close()
+		testLockConformance(t, rc, time.Minute, func(key string) { rc.Delete(key) })
+
+		value, err := rc.Get("lock/basic" + LockFenceSuffix)
+		assert.NoError(t, err, "防护令牌的计数器应当被存储。")
+		assert.Equal(t, "3", value, "防护令牌的计数器应当和预期相等（获取失败时亦会递增）。")
+		server.mutex.Lock()
+		assert.Contains(t, server.commands, "SET", "获取锁时应当执行 SET NX PX。")
+		assert.Contains(t, server.commands, "INCRBY", "递增防护令牌时应当执行 INCRBY。")
+		server.mutex.Unlock()
+	})
+
+	t.Run("Consul", func(t *testing.T) {
+		server := newTestConsulServer(t)
+		cc, _ := NewConsul(server.URL, 2, 8)
+		defer cc.Close()
+		testLockConformance(t, cc, time.Second, func(key string) {
+			server.mutex.Lock()
+			defer server.mutex.Unlock()
+			if pair := server.data[key]; pair != nil {
+				server.invalidate(pair.Session)
+			}
+		})
+
+		lease, _ := cc.Lock("lock/consul", time.Second)
+		assert.Equal(t, ConsulSessionMinTTL, lease.TTL(), "有效期应当被提升至会话的最小有效期。")
+		pair, _ := cc.GetPair("lock/consul")
+		assert.Equal(t, pair.ModifyIndex, lease.Token(), "防护令牌应当为键的 ModifyIndex。")
+		assert.NotEmpty(t, pair.Session, "锁应当被会话持有。")
+		lease.Unlock()
+		_, err := cc.GetPair("lock/consul")
+		assert.ErrorIs(t, err, ErrNotFound, "释放后键应当被删除。")
+		server.mutex.Lock()
+		assert.NotContains(t, server.sessions, pair.Session, "释放后会话应当被销毁。")
+		server.mutex.Unlock()
+	})
+
+	t.Run("Alias", func(t *testing.T) {
+		Register("lock_test", NewMemory())
+		defer func() {
+			Of("lock_test").Close()
+			pairsMap.Delete("lock_test")
+		}()
+
+		lease, err := Lock("lock_test", "lock/alias", time.Minute)
+		assert.NoError(t, err, "Lock 不应当返回错误。")
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			lease.Unlock()
+		}()
+		next, err := WaitLock("lock_test", "lock/alias", time.Minute, 3*time.Second)
+		assert.NoError(t, err, "锁被释放后 WaitLock 不应当返回错误。")
+
+		start := time.Now()
+		_, err = WaitLock("lock_test", "lock/alias", time.Minute, 150*time.Millisecond)
+		assert.ErrorIs(t, err, ErrLocked, "等待超时后应当返回 ErrLocked。")
+		assert.True(t, time.Since(start) >= 150*time.Millisecond, "WaitLock 应当等待至超时。")
+		next.Unlock()
+
+		_, err = Lock("lock_missing", "lock/alias", time.Minute)
+		assert.ErrorIs(t, err, ErrNotRegistered, "未注册的别名应当返回 ErrNotRegistered。")
+	})
+
+	t.Run("Fence", func(t *testing.T) {
+		mc := NewMemory()
+		defer mc.Close()
+
+		mc.Set("lock/incr", "5", time.Minute)
+		value, err := mc.IncrBy("lock/incr", 2)
+		assert.NoError(t, err, "IncrBy 不应当返回错误。")
+		assert.Equal(t, int64(7), value, "递增后的值应当和预期相等。")
+		ttl, _ := mc.TTL("lock/incr")
+		assert.True(t, ttl > 0, "IncrBy 不应当改变键的过期时间。")
+		mc.Set("lock/incr", "a", 0)
+		_, err = mc.IncrBy("lock/incr", 1)
+		assert.Error(t, err, "非整数的值递增时应当返回错误。")
+
+		// 并发递增时防护令牌应当唯一且连续，CAS 实现（隐藏 IncrBy）同样适用
+		for name, pairs := range map[string]IPairs{"Incr": mc, "CAS": struct{ IPairs }{mc}} {
+			var mutex sync.Mutex
+			var wg sync.WaitGroup
+			tokens := make(map[uint64]bool)
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					token, err := lockFence(pairs, "lock/fence/"+name)
+					assert.NoError(t, err, "递增防护令牌不应当返回错误：%v。", name)
+					mutex.Lock()
+					tokens[token] = true
+					mutex.Unlock()
+				}()
+			}
+			wg.Wait()
+			assert.Equal(t, 20, len(tokens), "并发递增的防护令牌应当唯一：%v。", name)
+			for i := uint64(1); i <= 20; i++ {
+				assert.True(t, tokens[i], "并发递增的防护令牌应当连续：%v。", name)
+			}
+		}
+	})
+
+	t.Run("Renew", func(t *testing.T) {
+		block := make(chan struct{})
+		lease := newLease("lock/renew", 1, time.Minute, time.Now())
+		lease.renew = func() error {
+			<-block
+			return nil
+		}
+		lease.unlock = func() error { return nil }
+
+		done := make(chan error, 1)
+		go func() { done <- lease.Renew() }()
+		time.Sleep(20 * time.Millisecond)
+		valid := make(chan bool, 1)
+		go func() { valid <- lease.Valid() }()
+		select {
+		case ok := <-valid:
+			assert.True(t, ok, "续约期间租约应当仍然有效。")
+		case <-time.After(time.Second):
+			assert.Fail(t, "续约的网络请求不应当阻塞 Valid。")
+		}
+
+		assert.NoError(t, lease.Unlock(), "续约期间 Unlock 不应当被阻塞。")
+		close(block)
+		assert.ErrorIs(t, <-done, ErrLockLost, "续约期间租约被释放时 Renew 应当返回 ErrLockLost。")
+		assert.False(t, lease.Valid(), "释放后的租约应当无效。")
+	})
+
+	t.Run("KeepAlive", func(t *testing.T) {
+		mc := NewMemory()
+		defer mc.Close()
+
+		lease, _ := mc.Lock("lock/keep", 60*time.Millisecond)
+		lost := make(chan error, 1)
+		lease.KeepAlive(func(err error) { lost <- err })
+		lease.KeepAlive(nil)
+		time.Sleep(200 * time.Millisecond)
+		assert.True(t, lease.Valid(), "自动续约的租约应当保持有效。")
+		_, err := mc.Lock("lock/keep", time.Minute)
+		assert.ErrorIs(t, err, ErrLocked, "自动续约的锁不应当被其他持有者获取。")
+
+		mc.Delete("lock/keep")
+		select {
+		case err := <-lost:
+			assert.ErrorIs(t, err, ErrLockLost, "租约失效时应当回调 ErrLockLost。")
+		case <-time.After(time.Second):
+			assert.Fail(t, "租约失效时应当回调 lost。")
+		}
+
+		lease, _ = mc.Lock("lock/keep", 60*time.Millisecond)
+		lease.KeepAlive(func(err error) { lost <- err })
+		assert.NoError(t, lease.Unlock(), "Unlock 不应当返回错误。")
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, 0, len(lost), "主动释放时不应当回调 lost。")
+	})
+}
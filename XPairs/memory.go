@@ -0,0 +1,409 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemorySweep 是内存存储清理过期键的间隔，过期的键在访问时亦会被立即清理。
+const MemorySweep = time.Second
+
+// MemoryClient 是基于进程内存的键值对存储，实现了 IPairs 接口，适用于单元测试及单节点部署。
+type MemoryClient struct {
+	data        map[string]*memoryEntry
+	watchers    []*watcher
+	subscribers []*subscriber
+	pushed      chan struct{} // 列表被插入时关闭并替换，用于唤醒 BLPop
+	closed      bool
+	done        chan struct{}
+	mutex       sync.Mutex
+}
+
+// memoryEntry 是内存存储的键值，kind 为 memoryString 时使用 value，否则使用对应类型的数据结构。
+type memoryEntry struct {
+	kind   int
+	value  string
+	hash   map[string]string
+	zset   map[string]float64
+	list   []string
+	expire time.Time // 过期时间，零值表示不过期
+}
+
+// NewMemory 创建内存存储，并启动过期键的定时清理。
+func NewMemory() *MemoryClient {
+	mc := &MemoryClient{data: make(map[string]*memoryEntry), pushed: make(chan struct{}), done: make(chan struct{})}
+	go mc.sweep()
+	return mc
+}
+
+// RegisterMemory 创建并注册指定别名的内存存储。
+func RegisterMemory(alias string) {
+	Register(alias, NewMemory())
+}
+
+// MemoryOf 返回指定别名的内存存储，未注册或类型不匹配时返回 nil。
+func MemoryOf(alias string) *MemoryClient {
+	mc, _ := Of(alias).(*MemoryClient)
+	return mc
+}
+
+// Get 读取键的值，键不存在时返回 ErrNotFound。
+func (mc *MemoryClient) Get(key string) (string, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return "", ErrClosed
+	}
+	entry := mc.load(key)
+	if entry == nil {
+		return "", ErrNotFound
+	}
+	if entry.kind != memoryString {
+		return "", ErrWrongType
+	}
+	return entry.value, nil
+}
+
+// Set 设置键的值，ttl 为过期时间，小于等于 0 时不过期。
+func (mc *MemoryClient) Set(key, value string, ttl time.Duration) error {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return ErrClosed
+	}
+	mc.store(key, value, ttl)
+	return nil
+}
+
+// SetNX 仅在键不存在时设置键的值，返回是否设置成功。
+func (mc *MemoryClient) SetNX(key, value string, ttl time.Duration) (bool, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return false, ErrClosed
+	}
+	if mc.load(key) != nil {
+		return false, nil
+	}
+	mc.store(key, value, ttl)
+	return true, nil
+}
+
+// IncrBy 将键的整数值原子地增加 delta 并返回增加后的值，键不存在时视为 0，不改变键的过期时间。
+func (mc *MemoryClient) IncrBy(key string, delta int64) (int64, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return 0, ErrClosed
+	}
+	entry := mc.load(key)
+	if entry != nil && entry.kind != memoryString {
+		return 0, ErrWrongType
+	}
+	value := int64(0)
+	var expire time.Time
+	if entry != nil {
+		var err error
+		if value, err = strconv.ParseInt(entry.value, 10, 64); err != nil {
+			return 0, fmt.Errorf("value of %v is not an integer", key)
+		}
+		expire = entry.expire
+	}
+	value += delta
+	mc.store(key, strconv.FormatInt(value, 10), 0)
+	mc.data[key].expire = expire
+	return value, nil
+}
+
+// Delete 删除一个或多个键，返回被删除的键数量。
+func (mc *MemoryClient) Delete(keys ...string) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return 0, ErrClosed
+	}
+	count := 0
+	for _, key := range keys {
+		if entry := mc.load(key); entry != nil {
+			mc.remove(key, entry)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Exists 返回一个或多个键中存在的数量。
+func (mc *MemoryClient) Exists(keys ...string) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return 0, ErrClosed
+	}
+	count := 0
+	for _, key := range keys {
+		if mc.load(key) != nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Keys 列举指定前缀的键名，结果按字典序排列，前缀为空时列举所有的键。
+func (mc *MemoryClient) Keys(prefix string) ([]string, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return nil, ErrClosed
+	}
+	keys := make([]string, 0)
+	for key := range mc.data {
+		if strings.HasPrefix(key, prefix) && mc.load(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Expire 设置键的过期时间，ttl 小于等于 0 时移除过期时间，返回键是否存在。
+func (mc *MemoryClient) Expire(key string, ttl time.Duration) (bool, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return false, ErrClosed
+	}
+	entry := mc.load(key)
+	if entry == nil {
+		return false, nil
+	}
+	if ttl > 0 {
+		entry.expire = time.Now().Add(ttl)
+	} else {
+		entry.expire = time.Time{}
+	}
+	return true, nil
+}
+
+// TTL 返回键的剩余过期时间，未设置过期时间时返回 -1，键不存在时返回 ErrNotFound。
+func (mc *MemoryClient) TTL(key string) (time.Duration, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return 0, ErrClosed
+	}
+	entry := mc.load(key)
+	if entry == nil {
+		return 0, ErrNotFound
+	}
+	if entry.expire.IsZero() {
+		return -1, nil
+	}
+	return time.Until(entry.expire), nil
+}
+
+// Rename 将键重命名为 newkey 并保留其过期时间，newkey 已存在时将被覆盖，键不存在时返回 ErrNotFound。
+func (mc *MemoryClient) Rename(key, newkey string) error {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return ErrClosed
+	}
+	entry := mc.load(key)
+	if entry == nil {
+		return ErrNotFound
+	}
+	if key == newkey {
+		return nil
+	}
+	mc.remove(key, entry)
+	if old := mc.load(newkey); old != nil {
+		mc.remove(newkey, old)
+	}
+	mc.data[newkey] = entry
+	if entry.kind == memoryString {
+		mc.notify(Event{Key: newkey, New: entry.value})
+	}
+	return nil
+}
+
+// CompareAndSwap 仅在键存在且值等于 old 时将其设置为 value，返回是否设置成功。
+func (mc *MemoryClient) CompareAndSwap(key, old, value string, ttl time.Duration) (bool, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return false, ErrClosed
+	}
+	entry := mc.load(key)
+	if entry != nil && entry.kind != memoryString {
+		return false, ErrWrongType
+	}
+	if entry == nil || entry.value != old {
+		return false, nil
+	}
+	mc.store(key, value, ttl)
+	return true, nil
+}
+
+// CompareAndDelete 仅在键存在且值等于 old 时删除该键，返回是否删除成功。
+func (mc *MemoryClient) CompareAndDelete(key, old string) (bool, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return false, ErrClosed
+	}
+	entry := mc.load(key)
+	if entry != nil && entry.kind != memoryString {
+		return false, ErrWrongType
+	}
+	if entry == nil || entry.value != old {
+		return false, nil
+	}
+	mc.remove(key, entry)
+	return true, nil
+}
+
+// Watch 监听指定前缀的键的变更，包括写入、删除及过期，返回停止监听的函数。
+func (mc *MemoryClient) Watch(prefix string, handler func(event Event)) (func(), error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return nil, ErrClosed
+	}
+	w := newWatcher(prefix, handler)
+	mc.watchers = append(mc.watchers, w)
+	return func() {
+		w.stop()
+		mc.mutex.Lock()
+		defer mc.mutex.Unlock()
+		for i, tw := range mc.watchers {
+			if tw == w {
+				mc.watchers = append(mc.watchers[:i], mc.watchers[i+1:]...)
+				break
+			}
+		}
+	}, nil
+}
+
+// Publish 向频道发布消息，返回匹配该频道的订阅者数量。
+func (mc *MemoryClient) Publish(channel, message string) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return 0, ErrClosed
+	}
+	count := 0
+	for _, s := range mc.subscribers {
+		if s.push(channel, message) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Subscribe 订阅匹配模式的频道，返回接收消息的通道及取消订阅的函数。
+func (mc *MemoryClient) Subscribe(pattern string) (<-chan Message, func(), error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return nil, nil, ErrClosed
+	}
+	s := newSubscriber(pattern)
+	mc.subscribers = append(mc.subscribers, s)
+	return s.out, func() {
+		s.stop()
+		mc.mutex.Lock()
+		defer mc.mutex.Unlock()
+		for i, ts := range mc.subscribers {
+			if ts == s {
+				mc.subscribers = append(mc.subscribers[:i], mc.subscribers[i+1:]...)
+				break
+			}
+		}
+	}, nil
+}
+
+// Close 关闭存储，停止定时清理、所有的监听及订阅，并释放存储的数据。
+func (mc *MemoryClient) Close() error {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.closed {
+		return nil
+	}
+	mc.closed = true
+	close(mc.done)
+	for _, w := range mc.watchers {
+		w.stop()
+	}
+	mc.watchers = nil
+	for _, s := range mc.subscribers {
+		s.stop()
+	}
+	mc.subscribers = nil
+	mc.data = make(map[string]*memoryEntry)
+	return nil
+}
+
+// load 读取未过期的键值，过期的键将被清理，调用者需持有锁。
+func (mc *MemoryClient) load(key string) *memoryEntry {
+	entry := mc.data[key]
+	if entry != nil && !entry.expire.IsZero() && !time.Now().Before(entry.expire) {
+		mc.remove(key, entry)
+		return nil
+	}
+	return entry
+}
+
+// store 写入字符串类型的键值并通知监听者，调用者需持有锁。
+func (mc *MemoryClient) store(key, value string, ttl time.Duration) {
+	event := Event{Key: key, New: value}
+	if entry := mc.load(key); entry != nil && entry.kind == memoryString {
+		event.Old = entry.value
+	}
+	entry := &memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expire = time.Now().Add(ttl)
+	}
+	mc.data[key] = entry
+	mc.notify(event)
+}
+
+// remove 删除键值并通知监听者，调用者需持有锁；与 Redis 的 Watch 一致，仅通知字符串类型的键。
+func (mc *MemoryClient) remove(key string, entry *memoryEntry) {
+	delete(mc.data, key)
+	if entry.kind == memoryString {
+		mc.notify(Event{Key: key, Old: entry.value, Deleted: true})
+	}
+}
+
+// notify 将变更事件分发至匹配的监听者，调用者需持有锁。
+func (mc *MemoryClient) notify(event Event) {
+	for _, w := range mc.watchers {
+		w.push(event)
+	}
+}
+
+// sweep 定时清理过期的键，直至存储被关闭。
+func (mc *MemoryClient) sweep() {
+	ticker := time.NewTicker(MemorySweep)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-mc.done:
+			return
+		case <-ticker.C:
+			mc.mutex.Lock()
+			for key := range mc.data {
+				mc.load(key)
+			}
+			mc.mutex.Unlock()
+		}
+	}
+}
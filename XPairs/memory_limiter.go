@@ -0,0 +1,113 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// TakeToken 从键对应的令牌桶中原子地取出 n 个令牌，桶的容量为 burst，每秒补充 rate 个令牌，令牌不足时不取出。
+// 令牌桶的状态与 Redis 一致，存储于哈希表的 tokens 及 time 字段中。
+func (mc *MemoryClient) TakeToken(key string, rate float64, burst, n int, now time.Time) (LimitResult, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryHash, true)
+	if err != nil {
+		return LimitResult{}, err
+	}
+	ms := now.UnixMilli()
+	tokens, terr := strconv.ParseFloat(entry.hash["tokens"], 64)
+	last, lerr := strconv.ParseInt(entry.hash["time"], 10, 64)
+	if terr != nil || lerr != nil {
+		tokens, last = float64(burst), ms
+	}
+	if ms > last {
+		tokens = min(float64(burst), tokens+float64(ms-last)*rate/1000)
+		last = ms
+	}
+	result := LimitResult{}
+	if tokens >= float64(n) {
+		tokens -= float64(n)
+		result.Allowed = true
+	} else if n > burst {
+		result.RetryAfter = -1
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((float64(n)-tokens)*1000/rate)) * time.Millisecond
+	}
+	result.Remaining = int(math.Floor(tokens))
+	entry.hash["tokens"] = strconv.FormatFloat(tokens, 'f', -1, 64)
+	entry.hash["time"] = strconv.FormatInt(last, 10)
+	entry.expire = time.Now().Add(time.Duration(max(math.Ceil((float64(burst)-tokens)*1000/rate), 1)) * time.Millisecond)
+	return result, nil
+}
+
+// TakeWindow 在键对应的滑动窗口中原子地记录 n 次请求，窗口内最多允许 limit 次请求，超出时不记录。
+// 窗口内的请求与 Redis 一致，以毫秒时间戳为分数存储于有序集合中。
+func (mc *MemoryClient) TakeWindow(key string, limit int, window time.Duration, n int, now time.Time) (LimitResult, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, false)
+	if err != nil {
+		return LimitResult{}, err
+	}
+	ms, span := now.UnixMilli(), window.Milliseconds()
+	if entry != nil {
+		for member, score := range entry.zset {
+			if score <= float64(ms-span) {
+				delete(entry.zset, member)
+			}
+		}
+		if len(entry.zset) == 0 {
+			mc.remove(key, entry)
+			entry = nil
+		}
+	}
+	count := 0
+	if entry != nil {
+		count = len(entry.zset)
+	}
+	if count+n <= limit {
+		if entry == nil {
+			entry, _ = mc.typed(key, memoryZSet, true)
+		}
+		nonce := lockNonce()
+		for i := 1; i <= n; i++ {
+			entry.zset[nonce+":"+strconv.Itoa(i)] = float64(ms)
+		}
+		entry.expire = time.Now().Add(window)
+		return LimitResult{Allowed: true, Remaining: limit - count - n}, nil
+	}
+	if n > limit {
+		return LimitResult{Remaining: max(limit-count, 0), RetryAfter: -1}, nil
+	}
+	oldest := sortZMembers(entry.zset)[count+n-limit-1]
+	return LimitResult{Remaining: max(limit-count, 0), RetryAfter: time.Duration(int64(oldest.Score)+span-ms) * time.Millisecond}, nil
+}
+
+// IncrExpire 将键的整数值原子地增加 delta 并返回增加后的值，键不存在时视为 0，键未设置过期时间时将其设置为 ttl。
+func (mc *MemoryClient) IncrExpire(key string, delta int64, ttl time.Duration) (int64, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryString, false)
+	if err != nil {
+		return 0, err
+	}
+	value := int64(0)
+	expire := time.Now().Add(max(ttl, time.Millisecond))
+	if entry != nil {
+		if value, err = strconv.ParseInt(entry.value, 10, 64); err != nil {
+			return 0, err
+		}
+		if !entry.expire.IsZero() {
+			expire = entry.expire
+		}
+	}
+	value += delta
+	mc.store(key, strconv.FormatInt(value, 10), 0)
+	mc.data[key].expire = expire
+	return value, nil
+}
@@ -0,0 +1,472 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// 内存存储的键值类型。
+const (
+	memoryString = iota // 字符串
+	memoryHash          // 哈希表
+	memoryZSet          // 有序集合
+	memoryList          // 列表
+)
+
+// HGet 读取哈希表字段的值，键或字段不存在时返回 ErrNotFound。
+func (mc *MemoryClient) HGet(key, field string) (string, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryHash, false)
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return "", ErrNotFound
+	}
+	value, ok := entry.hash[field]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// HSet 设置哈希表一个或多个字段的值，返回新增的字段数量。
+func (mc *MemoryClient) HSet(key string, fields map[string]string) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryHash, len(fields) > 0)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	count := 0
+	for field, value := range fields {
+		if _, ok := entry.hash[field]; !ok {
+			count++
+		}
+		entry.hash[field] = value
+	}
+	return count, nil
+}
+
+// HIncrBy 将哈希表字段的整数值增加 delta，字段不存在时视为 0，返回增加后的值。
+func (mc *MemoryClient) HIncrBy(key, field string, delta int64) (int64, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryHash, true)
+	if err != nil {
+		return 0, err
+	}
+	var value int64
+	if str, ok := entry.hash[field]; ok {
+		if value, err = strconv.ParseInt(str, 10, 64); err != nil {
+			return 0, fmt.Errorf("hash value of %v is not an integer", field)
+		}
+	}
+	value += delta
+	entry.hash[field] = strconv.FormatInt(value, 10)
+	return value, nil
+}
+
+// HGetAll 读取哈希表的所有字段及其值，键不存在时返回空的结果。
+func (mc *MemoryClient) HGetAll(key string) (map[string]string, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryHash, false)
+	if err != nil {
+		return nil, err
+	}
+	rets := make(map[string]string)
+	if entry != nil {
+		for field, value := range entry.hash {
+			rets[field] = value
+		}
+	}
+	return rets, nil
+}
+
+// HDel 删除哈希表的一个或多个字段，返回被删除的字段数量。
+func (mc *MemoryClient) HDel(key string, fields ...string) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryHash, false)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	count := 0
+	for _, field := range fields {
+		if _, ok := entry.hash[field]; ok {
+			delete(entry.hash, field)
+			count++
+		}
+	}
+	if len(entry.hash) == 0 {
+		mc.remove(key, entry)
+	}
+	return count, nil
+}
+
+// ZAdd 添加一个或多个成员或更新已存在成员的分数，返回新增的成员数量。
+func (mc *MemoryClient) ZAdd(key string, members map[string]float64) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, len(members) > 0)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	count := 0
+	for member, score := range members {
+		if _, ok := entry.zset[member]; !ok {
+			count++
+		}
+		entry.zset[member] = score
+	}
+	return count, nil
+}
+
+// ZIncrBy 将成员的分数增加 delta，成员不存在时视为 0，返回增加后的分数。
+func (mc *MemoryClient) ZIncrBy(key, member string, delta float64) (float64, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, true)
+	if err != nil {
+		return 0, err
+	}
+	entry.zset[member] += delta
+	return entry.zset[member], nil
+}
+
+// ZIncrByInit 原子地将成员的分数增加 delta，成员不存在时以 init 为初始分数，返回增加后的分数。
+func (mc *MemoryClient) ZIncrByInit(key, member string, init, delta float64) (float64, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, true)
+	if err != nil {
+		return 0, err
+	}
+	score, ok := entry.zset[member]
+	if !ok {
+		score = init
+	}
+	entry.zset[member] = score + delta
+	return entry.zset[member], nil
+}
+
+// ZScore 读取成员的分数，键或成员不存在时返回 ErrNotFound。
+func (mc *MemoryClient) ZScore(key, member string) (float64, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, false)
+	if err != nil {
+		return 0, err
+	}
+	if entry == nil {
+		return 0, ErrNotFound
+	}
+	score, ok := entry.zset[member]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return score, nil
+}
+
+// ZRange 按分数升序返回索引在 [start, stop] 之间的成员及其分数。
+func (mc *MemoryClient) ZRange(key string, start, stop int) ([]ZMember, error) {
+	return mc.zrange(key, start, stop, false)
+}
+
+// ZRevRange 按分数降序返回索引在 [start, stop] 之间的成员及其分数。
+func (mc *MemoryClient) ZRevRange(key string, start, stop int) ([]ZMember, error) {
+	return mc.zrange(key, start, stop, true)
+}
+
+// ZRangeByScore 按分数升序返回分数在 [min, max] 之间的成员，count 小于 0 时返回所有。
+func (mc *MemoryClient) ZRangeByScore(key string, min, max float64, offset, count int) ([]ZMember, error) {
+	return mc.zrangeByScore(key, min, max, offset, count, false)
+}
+
+// ZRevRangeByScore 按分数降序返回分数在 [min, max] 之间的成员，count 小于 0 时返回所有。
+func (mc *MemoryClient) ZRevRangeByScore(key string, min, max float64, offset, count int) ([]ZMember, error) {
+	return mc.zrangeByScore(key, min, max, offset, count, true)
+}
+
+// ZRank 返回成员按分数升序的排名，键或成员不存在时返回 ErrNotFound。
+func (mc *MemoryClient) ZRank(key, member string) (int, error) {
+	return mc.zrank(key, member, false)
+}
+
+// ZRevRank 返回成员按分数降序的排名，键或成员不存在时返回 ErrNotFound。
+func (mc *MemoryClient) ZRevRank(key, member string) (int, error) {
+	return mc.zrank(key, member, true)
+}
+
+// ZRem 删除一个或多个成员，返回被删除的成员数量。
+func (mc *MemoryClient) ZRem(key string, members ...string) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, false)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	count := 0
+	for _, member := range members {
+		if _, ok := entry.zset[member]; ok {
+			delete(entry.zset, member)
+			count++
+		}
+	}
+	if len(entry.zset) == 0 {
+		mc.remove(key, entry)
+	}
+	return count, nil
+}
+
+// ZCard 返回有序集合的成员数量，键不存在时返回 0。
+func (mc *MemoryClient) ZCard(key string) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, false)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	return len(entry.zset), nil
+}
+
+// LPush 将一个或多个值依次插入列表的头部，返回插入后列表的长度。
+func (mc *MemoryClient) LPush(key string, values ...string) (int, error) {
+	return mc.push(key, values, true)
+}
+
+// RPush 将一个或多个值依次插入列表的尾部，返回插入后列表的长度。
+func (mc *MemoryClient) RPush(key string, values ...string) (int, error) {
+	return mc.push(key, values, false)
+}
+
+// LPop 弹出列表头部的值，键不存在时返回 ErrNotFound。
+func (mc *MemoryClient) LPop(key string) (string, error) {
+	return mc.popOne(key, true)
+}
+
+// RPop 弹出列表尾部的值，键不存在时返回 ErrNotFound。
+func (mc *MemoryClient) RPop(key string) (string, error) {
+	return mc.popOne(key, false)
+}
+
+// LRange 返回列表中索引在 [start, stop] 之间的值。
+func (mc *MemoryClient) LRange(key string, start, stop int) ([]string, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryList, false)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return []string{}, nil
+	}
+	from, to := rangeIndex(start, stop, len(entry.list))
+	return slices.Clone(entry.list[from:to]), nil
+}
+
+// LLen 返回列表的长度，键不存在时返回 0。
+func (mc *MemoryClient) LLen(key string) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryList, false)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	return len(entry.list), nil
+}
+
+// BLPop 按顺序检查多个列表并弹出第一个非空列表头部的值，所有列表均为空时阻塞等待，超时时返回 ErrNotFound。
+func (mc *MemoryClient) BLPop(timeout time.Duration, keys ...string) (string, string, error) {
+	if timeout <= 0 {
+		return "", "", fmt.Errorf("invalid timeout %v of blpop", timeout)
+	}
+	if len(keys) == 0 {
+		return "", "", fmt.Errorf("empty keys of blpop")
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		mc.mutex.Lock()
+		for _, key := range keys {
+			entry, err := mc.typed(key, memoryList, false)
+			if err != nil {
+				mc.mutex.Unlock()
+				return "", "", err
+			}
+			if entry != nil {
+				value := mc.pop(key, entry, true)
+				mc.mutex.Unlock()
+				return key, value, nil
+			}
+		}
+		pushed := mc.pushed
+		mc.mutex.Unlock()
+
+		select {
+		case <-pushed:
+		case <-timer.C:
+			return "", "", ErrNotFound
+		case <-mc.done:
+			return "", "", ErrClosed
+		}
+	}
+}
+
+// typed 读取指定类型的键值，调用者需持有锁。
+// 类型不匹配时返回 ErrWrongType，键不存在时若 create 为 true 则创建空的数据结构，否则返回 nil。
+func (mc *MemoryClient) typed(key string, kind int, create bool) (*memoryEntry, error) {
+	if mc.closed {
+		return nil, ErrClosed
+	}
+	entry := mc.load(key)
+	if entry != nil {
+		if entry.kind != kind {
+			return nil, ErrWrongType
+		}
+		return entry, nil
+	}
+	if !create {
+		return nil, nil
+	}
+	entry = &memoryEntry{kind: kind}
+	switch kind {
+	case memoryHash:
+		entry.hash = make(map[string]string)
+	case memoryZSet:
+		entry.zset = make(map[string]float64)
+	}
+	mc.data[key] = entry
+	return entry, nil
+}
+
+// zrange 返回有序集合中索引在 [start, stop] 之间的成员，reverse 表示按分数降序排列。
+func (mc *MemoryClient) zrange(key string, start, stop int, reverse bool) ([]ZMember, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, false)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return []ZMember{}, nil
+	}
+	members := sortZMembers(entry.zset)
+	if reverse {
+		slices.Reverse(members)
+	}
+	from, to := rangeIndex(start, stop, len(members))
+	return members[from:to], nil
+}
+
+// zrangeByScore 返回有序集合中分数在 [min, max] 之间的成员，reverse 表示按分数降序排列。
+func (mc *MemoryClient) zrangeByScore(key string, min, max float64, offset, count int, reverse bool) ([]ZMember, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, false)
+	if err != nil {
+		return nil, err
+	}
+	rets := []ZMember{}
+	if entry == nil || offset < 0 {
+		return rets, nil
+	}
+	members := sortZMembers(entry.zset)
+	if reverse {
+		slices.Reverse(members)
+	}
+	for _, member := range members {
+		if member.Score < min || member.Score > max {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if count >= 0 && len(rets) >= count {
+			break
+		}
+		rets = append(rets, member)
+	}
+	return rets, nil
+}
+
+// zrank 返回成员在有序集合中的排名，reverse 表示按分数降序排列。
+func (mc *MemoryClient) zrank(key, member string, reverse bool) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryZSet, false)
+	if err != nil {
+		return 0, err
+	}
+	if entry == nil {
+		return 0, ErrNotFound
+	}
+	if _, ok := entry.zset[member]; !ok {
+		return 0, ErrNotFound
+	}
+	members := sortZMembers(entry.zset)
+	rank := slices.IndexFunc(members, func(m ZMember) bool { return m.Member == member })
+	if reverse {
+		rank = len(members) - 1 - rank
+	}
+	return rank, nil
+}
+
+// push 将值插入列表的头部或尾部，并唤醒等待中的 BLPop。
+func (mc *MemoryClient) push(key string, values []string, left bool) (int, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryList, len(values) > 0)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	if left {
+		head := slices.Clone(values)
+		slices.Reverse(head)
+		entry.list = append(head, entry.list...)
+	} else {
+		entry.list = append(entry.list, values...)
+	}
+	close(mc.pushed)
+	mc.pushed = make(chan struct{})
+	return len(entry.list), nil
+}
+
+// popOne 弹出列表头部或尾部的值，键不存在时返回 ErrNotFound。
+func (mc *MemoryClient) popOne(key string, left bool) (string, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	entry, err := mc.typed(key, memoryList, false)
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return "", ErrNotFound
+	}
+	return mc.pop(key, entry, left), nil
+}
+
+// pop 弹出非空列表头部或尾部的值，列表为空后删除该键，调用者需持有锁。
+func (mc *MemoryClient) pop(key string, entry *memoryEntry, left bool) string {
+	var value string
+	if left {
+		value = entry.list[0]
+		entry.list = entry.list[1:]
+	} else {
+		value = entry.list[len(entry.list)-1]
+		entry.list = entry.list[:len(entry.list)-1]
+	}
+	if len(entry.list) == 0 {
+		mc.remove(key, entry)
+	}
+	return value
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryKV(t *testing.T) {
+	t.Run("Expire", func(t *testing.T) {
+		mc := NewMemory()
+		defer mc.Close()
+
+		assert.NoError(t, mc.Set("ttl", "value", 50*time.Millisecond), "带过期时间的 Set 不应当返回错误。")
+		ok, _ := mc.SetNX("ttl", "value", 0)
+		assert.False(t, ok, "未过期的键 SetNX 应当失败。")
+		time.Sleep(80 * time.Millisecond)
+		_, err := mc.Get("ttl")
+		assert.ErrorIs(t, err, ErrNotFound, "过期的键应当返回 ErrNotFound。")
+		ok, _ = mc.SetNX("ttl", "value", 0)
+		assert.True(t, ok, "过期的键 SetNX 应当成功。")
+
+		ok, _ = mc.Expire("ttl", 50*time.Millisecond)
+		assert.True(t, ok, "存在的键应当设置成功。")
+		time.Sleep(80 * time.Millisecond)
+		keys, _ := mc.Keys("")
+		assert.Empty(t, keys, "过期的键不应当被列举。")
+		ok, _ = mc.Expire("ttl", time.Minute)
+		assert.False(t, ok, "不存在的键应当设置失败。")
+	})
+
+	t.Run("Rename", func(t *testing.T) {
+		mc := NewMemory()
+		defer mc.Close()
+
+		mc.Set("old", "v1", time.Minute)
+		mc.Set("new", "v0", 0)
+		mc.ZAdd("zset", map[string]float64{"m": 1})
+		assert.NoError(t, mc.Rename("old", "new"), "Rename 不应当返回错误。")
+		value, _ := mc.Get("new")
+		assert.Equal(t, "v1", value, "重命名应当覆盖已存在的键。")
+		ttl, _ := mc.TTL("new")
+		assert.True(t, ttl > 0, "重命名应当保留过期时间。")
+		_, err := mc.Get("old")
+		assert.ErrorIs(t, err, ErrNotFound, "重命名后原键不应当存在。")
+		assert.NoError(t, mc.Rename("zset", "zset:archive"), "重命名有序集合不应当返回错误。")
+		score, _ := mc.ZScore("zset:archive", "m")
+		assert.Equal(t, 1.0, score, "重命名后有序集合的成员应当被保留。")
+		assert.ErrorIs(t, mc.Rename("missing", "new"), ErrNotFound, "重命名不存在的键应当返回 ErrNotFound。")
+		assert.NoError(t, mc.Rename("new", "new"), "重命名为自身不应当返回错误。")
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		mc := NewMemory()
+		defer mc.Close()
+
+		events := make(chan Event, 16)
+		stop, err := mc.Watch("w/", func(event Event) { events <- event })
+		assert.NoError(t, err, "Watch 不应当返回错误。")
+
+		mc.Set("w/a", "1", 0)
+		mc.Set("w/a", "2", 30*time.Millisecond)
+		assert.Equal(t, Event{Key: "w/a", New: "1"}, <-events, "新建的事件应当和预期相等。")
+		assert.Equal(t, Event{Key: "w/a", Old: "1", New: "2"}, <-events, "修改的事件应当和预期相等。")
+		select {
+		case event := <-events:
+			assert.Equal(t, Event{Key: "w/a", Old: "2", Deleted: true}, event, "过期的事件应当和预期相等。")
+		case <-time.After(3 * MemorySweep):
+			assert.Fail(t, "过期的键应当被定时清理并回调事件。")
+		}
+
+		stop()
+		mc.Set("w/b", "1", 0)
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, 0, len(events), "停止监听后不应当回调事件。")
+		assert.Empty(t, mc.watchers, "停止监听后应当移除监听者。")
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		mc := NewMemory()
+		mc.Set("key", "value", 0)
+		stop, _ := mc.Watch("", func(event Event) {})
+		assert.NoError(t, mc.Close(), "Close 不应当返回错误。")
+		assert.NoError(t, mc.Close(), "重复的 Close 不应当返回错误。")
+		stop()
+
+		_, err := mc.Get("key")
+		assert.ErrorIs(t, err, ErrClosed, "关闭后的 Get 应当返回 ErrClosed。")
+		assert.ErrorIs(t, mc.Set("key", "value", 0), ErrClosed, "关闭后的 Set 应当返回 ErrClosed。")
+		_, err = mc.Keys("")
+		assert.ErrorIs(t, err, ErrClosed, "关闭后的 Keys 应当返回 ErrClosed。")
+		_, err = mc.Watch("", func(event Event) {})
+		assert.ErrorIs(t, err, ErrClosed, "关闭后的 Watch 应当返回 ErrClosed。")
+	})
+}
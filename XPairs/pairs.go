@@ -0,0 +1,190 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IPairs 是键值对存储的通用接口，由 Redis、Consul 及内存等存储实现。
+// 所有的实现都是线程安全的，键不存在时返回 ErrNotFound，存储不支持的操作返回 ErrNotSupported。
+type IPairs interface {
+	// Get 读取键的值，键不存在时返回 ErrNotFound。
+	Get(key string) (string, error)
+
+	// Set 设置键的值，ttl 为过期时间，小于等于 0 时不过期。
+	Set(key, value string, ttl time.Duration) error
+
+	// SetNX 仅在键不存在时设置键的值，返回是否设置成功。
+	SetNX(key, value string, ttl time.Duration) (bool, error)
+
+	// Delete 删除一个或多个键，返回被删除的键数量。
+	Delete(keys ...string) (int, error)
+
+	// Exists 返回一个或多个键中存在的数量。
+	Exists(keys ...string) (int, error)
+
+	// Keys 列举指定前缀的键名，结果按字典序排列，前缀为空时列举所有的键。
+	Keys(prefix string) ([]string, error)
+
+	// Expire 设置键的过期时间，ttl 小于等于 0 时移除过期时间，返回键是否存在。
+	Expire(key string, ttl time.Duration) (bool, error)
+
+	// TTL 返回键的剩余过期时间，未设置过期时间时返回 -1，键不存在时返回 ErrNotFound。
+	TTL(key string) (time.Duration, error)
+
+	// CompareAndSwap 仅在键存在且值等于 old 时将其设置为 value，ttl 的规则与 Set 相同，返回是否设置成功。
+	CompareAndSwap(key, old, value string, ttl time.Duration) (bool, error)
+
+	// CompareAndDelete 仅在键存在且值等于 old 时删除该键，返回是否删除成功。
+	CompareAndDelete(key, old string) (bool, error)
+
+	// Watch 监听指定前缀的键的变更，变更事件将按照发生的顺序在独立的 goroutine 中回调 handler。
+	// 返回停止监听的函数，停止后不再回调 handler。
+	Watch(prefix string, handler func(event Event)) (func(), error)
+
+	// Close 关闭存储的连接及监听。
+	Close() error
+}
+
+// Event 是键的变更事件。
+type Event struct {
+	Key     string // 键名
+	Old     string // 变更前的值，新建时为空
+	New     string // 变更后的值，删除时为空
+	Deleted bool   // 是否被删除（包括过期）
+}
+
+// pairsMap 存储已注册的键值对存储，键为别名。
+var pairsMap sync.Map
+
+// Register 注册指定别名的键值对存储，若别名已被注册，则关闭并替换原有的存储。
+// 别名的作用与数据模型的 AliasName 相同，业务代码通过 Of 获取对应的存储，存储的请求指标以别名为标签统计。
+func Register(alias string, pairs IPairs) {
+	if binder, ok := pairs.(interface{ bind(alias string) }); ok {
+		binder.bind(alias)
+	}
+	if old, loaded := pairsMap.Swap(alias, pairs); loaded && old != pairs {
+		old.(IPairs).Close()
+	}
+}
+
+// Of 返回指定别名的键值对存储，未注册时返回 nil。
+func Of(alias string) IPairs {
+	if val, ok := pairsMap.Load(alias); ok {
+		return val.(IPairs)
+	}
+	return nil
+}
+
+// Aliases 返回所有已注册的别名，结果按字典序排列。
+func Aliases() []string {
+	aliases := make([]string, 0)
+	pairsMap.Range(func(key, value any) bool {
+		aliases = append(aliases, key.(string))
+		return true
+	})
+	sort.Strings(aliases)
+	return aliases
+}
+
+// watcher 是变更事件的分发器，使用无界队列按序回调处理函数，避免阻塞存储的写入。
+type watcher struct {
+	prefix  string
+	handler func(event Event)
+	events  []Event
+	signal  chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	mutex   sync.Mutex
+}
+
+// newWatcher 创建并启动变更事件的分发器。
+func newWatcher(prefix string, handler func(event Event)) *watcher {
+	w := &watcher{prefix: prefix, handler: handler, signal: make(chan struct{}, 1), done: make(chan struct{})}
+	go w.run()
+	return w
+}
+
+// match 判断键是否匹配监听的前缀。
+func (w *watcher) match(key string) bool { return strings.HasPrefix(key, w.prefix) }
+
+// push 将变更事件加入队列，不匹配前缀的事件将被忽略。
+func (w *watcher) push(events ...Event) {
+	w.mutex.Lock()
+	for _, event := range events {
+		if w.match(event.Key) {
+			w.events = append(w.events, event)
+		}
+	}
+	w.mutex.Unlock()
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+// stop 停止分发，可以重复调用。
+func (w *watcher) stop() {
+	w.once.Do(func() { close(w.done) })
+}
+
+// stopped 判断分发器是否已停止。
+func (w *watcher) stopped() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// run 按序分发队列中的事件。
+func (w *watcher) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.signal:
+		}
+		for {
+			w.mutex.Lock()
+			events := w.events
+			w.events = nil
+			w.mutex.Unlock()
+			if len(events) == 0 {
+				break
+			}
+			for _, event := range events {
+				if w.stopped() {
+					return
+				}
+				w.handler(event)
+			}
+		}
+	}
+}
+
+// diffPairs 对比前后两次的键值快照，返回按键名排序的变更事件。
+func diffPairs(old, new map[string]string) []Event {
+	events := make([]Event, 0)
+	for key, value := range new {
+		if ovalue, ok := old[key]; !ok {
+			events = append(events, Event{Key: key, New: value})
+		} else if ovalue != value {
+			events = append(events, Event{Key: key, Old: ovalue, New: value})
+		}
+	}
+	for key, value := range old {
+		if _, ok := new[key]; !ok {
+			events = append(events, Event{Key: key, Old: value, Deleted: true})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Key < events[j].Key })
+	return events
+}
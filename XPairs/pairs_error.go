@@ -0,0 +1,40 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import "errors"
+
+// 以下错误由键值对的操作函数返回，可以使用 errors.Is 判断，网络等其他错误将被原样或包装后返回。
+var (
+	// ErrNotFound 表示键不存在。
+	ErrNotFound = errors.New("key was not found")
+
+	// ErrClosed 表示客户端已被关闭。
+	ErrClosed = errors.New("client was closed")
+
+	// ErrNotSupported 表示存储不支持该操作，如 Consul 的过期时间或未注册的编解码器。
+	ErrNotSupported = errors.New("operation was not supported")
+
+	// ErrNotRegistered 表示存储的别名未被注册。
+	ErrNotRegistered = errors.New("alias was not registered")
+
+	// ErrLocked 表示锁已被其他持有者获取。
+	ErrLocked = errors.New("lock was held by others")
+
+	// ErrLockLost 表示租约已过期、被释放或被其他持有者获取。
+	ErrLockLost = errors.New("lock was lost")
+
+	// ErrWrongType 表示键的类型与操作不匹配，如对列表执行哈希表的操作。
+	ErrWrongType = errors.New("key holds the wrong kind of value")
+
+	// ErrConflict 表示事务监视的键被并发修改，且重试次数已达上限。
+	ErrConflict = errors.New("transaction was aborted by concurrent modification")
+
+	// ErrUnavailable 表示无法连接服务端，在重连成功之前请求将快速失败并返回该错误。
+	ErrUnavailable = errors.New("backend was unavailable")
+
+	// ErrCrossSlot 表示 Redis 集群中的多键操作、管道或事务的键不在同一槽位，可以使用哈希标签使键位于同一槽位。
+	ErrCrossSlot = errors.New("keys were not in the same slot")
+)
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"strings"
+
+	"github.com/eframework-org/GO.UTIL/XEnv"
+	"github.com/eframework-org/GO.UTIL/XLog"
+	"github.com/eframework-org/GO.UTIL/XPrefs"
+	"github.com/eframework-org/GO.UTIL/XString"
+)
+
+const (
+	prefsPairsAddr  = "Addr"
+	prefsPairsPool  = "Pool"
+	prefsPairsConn  = "Conn"
+	prefsPairsCodec = "Codec"
+)
+
+func init() {
+	initPairs(XPrefs.Asset())
+}
+
+func initPairs(prefs XPrefs.IBase) {
+	if prefs == nil {
+		XLog.Panic("XPairs.Init: prefs is nil.")
+		return
+	}
+
+	for _, key := range prefs.Keys() {
+		if !strings.HasPrefix(key, "Pairs/Source") {
+			continue
+		}
+		parts := strings.Split(key, "/")
+		if len(parts) < 4 {
+			XLog.Panic("XPairs.Init: invalid prefs key %v.", key)
+			return
+		}
+
+		pairsType := strings.ToLower(parts[2])
+		pairsAlias := parts[3]
+
+		if base, ok := prefs.Get(key).(XPrefs.IBase); ok && base != nil {
+			pairsAddr := XString.Eval(base.GetString(prefsPairsAddr), XEnv.Vars())
+			pairsPool := base.GetInt(prefsPairsPool)
+			pairsConn := base.GetInt(prefsPairsConn)
+			switch pairsType {
+			case "redis":
+				if err := RegisterRedis(pairsAlias, pairsAddr, pairsPool, pairsConn); err != nil {
+					XLog.Panic("XPairs.Init: register redis %v failed, err: %v", pairsAlias, err)
+					return
+				}
+			case "consul":
+				if err := RegisterConsul(pairsAlias, pairsAddr, pairsPool, pairsConn); err != nil {
+					XLog.Panic("XPairs.Init: register consul %v failed, err: %v", pairsAlias, err)
+					return
+				}
+			case "memory":
+				RegisterMemory(pairsAlias)
+			default:
+				XLog.Error("XPairs.Init: unsupported type of %v", key)
+				continue
+			}
+			if pairsCodec := base.GetString(prefsPairsCodec); pairsCodec != "" {
+				if err := UseCodec(pairsAlias, pairsCodec); err != nil {
+					XLog.Panic("XPairs.Init: use codec %v for %v failed, err: %v", pairsCodec, pairsAlias, err)
+					return
+				}
+			}
+		} else {
+			XLog.Error("XPairs.Init: invalid config for %v", key)
+			continue
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"testing"
+
+	"github.com/eframework-org/GO.UTIL/XPrefs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairsInit(t *testing.T) {
+	server := newTestRedisServer(t)
+	cserver := newTestConsulServer(t)
+	t.Setenv("XPAIRS_TEST_ADDR", server.Addr())
+
+	tests := []struct {
+		name   string
+		prefs  XPrefs.IBase
+		alias  []string
+		consul []string
+		memory []string
+		panic  bool
+	}{
+		{
+			name: "Single",
+			prefs: XPrefs.New().Set("Pairs/Source/Redis/myredis", XPrefs.New().
+				Set(prefsPairsAddr, server.Addr()).
+				Set(prefsPairsPool, 10).
+				Set(prefsPairsConn, 100)),
+			alias: []string{"myredis"},
+		},
+		{
+			name: "Multiple",
+			prefs: XPrefs.New().
+				Set("Pairs/Source/Redis/myredis1", XPrefs.New().
+					Set(prefsPairsAddr, "redis://"+server.Addr()+"/1").
+					Set(prefsPairsPool, 10).
+					Set(prefsPairsConn, 100)).
+				Set("Pairs/Source/Redis/myredis2", XPrefs.New().
+					Set(prefsPairsAddr, "${Env.XPAIRS_TEST_ADDR}").
+					Set(prefsPairsPool, 20).
+					Set(prefsPairsConn, 200)),
+			alias: []string{"myredis1", "myredis2"},
+		},
+		{
+			name: "Consul",
+			prefs: XPrefs.New().Set("Pairs/Source/Consul/myconsul", XPrefs.New().
+				Set(prefsPairsAddr, cserver.URL).
+				Set(prefsPairsPool, 10).
+				Set(prefsPairsConn, 100)),
+			consul: []string{"myconsul"},
+		},
+		{
+			name:   "Memory",
+			prefs:  XPrefs.New().Set("Pairs/Source/Memory/mymemory", XPrefs.New()),
+			memory: []string{"mymemory"},
+		},
+		{
+			name:   "Codec",
+			prefs:  XPrefs.New().Set("Pairs/Source/Memory/mycodec", XPrefs.New().Set(prefsPairsCodec, CodecMsgpack)),
+			memory: []string{"mycodec"},
+		},
+		{
+			name:  "InvalidCodec",
+			prefs: XPrefs.New().Set("Pairs/Source/Memory/mycodec2", XPrefs.New().Set(prefsPairsCodec, "missing")),
+			panic: true,
+		},
+		{
+			name: "Invalid",
+			prefs: XPrefs.New().Set("Pairs/Source/Redis/myredis3", XPrefs.New().
+				Set(prefsPairsAddr, "redis://127.0.0.1:6379/db")),
+			panic: true,
+		},
+		{
+			name:  "Key",
+			prefs: XPrefs.New().Set("Pairs/Source/Redis", XPrefs.New()),
+			panic: true,
+		},
+		{
+			name:  "Nil",
+			prefs: nil,
+			panic: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.panic {
+				assert.Panics(t, func() { initPairs(test.prefs) }, "错误的配置应当 panic。")
+				return
+			}
+			assert.NotPanics(t, func() { initPairs(test.prefs) }, "正确的配置不应当 panic。")
+			for _, alias := range test.alias {
+				client := RedisOf(alias)
+				assert.NotNil(t, client, "配置的客户端应当被注册。")
+				assert.NoError(t, client.Ping(), "注册的客户端应当可以连接服务端。")
+				client.Close()
+			}
+			for _, alias := range test.consul {
+				client := ConsulOf(alias)
+				assert.NotNil(t, client, "配置的客户端应当被注册。")
+				assert.NoError(t, client.Put("key", "value"), "注册的客户端应当可以连接服务端。")
+			}
+			for _, alias := range test.memory {
+				client := MemoryOf(alias)
+				assert.NotNil(t, client, "配置的存储应当被注册。")
+				assert.NoError(t, client.Set("key", "value", 0), "注册的存储应当可以写入。")
+				client.Close()
+			}
+		})
+	}
+
+	assert.Nil(t, RedisOf("unknown"), "未注册的别名应当返回 nil。")
+	assert.Nil(t, ConsulOf("unknown"), "未注册的别名应当返回 nil。")
+	assert.Nil(t, MemoryOf("unknown"), "未注册的别名应当返回 nil。")
+	if val, ok := codecAliasMap.Load("mycodec"); assert.True(t, ok, "配置的编解码器应当被设置。") {
+		assert.Equal(t, CodecMsgpack, val, "配置的编解码器应当和预期相等。")
+	}
+}
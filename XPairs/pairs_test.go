@@ -0,0 +1,181 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPairsConformance 校验存储的行为是否符合 IPairs 接口的约定，ttl 表示存储是否支持过期时间。
+func testPairsConformance(t *testing.T, pairs IPairs, ttl bool) {
+	t.Run("Basic", func(t *testing.T) {
+		assert.NoError(t, pairs.Set("conf/a", "1", 0), "Set 不应当返回错误。")
+		value, err := pairs.Get("conf/a")
+		assert.NoError(t, err, "Get 不应当返回错误。")
+		assert.Equal(t, "1", value, "读取的值应当和写入的值相等。")
+		_, err = pairs.Get("conf/missing")
+		assert.ErrorIs(t, err, ErrNotFound, "不存在的键应当返回 ErrNotFound。")
+
+		ok, err := pairs.SetNX("conf/a", "2", 0)
+		assert.NoError(t, err, "SetNX 不应当返回错误。")
+		assert.False(t, ok, "键已存在时 SetNX 应当失败。")
+		ok, _ = pairs.SetNX("conf/b", "2", 0)
+		assert.True(t, ok, "键不存在时 SetNX 应当成功。")
+
+		count, err := pairs.Exists("conf/a", "conf/b", "conf/missing")
+		assert.NoError(t, err, "Exists 不应当返回错误。")
+		assert.Equal(t, 2, count, "存在的键数量应当为 2。")
+		keys, err := pairs.Keys("conf/")
+		assert.NoError(t, err, "Keys 不应当返回错误。")
+		assert.Equal(t, []string{"conf/a", "conf/b"}, keys, "列举的键名应当有序且和预期相等。")
+
+		ok, _ = pairs.CompareAndSwap("conf/a", "0", "3", 0)
+		assert.False(t, ok, "值不匹配时 CompareAndSwap 应当失败。")
+		ok, _ = pairs.CompareAndSwap("conf/a", "1", "3", 0)
+		assert.True(t, ok, "值匹配时 CompareAndSwap 应当成功。")
+		ok, _ = pairs.CompareAndSwap("conf/missing", "", "3", 0)
+		assert.False(t, ok, "键不存在时 CompareAndSwap 应当失败。")
+		ok, _ = pairs.CompareAndDelete("conf/a", "1")
+		assert.False(t, ok, "值不匹配时 CompareAndDelete 应当失败。")
+		ok, _ = pairs.CompareAndDelete("conf/a", "3")
+		assert.True(t, ok, "值匹配时 CompareAndDelete 应当成功。")
+
+		ttl, err := pairs.TTL("conf/b")
+		assert.NoError(t, err, "TTL 不应当返回错误。")
+		assert.Equal(t, time.Duration(-1), ttl, "未设置过期时间的键应当返回 -1。")
+		_, err = pairs.TTL("conf/missing")
+		assert.ErrorIs(t, err, ErrNotFound, "不存在的键应当返回 ErrNotFound。")
+
+		count, err = pairs.Delete("conf/a", "conf/b")
+		assert.NoError(t, err, "Delete 不应当返回错误。")
+		assert.Equal(t, 1, count, "被删除的键数量应当为 1。")
+		keys, _ = pairs.Keys("conf/")
+		assert.Empty(t, keys, "删除后前缀下不应当有键。")
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		err := pairs.Set("conf/ttl", "1", time.Minute)
+		if !ttl {
+			assert.ErrorIs(t, err, ErrNotSupported, "不支持过期时间的存储应当返回 ErrNotSupported。")
+			_, err = pairs.Expire("conf/ttl", time.Minute)
+			assert.ErrorIs(t, err, ErrNotSupported, "不支持过期时间的存储应当返回 ErrNotSupported。")
+			return
+		}
+		assert.NoError(t, err, "带过期时间的 Set 不应当返回错误。")
+		left, _ := pairs.TTL("conf/ttl")
+		assert.True(t, left > 0 && left <= time.Minute, "剩余过期时间应当在设置的范围内。")
+		ok, err := pairs.Expire("conf/ttl", 0)
+		assert.NoError(t, err, "Expire 不应当返回错误。")
+		assert.True(t, ok, "存在的键应当移除过期时间成功。")
+		left, _ = pairs.TTL("conf/ttl")
+		assert.Equal(t, time.Duration(-1), left, "移除过期时间后应当返回 -1。")
+		pairs.Delete("conf/ttl")
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		events := make(chan Event, 16)
+		stop, err := pairs.Watch("conf/", func(event Event) { events <- event })
+		assert.NoError(t, err, "Watch 不应当返回错误。")
+		defer stop()
+
+		wait := func() Event {
+			select {
+			case event := <-events:
+				return event
+			case <-time.After(3 * time.Second):
+				t.Fatal("等待变更事件超时。")
+				return Event{}
+			}
+		}
+		pairs.Set("conf/w", "1", 0)
+		assert.Equal(t, Event{Key: "conf/w", New: "1"}, wait(), "新建的事件应当和预期相等。")
+		pairs.Set("other", "1", 0)
+		pairs.Set("conf/w", "2", 0)
+		assert.Equal(t, Event{Key: "conf/w", Old: "1", New: "2"}, wait(), "修改的事件应当和预期相等。")
+		pairs.Delete("conf/w")
+		assert.Equal(t, Event{Key: "conf/w", Old: "2", Deleted: true}, wait(), "删除的事件应当和预期相等。")
+	})
+}
+
+func TestPairs(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		mc := NewMemory()
+		defer mc.Close()
+		testPairsConformance(t, mc, true)
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		server := newTestRedisServer(t)
+		rc, _ := NewRedis(server.Addr(), 2, 4)
+		defer rc.Close()
+		rc.interval = 20 * time.Millisecond
+		testPairsConformance(t, rc, true)
+	})
+
+	t.Run("Consul", func(t *testing.T) {
+		server := newTestConsulServer(t)
+		cc, _ := NewConsul(server.URL, 2, 4)
+		defer cc.Close()
+		cc.wait = time.Second
+		testPairsConformance(t, cc, false)
+	})
+
+	t.Run("Register", func(t *testing.T) {
+		mc1 := NewMemory()
+		Register("pairs_test", mc1)
+		assert.Equal(t, IPairs(mc1), Of("pairs_test"), "注册的存储应当和预期相等。")
+		assert.Contains(t, Aliases(), "pairs_test", "注册的别名应当被列举。")
+		assert.Nil(t, Of("pairs_missing"), "未注册的别名应当返回 nil。")
+
+		Register("pairs_test", mc1)
+		assert.NoError(t, mc1.Set("key", "value", 0), "重复注册相同的存储不应当关闭该存储。")
+
+		mc2 := NewMemory()
+		Register("pairs_test", mc2)
+		assert.Equal(t, mc2, MemoryOf("pairs_test"), "替换的存储应当和预期相等。")
+		assert.ErrorIs(t, mc1.Set("key", "value", 0), ErrClosed, "被替换的存储应当被关闭。")
+		assert.Nil(t, RedisOf("pairs_test"), "类型不匹配时应当返回 nil。")
+		mc2.Close()
+		pairsMap.Delete("pairs_test")
+	})
+
+	t.Run("Watcher", func(t *testing.T) {
+		var mutex sync.Mutex
+		keys := make([]string, 0)
+		done := make(chan struct{})
+		w := newWatcher("a/", func(event Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			keys = append(keys, event.Key)
+			if len(keys) == 100 {
+				close(done)
+			}
+		})
+		for i := 0; i < 100; i++ {
+			w.push(Event{Key: "a/" + time.Duration(i).String()}, Event{Key: "b/ignored"})
+		}
+		<-done
+		w.stop()
+		w.stop()
+		assert.True(t, w.stopped(), "停止后的分发器应当返回已停止。")
+		for i, key := range keys {
+			assert.Equal(t, "a/"+time.Duration(i).String(), key, "事件应当按照发生的顺序分发。")
+		}
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		events := diffPairs(map[string]string{"a": "1", "b": "2", "c": "3"}, map[string]string{"b": "2", "c": "4", "d": "5"})
+		assert.Equal(t, []Event{
+			{Key: "a", Old: "1", Deleted: true},
+			{Key: "c", Old: "3", New: "4"},
+			{Key: "d", New: "5"},
+		}, events, "快照的差异应当和预期相等。")
+		assert.Empty(t, diffPairs(nil, nil), "相同的快照不应当有差异。")
+	})
+}
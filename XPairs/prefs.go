@@ -0,0 +1,92 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eframework-org/GO.UTIL/XPrefs"
+)
+
+// PrefsView 是将存储中指定前缀的键值绑定为 XPrefs.IBase 的实时视图，使现有的首选项读取代码无需重启即可读取到最新的配置。
+// 配置项的键名为去除前缀后的键名，值为合法的 JSON 时按 JSON 解析（如 true、10、[1,2] 及对象），否则为原始的字符串。
+// 视图是线程安全的；Set 及 Unset 仅修改本地的视图，且将在键下次变更时被覆盖。
+type PrefsView struct {
+	XPrefs.IBase
+	prefix  string
+	handler func(event Event)
+	ready   chan struct{} // 初始的配置项加载完成时关闭
+	stop    func()
+}
+
+// NewPrefsView 监听指定别名的存储中 prefix 前缀的键值并创建实时视图，返回加载了所有现有配置项的视图。
+// handler 在变更被应用于视图之后按序回调（可以省略），用于响应配置的变更；别名未注册时返回 ErrNotRegistered。
+func NewPrefsView(alias, prefix string, handler ...func(event Event)) (*PrefsView, error) {
+	pairs := Of(alias)
+	if pairs == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRegistered, alias)
+	}
+	pv := &PrefsView{IBase: XPrefs.New(), prefix: prefix, ready: make(chan struct{})}
+	if len(handler) > 0 {
+		pv.handler = handler[0]
+	}
+	defer close(pv.ready)
+
+	// 先监听后加载，加载期间的变更将在加载完成后按序应用
+	stop, err := pairs.Watch(prefix, pv.apply)
+	if err != nil {
+		return nil, err
+	}
+	pv.stop = stop
+	keys, err := pairs.Keys(prefix)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	for _, key := range keys {
+		value, err := pairs.Get(key)
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrWrongType) {
+			continue
+		} else if err != nil {
+			stop()
+			return nil, err
+		}
+		pv.IBase.Set(strings.TrimPrefix(key, prefix), prefsValue(value))
+	}
+	return pv, nil
+}
+
+// Prefix 返回视图监听的前缀。
+func (pv *PrefsView) Prefix() string { return pv.prefix }
+
+// Close 停止监听，视图保留最后的配置项。
+func (pv *PrefsView) Close() { pv.stop() }
+
+// apply 将变更事件应用于视图并回调 handler，在初始的配置项加载完成之前等待。
+func (pv *PrefsView) apply(event Event) {
+	<-pv.ready
+	key := strings.TrimPrefix(event.Key, pv.prefix)
+	if _, nested := pv.IBase.Get(key).(XPrefs.IBase); nested || event.Deleted {
+		pv.IBase.Unset(key) // 同时清除多级配置的缓存
+	}
+	if !event.Deleted {
+		pv.IBase.Set(key, prefsValue(event.New))
+	}
+	if pv.handler != nil {
+		pv.handler(event)
+	}
+}
+
+// prefsValue 将键值解析为配置项的值，合法的 JSON 按 JSON 解析，否则为原始的字符串。
+func prefsValue(value string) any {
+	var ret any
+	if err := json.Unmarshal([]byte(value), &ret); err == nil {
+		return ret
+	}
+	return value
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eframework-org/GO.UTIL/XPrefs"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPrefsViewConformance 校验配置视图的基本行为，alias 为已注册的存储的别名。
+func testPrefsViewConformance(t *testing.T, alias string) {
+	pairs := Of(alias)
+	pairs.Set("config/Game/MaxLevel", "60", 0)
+	pairs.Set("config/Game/Name", "demo", 0)
+	pairs.Set("config/Feature/Enabled", "true", 0)
+	pairs.Set("other/Key", "1", 0)
+
+	events := make(chan Event, 16)
+	pv, err := NewPrefsView(alias, "config/", func(event Event) { events <- event })
+	assert.NoError(t, err, "NewPrefsView 不应当返回错误。")
+	defer pv.Close()
+	assert.Equal(t, "config/", pv.Prefix(), "视图的前缀应当和预期相等。")
+
+	var prefs XPrefs.IBase = pv
+	assert.ElementsMatch(t, []string{"Game/MaxLevel", "Game/Name", "Feature/Enabled"}, prefs.Keys(), "配置项的键名应当为去除前缀后的键名。")
+	assert.Equal(t, 60, prefs.GetInt("Game/MaxLevel"), "数值应当按 JSON 解析。")
+	assert.Equal(t, "demo", prefs.GetString("Game/Name"), "非 JSON 的值应当为原始的字符串。")
+	assert.True(t, prefs.GetBool("Feature/Enabled"), "布尔值应当按 JSON 解析。")
+
+	pairs.Set("config/Game/MaxLevel", "70", 0)
+	assert.Equal(t, Event{Key: "config/Game/MaxLevel", Old: "60", New: "70"}, <-events, "变更应当回调 handler。")
+	assert.Equal(t, 70, prefs.GetInt("Game/MaxLevel"), "变更后应当读取到最新的值。")
+
+	pairs.Set("config/Game/Rates", "[1,2,3]", 0)
+	<-events
+	assert.Equal(t, []int{1, 2, 3}, prefs.GetInts("Game/Rates"), "数组应当按 JSON 解析。")
+
+	pairs.Set("config/Server", `{"Port":8080}`, 0)
+	<-events
+	nested, ok := prefs.Get("Server").(XPrefs.IBase)
+	assert.True(t, ok, "对象应当被解析为多级配置。")
+	assert.Equal(t, 8080, nested.GetInt("Port"), "多级配置的值应当和预期相等。")
+	pairs.Set("config/Server", `{"Port":9090}`, 0)
+	<-events
+	nested, _ = prefs.Get("Server").(XPrefs.IBase)
+	assert.Equal(t, 9090, nested.GetInt("Port"), "多级配置变更后不应当读取到缓存的旧值。")
+
+	pairs.Delete("config/Game/Name")
+	<-events
+	assert.False(t, prefs.Has("Game/Name"), "删除的键应当从视图中移除。")
+	assert.Equal(t, "fallback", prefs.GetString("Game/Name", "fallback"), "删除的键应当返回默认值。")
+
+	pv.Close()
+	pairs.Set("config/Game/MaxLevel", "80", 0)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 70, prefs.GetInt("Game/MaxLevel"), "关闭后视图应当保留最后的配置项。")
+}
+
+func TestPrefsView(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		Register("prefs_memory", NewMemory())
+		defer func() {
+			Of("prefs_memory").Close()
+			pairsMap.Delete("prefs_memory")
+		}()
+		testPrefsViewConformance(t, "prefs_memory")
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		server := newTestRedisServer(t)
+		rc, _ := NewRedis(server.Addr(), 2, 8)
+		rc.Do("CONFIG", "SET", "notify-keyspace-events", "KA")
+		Register("prefs_redis", rc)
+		defer func() {
+			rc.Close()
+			pairsMap.Delete("prefs_redis")
+		}()
+		testPrefsViewConformance(t, "prefs_redis")
+	})
+
+	t.Run("Value", func(t *testing.T) {
+		tests := []struct {
+			value    string
+			expected any
+		}{
+			{"10", float64(10)},
+			{"1.5", 1.5},
+			{"false", false},
+			{`"quoted"`, "quoted"},
+			{"plain", "plain"},
+			{"007", "007"},
+			{"", ""},
+			{`[1,"a"]`, []any{float64(1), "a"}},
+		}
+		for _, test := range tests {
+			assert.Equal(t, test.expected, prefsValue(test.value), "键值的解析结果应当和预期相等：%q", test.value)
+		}
+	})
+
+	t.Run("Unregistered", func(t *testing.T) {
+		_, err := NewPrefsView("prefs_missing", "config/")
+		assert.ErrorIs(t, err, ErrNotRegistered, "未注册的别名应当返回 ErrNotRegistered。")
+	})
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Message 是发布订阅的消息。
+type Message struct {
+	Channel string // 消息发布的频道
+	Pattern string // 匹配该消息的订阅模式
+	Payload string // 消息的内容
+}
+
+// IPubSub 是发布订阅的接口，存储实现该接口时可以使用 Publish 及 Subscribe。
+// 订阅模式使用 Redis 的 glob 风格：* 匹配任意字符串，? 匹配单个字符，[abc]、[^a]、[a-z] 匹配字符集合，\ 转义特殊字符。
+type IPubSub interface {
+	// Publish 向频道发布消息，返回接收到消息的订阅者数量，无法统计时返回 0。
+	Publish(channel, message string) (int, error)
+
+	// Subscribe 订阅匹配模式的频道，返回接收消息的通道及取消订阅的函数。
+	// 取消订阅或存储被关闭后通道将被关闭；连接断开时将自动重连并重新订阅，断开期间发布的消息将会丢失。
+	Subscribe(pattern string) (<-chan Message, func(), error)
+}
+
+// Publish 向指定别名的存储的频道发布消息，返回接收到消息的订阅者数量。
+// 别名未注册时返回 ErrNotRegistered，存储未实现 IPubSub 时返回 ErrNotSupported。
+func Publish(alias, channel, message string) (int, error) {
+	ps, err := pubsubFor(alias)
+	if err != nil {
+		return 0, err
+	}
+	return ps.Publish(channel, message)
+}
+
+// Subscribe 订阅指定别名的存储中匹配模式的频道，返回接收消息的通道及取消订阅的函数。
+// 别名未注册时返回 ErrNotRegistered，存储未实现 IPubSub 时返回 ErrNotSupported。
+func Subscribe(alias, pattern string) (<-chan Message, func(), error) {
+	ps, err := pubsubFor(alias)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ps.Subscribe(pattern)
+}
+
+// pubsubFor 返回指定别名的发布订阅实现。
+func pubsubFor(alias string) (IPubSub, error) {
+	pairs := Of(alias)
+	if pairs == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRegistered, alias)
+	}
+	ps, ok := pairs.(IPubSub)
+	if !ok {
+		return nil, fmt.Errorf("%w: pubsub of %v", ErrNotSupported, alias)
+	}
+	return ps, nil
+}
+
+// subscriber 是订阅者，使用无界队列按序投递消息，避免慢速的消费者阻塞消息的分发。
+type subscriber struct {
+	pattern  string
+	messages []Message
+	out      chan Message
+	signal   chan struct{}
+	done     chan struct{}
+	once     sync.Once
+	mutex    sync.Mutex
+}
+
+// newSubscriber 创建并启动订阅者。
+func newSubscriber(pattern string) *subscriber {
+	s := &subscriber{pattern: pattern, out: make(chan Message), signal: make(chan struct{}, 1), done: make(chan struct{})}
+	go s.run()
+	return s
+}
+
+// match 判断频道是否匹配订阅模式。
+func (s *subscriber) match(channel string) bool { return globMatch(s.pattern, channel) }
+
+// push 将消息加入队列，返回频道是否匹配订阅模式，不匹配的消息将被忽略。
+func (s *subscriber) push(channel, payload string) bool {
+	if !s.match(channel) {
+		return false
+	}
+	s.mutex.Lock()
+	s.messages = append(s.messages, Message{Channel: channel, Pattern: s.pattern, Payload: payload})
+	s.mutex.Unlock()
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// stop 停止投递并关闭通道，可以重复调用。
+func (s *subscriber) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// run 按序投递队列中的消息，停止后关闭通道。
+func (s *subscriber) run() {
+	defer close(s.out)
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.signal:
+		}
+		for {
+			s.mutex.Lock()
+			messages := s.messages
+			s.messages = nil
+			s.mutex.Unlock()
+			if len(messages) == 0 {
+				break
+			}
+			for _, message := range messages {
+				select {
+				case <-s.done:
+					return
+				case s.out <- message:
+				}
+			}
+		}
+	}
+}
+
+// globMatch 判断字符串是否匹配 Redis 的 glob 风格的模式，按字节进行匹配。
+func globMatch(pattern, str string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(str); i++ {
+				if globMatch(pattern[1:], str[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(str) == 0 {
+				return false
+			}
+			str = str[1:]
+		case '[':
+			if len(str) == 0 {
+				return false
+			}
+			set := pattern[1:]
+			not := len(set) > 0 && set[0] == '^'
+			if not {
+				set = set[1:]
+			}
+			matched := false
+			for len(set) > 0 && set[0] != ']' {
+				if set[0] == '\\' && len(set) >= 2 {
+					matched = matched || set[1] == str[0]
+					set = set[2:]
+				} else if len(set) >= 3 && set[1] == '-' {
+					lo, hi := set[0], set[2]
+					if lo > hi {
+						lo, hi = hi, lo
+					}
+					matched = matched || (str[0] >= lo && str[0] <= hi)
+					set = set[3:]
+				} else {
+					matched = matched || set[0] == str[0]
+					set = set[1:]
+				}
+			}
+			if not {
+				matched = !matched
+			}
+			if !matched {
+				return false
+			}
+			str = str[1:]
+			if len(set) == 0 { // 未闭合的字符集合视为到达模式的末尾
+				pattern = set
+				continue
+			}
+			pattern = set
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(str) == 0 || pattern[0] != str[0] {
+				return false
+			}
+			str = str[1:]
+		}
+		pattern = pattern[1:]
+	}
+	return len(str) == 0
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testReceive 在超时之前读取通道中的消息，超时时返回 false。
+func testReceive(ch <-chan Message, timeout time.Duration) (Message, bool) {
+	select {
+	case message, ok := <-ch:
+		return message, ok
+	case <-time.After(timeout):
+		return Message{}, false
+	}
+}
+
+// testPubSubConformance 校验发布订阅的基本行为，counted 表示存储是否返回接收到消息的订阅者数量。
+func testPubSubConformance(t *testing.T, ps IPubSub, counted bool) {
+	chat, unchat, err := ps.Subscribe("chat.*")
+	assert.NoError(t, err, "Subscribe 不应当返回错误。")
+	news, unnews, err := ps.Subscribe("news")
+	assert.NoError(t, err, "Subscribe 不应当返回错误。")
+
+	count, err := ps.Publish("chat.world", "hello")
+	assert.NoError(t, err, "Publish 不应当返回错误。")
+	if counted {
+		assert.Equal(t, 1, count, "接收到消息的订阅者数量应当为 1。")
+	}
+	ps.Publish("chat.guild", "hi")
+	ps.Publish("news", "update")
+	ps.Publish("mail", "ignored")
+
+	message, ok := testReceive(chat, 3*time.Second)
+	assert.True(t, ok, "订阅者应当接收到消息。")
+	assert.Equal(t, Message{Channel: "chat.world", Pattern: "chat.*", Payload: "hello"}, message, "接收到的消息应当和预期相等。")
+	message, _ = testReceive(chat, 3*time.Second)
+	assert.Equal(t, "chat.guild", message.Channel, "消息应当按发布的顺序投递。")
+	message, _ = testReceive(news, 3*time.Second)
+	assert.Equal(t, "update", message.Payload, "精确匹配的订阅者应当接收到消息。")
+	_, ok = testReceive(news, 200*time.Millisecond)
+	assert.False(t, ok, "不匹配的频道不应当被投递。")
+
+	unchat()
+	_, ok = <-chat
+	assert.False(t, ok, "取消订阅后通道应当被关闭。")
+	if counted {
+		assert.Eventually(t, func() bool {
+			count, _ = ps.Publish("chat.world", "bye")
+			return count == 0
+		}, 3*time.Second, 20*time.Millisecond, "取消订阅后不应当有订阅者接收到消息。")
+	}
+	unnews()
+	unnews()
+}
+
+func TestPubSub(t *testing.T) {
+	t.Run("Glob", func(t *testing.T) {
+		tests := []struct {
+			pattern string
+			str     string
+			match   bool
+		}{
+			{"*", "", true},
+			{"chat.*", "chat.world", true},
+			{"chat.*", "chat", false},
+			{"h?llo", "hello", true},
+			{"h?llo", "hllo", false},
+			{"h[ae]llo", "hallo", true},
+			{"h[ae]llo", "hillo", false},
+			{"h[^e]llo", "hallo", true},
+			{"h[^e]llo", "hello", false},
+			{"h[a-b]llo", "hbllo", true},
+			{"h[b-a]llo", "hallo", true},
+			{"h[a-b]llo", "hcllo", false},
+			{`h\*llo`, "h*llo", true},
+			{`h\*llo`, "hello", false},
+			{`h[\]]llo`, "h]llo", true},
+			{"a**b*c", "axxbyyc", true},
+			{"a*b*c", "axxbyy", false},
+			{"news", "news", true},
+			{"news", "newsx", false},
+		}
+		for _, test := range tests {
+			assert.Equal(t, test.match, globMatch(test.pattern, test.str), "模式匹配的结果应当和预期相等：%v %v", test.pattern, test.str)
+		}
+	})
+
+	t.Run("Memory", func(t *testing.T) {
+		mc := NewMemory()
+		testPubSubConformance(t, mc, true)
+
+		ch, _, _ := mc.Subscribe("*")
+		mc.Close()
+		_, ok := <-ch
+		assert.False(t, ok, "关闭存储后通道应当被关闭。")
+		_, err := mc.Publish("chat", "hello")
+		assert.ErrorIs(t, err, ErrClosed, "关闭后 Publish 应当返回 ErrClosed。")
+		_, _, err = mc.Subscribe("*")
+		assert.ErrorIs(t, err, ErrClosed, "关闭后 Subscribe 应当返回 ErrClosed。")
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		server := newTestRedisServer(t)
+		rc, _ := NewRedis(server.Addr(), 2, 8)
+		rc.retry = 50 * time.Millisecond
+		testPubSubConformance(t, rc, true)
+
+		ch, _, _ := rc.Subscribe("room.*")
+		server.kill()
+		deadline := time.Now().Add(3 * time.Second)
+		count := 0
+		for count == 0 && time.Now().Before(deadline) {
+			time.Sleep(20 * time.Millisecond)
+			count, _ = rc.Publish("room.1", "reconnected")
+		}
+		assert.Equal(t, 1, count, "断线重连后应当自动重新订阅。")
+		message, ok := testReceive(ch, 3*time.Second)
+		assert.True(t, ok, "断线重连后订阅者应当接收到消息。")
+		assert.Equal(t, "reconnected", message.Payload, "断线重连后接收到的消息应当和预期相等。")
+
+		rc.Close()
+		_, ok = <-ch
+		assert.False(t, ok, "关闭客户端后通道应当被关闭。")
+		_, _, err := rc.Subscribe("*")
+		assert.ErrorIs(t, err, ErrClosed, "关闭后 Subscribe 应当返回 ErrClosed。")
+	})
+
+	t.Run("Consul", func(t *testing.T) {
+		server := newTestConsulServer(t)
+		cc, _ := NewConsul(server.URL, 2, 8)
+		testPubSubConformance(t, cc, false)
+
+		keys, _ := cc.Keys(ConsulPubSubPrefix + "chat.world/")
+		assert.Len(t, keys, 1, "消息应当被写入频道目录下的键。")
+		cc.purge(ConsulPubSubPrefix+"chat.world/", time.Now())
+		keys, _ = cc.Keys(ConsulPubSubPrefix + "chat.world/")
+		assert.Empty(t, keys, "过期的消息应当被清理。")
+
+		ch, _, _ := cc.Subscribe("*")
+		cc.Close()
+		_, ok := testReceive(ch, 3*time.Second)
+		assert.False(t, ok, "关闭客户端后通道应当被关闭。")
+	})
+
+	t.Run("Alias", func(t *testing.T) {
+		Register("pubsub_test", NewMemory())
+		defer func() {
+			Of("pubsub_test").Close()
+			pairsMap.Delete("pubsub_test")
+		}()
+
+		ch, cancel, err := Subscribe("pubsub_test", "alias.*")
+		assert.NoError(t, err, "Subscribe 不应当返回错误。")
+		defer cancel()
+		count, err := Publish("pubsub_test", "alias.a", "hello")
+		assert.NoError(t, err, "Publish 不应当返回错误。")
+		assert.Equal(t, 1, count, "接收到消息的订阅者数量应当为 1。")
+		message, _ := testReceive(ch, time.Second)
+		assert.Equal(t, "hello", message.Payload, "接收到的消息应当和预期相等。")
+
+		_, err = Publish("pubsub_missing", "alias.a", "hello")
+		assert.ErrorIs(t, err, ErrNotRegistered, "未注册的别名应当返回 ErrNotRegistered。")
+		_, _, err = Subscribe("pubsub_missing", "*")
+		assert.ErrorIs(t, err, ErrNotRegistered, "未注册的别名应当返回 ErrNotRegistered。")
+	})
+}
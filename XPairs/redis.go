@@ -3,3 +3,1096 @@
 // license that can be found in the LICENSE file.
 
 package XPairs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// RedisTimeout 是 Redis 连接的默认超时时间，包括建立连接及单次命令的读写。
+	RedisTimeout = 5 * time.Second
+
+	// RedisWatchInterval 是 Redis 监听键变更的默认轮询间隔，服务端未开启键空间通知时使用。
+	RedisWatchInterval = time.Second
+
+	// RedisWatchResync 是基于键空间通知监听时全量对比的间隔，用于弥补订阅连接断开期间丢失的通知。
+	RedisWatchResync = time.Minute
+
+	// RedisRetry 是 Redis 连接失败后首次重连的间隔，连续失败时加倍，直至 RedisBackoff。
+	RedisRetry = time.Second
+
+	// RedisBackoff 是 Redis 连接失败后重连间隔的上限。
+	RedisBackoff = 30 * time.Second
+
+	// RedisPingInterval 是 Redis 连接池健康检查的间隔，检查时关闭过期的连接，并使用 PING 检查空闲超过该间隔的连接。
+	RedisPingInterval = 30 * time.Second
+
+	// RedisIdleTimeout 是 Redis 连接的最长空闲时间，超出时关闭连接。
+	RedisIdleTimeout = 5 * time.Minute
+
+	// RedisMaxAge 是 Redis 连接的最长存活时间，超出时关闭连接，使故障转移后的连接逐渐迁移至新的节点。
+	RedisMaxAge = 30 * time.Minute
+
+	// redisScanCount 是 SCAN 命令每次迭代的建议数量。
+	redisScanCount = 1000
+)
+
+// RedisError 表示 Redis 服务端返回的错误应答，如 WRONGTYPE 等。
+type RedisError string
+
+// Error 返回错误信息。
+func (e RedisError) Error() string { return string(e) }
+
+// RedisClient 是基于 RESP 协议的 Redis 客户端，实现了 IPairs 接口，内置连接池且是线程安全的。
+type RedisClient struct {
+	health
+	addr     string         // 服务地址，格式为 host:port，Sentinel 及集群模式下为以逗号分隔的多个地址
+	username string         // 认证的用户名（Redis 6.0 的 ACL），为空时仅使用密码认证
+	password string         // 认证密码
+	db       int            // 数据库索引
+	timeout  time.Duration  // 超时时间
+	interval time.Duration  // 监听的轮询间隔
+	resync   time.Duration  // 基于键空间通知监听时全量对比的间隔
+	ping     time.Duration  // 连接池健康检查的间隔，为 0 时不检查
+	idle     time.Duration  // 连接的最长空闲时间，为 0 时不限制
+	age      time.Duration  // 连接的最长存活时间，为 0 时不限制
+	sentinel *redisSentinel // Sentinel 模式的配置，为 nil 时不使用 Sentinel
+	cluster  *redisCluster  // 集群模式的拓扑，为 nil 时不使用集群
+	idles    chan *redisConn
+	slots    chan struct{} // 连接数量的信号量，为 nil 时不限制
+	opened   atomic.Int64  // 连接池已建立的连接数量
+	inuse    atomic.Int64  // 连接池使用中的连接数量
+	watchers []*watcher
+	pubsub   *redisPubSub // 订阅连接，在首次订阅时创建
+	checker  sync.Once    // 健康检查在首次获取连接时启动
+	done     chan struct{}
+	closed   bool
+	mutex    sync.RWMutex
+}
+
+// redisConn 是 Redis 的单个连接。
+type redisConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	addr    string    // 连接的服务地址
+	created time.Time // 建立的时间
+	used    time.Time // 最后归还连接池的时间
+}
+
+// NewRedis 创建 Redis 客户端。
+// addr 为服务地址，支持 host:port 或 redis://[[username]:password@]host:port[/db][?ping=<间隔>&idle=<时长>&age=<时长>] 格式，
+// 指定用户名时使用 AUTH <username> <password> 认证（Redis 6.0 的 ACL），仅指定 redis://password@ 时视为密码；
+// 查询参数分别覆盖 RedisPingInterval、RedisIdleTimeout 及 RedisMaxAge，格式如 30s、5m，为 0 时不检查或不限制；
+// Sentinel 模式的格式为 redis+sentinel://[[username]:password@]host:port[,host:port...]/<主节点名称>[/db][?sentinel_username=<用户名>&sentinel_password=<密码>]，
+// 集群模式的格式为 redis+cluster://[[username]:password@]host:port[,host:port...]，查询参数与 redis:// 相同；
+// pool 为连接池的空闲连接数量，conn 为最大连接数，小于等于 0 时不限制，集群模式下为每个节点的数量。
+// 客户端实现了 IPairs 接口，连接将在首次执行命令时建立，返回创建的客户端及地址的解析错误。
+func NewRedis(addr string, pool, conn int) (*RedisClient, error) {
+	rc := &RedisClient{
+		health:   health{retry: RedisRetry, backoff: RedisBackoff},
+		timeout:  RedisTimeout,
+		interval: RedisWatchInterval,
+		resync:   RedisWatchResync,
+		ping:     RedisPingInterval,
+		idle:     RedisIdleTimeout,
+		age:      RedisMaxAge,
+		done:     make(chan struct{}),
+	}
+	if strings.Contains(addr, "://") {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return nil, err
+		}
+		rc.addr = u.Host
+		path := strings.Trim(u.Path, "/")
+		switch u.Scheme {
+		case "redis":
+			if strings.Contains(u.Host, ",") {
+				return nil, fmt.Errorf("multiple hosts of redis addr, use redis+sentinel or redis+cluster instead")
+			}
+		case "redis+sentinel":
+			name, db, _ := strings.Cut(path, "/")
+			if name == "" {
+				return nil, fmt.Errorf("empty master name of redis sentinel addr")
+			}
+			rc.sentinel = &redisSentinel{addrs: strings.Split(u.Host, ","), name: name, username: u.Query().Get("sentinel_username"), password: u.Query().Get("sentinel_password")}
+			path = db
+		case "redis+cluster":
+			if path != "" && path != "0" {
+				return nil, fmt.Errorf("redis cluster only supports db 0")
+			}
+			path = ""
+			rc.cluster = newRedisCluster(rc, strings.Split(u.Host, ","))
+		default:
+			return nil, fmt.Errorf("unsupported scheme %v of redis addr", u.Scheme)
+		}
+		if u.User != nil {
+			if password, ok := u.User.Password(); ok {
+				rc.username, rc.password = u.User.Username(), password
+			} else { // redis://password@host 兼容仅指定密码的格式
+				rc.password = u.User.Username()
+			}
+		}
+		if path != "" {
+			db, err := strconv.Atoi(path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid db %v of redis addr", path)
+			}
+			rc.db = db
+		}
+		for name, field := range map[string]*time.Duration{"ping": &rc.ping, "idle": &rc.idle, "age": &rc.age} {
+			if value := u.Query().Get(name); value != "" {
+				duration, err := time.ParseDuration(value)
+				if err != nil || duration < 0 {
+					return nil, fmt.Errorf("invalid %v %v of redis addr", name, value)
+				}
+				*field = duration
+			}
+		}
+	} else {
+		rc.addr = addr
+	}
+	if rc.addr == "" {
+		return nil, fmt.Errorf("empty redis addr")
+	}
+	for _, node := range strings.Split(rc.addr, ",") {
+		if _, _, err := net.SplitHostPort(node); err != nil {
+			return nil, err
+		}
+	}
+	rc.target = rc.addr
+	if pool < 0 {
+		pool = 0
+	}
+	if conn > 0 {
+		rc.slots = make(chan struct{}, conn)
+		pool = min(pool, conn)
+	}
+	rc.idles = make(chan *redisConn, pool)
+	return rc, nil
+}
+
+// clone 创建连接至指定地址的单节点客户端，配置与当前的客户端相同，用于集群模式的节点。
+func (rc *RedisClient) clone(addr string) *RedisClient {
+	node := &RedisClient{
+		health:   health{target: addr, retry: rc.retry, backoff: rc.backoff},
+		addr:     addr,
+		username: rc.username,
+		password: rc.password,
+		timeout:  rc.timeout,
+		interval: rc.interval,
+		resync:   rc.resync,
+		ping:     rc.ping,
+		idle:     rc.idle,
+		age:      rc.age,
+		idles:    make(chan *redisConn, cap(rc.idles)),
+		done:     make(chan struct{}),
+	}
+	if rc.slots != nil {
+		node.slots = make(chan struct{}, cap(rc.slots))
+	}
+	return node
+}
+
+// RegisterRedis 创建并注册指定别名的 Redis 客户端，参数与 NewRedis 相同，注册规则与 Register 相同。
+func RegisterRedis(alias, addr string, pool, conn int) error {
+	rc, err := NewRedis(addr, pool, conn)
+	if err != nil {
+		return err
+	}
+	Register(alias, rc)
+	return nil
+}
+
+// RedisOf 返回指定别名的 Redis 客户端，未注册或类型不匹配时返回 nil。
+func RedisOf(alias string) *RedisClient {
+	rc, _ := Of(alias).(*RedisClient)
+	return rc
+}
+
+// Do 执行 Redis 命令并返回应答。
+// args 为命令及其参数，如 Do("SET", "key", "value")，参数支持字符串、字节切片、数值及布尔类型。
+// 应答的类型为：简单字符串及批量字符串返回 string，整数返回 int64，数组返回 []any，空值返回 nil；
+// 服务端的错误应答返回 RedisError。
+func (rc *RedisClient) Do(args ...any) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty redis command")
+	}
+	var reply any
+	err := rc.on(redisCommandKey(args), func(conn *redisConn) (err error) {
+		reply, err = conn.call(rc.timeout, args...)
+		return err
+	})
+	return reply, err
+}
+
+// Get 读取键的值，键不存在时返回 ErrNotFound。
+func (rc *RedisClient) Get(key string) (string, error) {
+	reply, err := rc.Do("GET", key)
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", ErrNotFound
+	}
+	return redisString(reply)
+}
+
+// Set 设置键的值，ttl 为过期时间，小于等于 0 时不过期。
+func (rc *RedisClient) Set(key, value string, ttl time.Duration) error {
+	var err error
+	if ttl > 0 {
+		_, err = rc.Do("SET", key, value, "PX", ttl.Milliseconds())
+	} else {
+		_, err = rc.Do("SET", key, value)
+	}
+	return err
+}
+
+// SetNX 仅在键不存在时设置键的值，返回是否设置成功。
+func (rc *RedisClient) SetNX(key, value string, ttl time.Duration) (bool, error) {
+	var reply any
+	var err error
+	if ttl > 0 {
+		reply, err = rc.Do("SET", key, value, "NX", "PX", ttl.Milliseconds())
+	} else {
+		reply, err = rc.Do("SET", key, value, "NX")
+	}
+	return reply != nil, err
+}
+
+// IncrBy 使用 INCRBY 命令将键的整数值原子地增加 delta 并返回增加后的值，键不存在时视为 0，不改变键的过期时间。
+func (rc *RedisClient) IncrBy(key string, delta int64) (int64, error) {
+	reply, err := rc.Do("INCRBY", key, delta)
+	if err != nil {
+		return 0, err
+	}
+	if val, ok := reply.(int64); ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("unexpected reply type %T", reply)
+}
+
+// Delete 删除一个或多个键，返回被删除的键数量。
+func (rc *RedisClient) Delete(keys ...string) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	return redisInt(rc.Do(redisArgs("DEL", keys)...))
+}
+
+// Exists 返回一个或多个键中存在的数量。
+func (rc *RedisClient) Exists(keys ...string) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	return redisInt(rc.Do(redisArgs("EXISTS", keys)...))
+}
+
+// Expire 设置键的过期时间，ttl 小于等于 0 时移除过期时间（永不过期），返回键是否存在。
+func (rc *RedisClient) Expire(key string, ttl time.Duration) (bool, error) {
+	var count int
+	var err error
+	if ttl > 0 {
+		count, err = redisInt(rc.Do("PEXPIRE", key, ttl.Milliseconds()))
+	} else {
+		if count, err = redisInt(rc.Do("PERSIST", key)); err == nil && count == 0 {
+			count, err = rc.Exists(key)
+		}
+	}
+	return count > 0, err
+}
+
+// TTL 返回键的剩余过期时间，未设置过期时间时返回 -1，键不存在时返回 ErrNotFound。
+func (rc *RedisClient) TTL(key string) (time.Duration, error) {
+	ms, err := redisInt(rc.Do("PTTL", key))
+	if err != nil {
+		return 0, err
+	}
+	switch ms {
+	case -2:
+		return 0, ErrNotFound
+	case -1:
+		return -1, nil
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// Rename 使用 RENAME 命令将键重命名为 newkey，newkey 已存在时将被覆盖，键不存在时返回 ErrNotFound。
+func (rc *RedisClient) Rename(key, newkey string) error {
+	_, err := rc.Do("RENAME", key, newkey)
+	var rerr RedisError
+	if errors.As(err, &rerr) && strings.HasPrefix(string(rerr), "ERR no such key") {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Keys 使用 SCAN 命令列举指定前缀的键名，结果按字典序排列，前缀为空时列举所有的键。
+// 与 KEYS 命令不同，SCAN 不会阻塞服务端，但列举期间发生变更的键可能被遗漏。
+func (rc *RedisClient) Keys(prefix string) ([]string, error) {
+	if rc.cluster != nil {
+		return rc.cluster.keys(prefix)
+	}
+	match := redisGlobEscape(prefix) + "*"
+	founds := make(map[string]struct{})
+	cursor := "0"
+	for {
+		reply, err := rc.Do("SCAN", cursor, "MATCH", match, "COUNT", redisScanCount)
+		if err != nil {
+			return nil, err
+		}
+		items, ok := reply.([]any)
+		if !ok || len(items) != 2 {
+			return nil, fmt.Errorf("unexpected reply of SCAN: %v", reply)
+		}
+		if cursor, err = redisString(items[0]); err != nil {
+			return nil, err
+		}
+		keys, _ := items[1].([]any)
+		for _, key := range keys {
+			if str, ok := key.(string); ok {
+				founds[str] = struct{}{}
+			}
+		}
+		if cursor == "0" {
+			break
+		}
+	}
+	keys := make([]string, 0, len(founds))
+	for key := range founds {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// CompareAndSwap 仅在键存在且值等于 old 时将其设置为 value，返回是否设置成功。
+// 使用 WATCH 及 MULTI/EXEC 实现乐观锁，比较期间键被其他客户端修改时返回 false。
+func (rc *RedisClient) CompareAndSwap(key, old, value string, ttl time.Duration) (bool, error) {
+	args := []any{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", ttl.Milliseconds())
+	}
+	return rc.compareAndExec(key, old, args...)
+}
+
+// CompareAndDelete 仅在键存在且值等于 old 时删除该键，返回是否删除成功。
+func (rc *RedisClient) CompareAndDelete(key, old string) (bool, error) {
+	return rc.compareAndExec(key, old, "DEL", key)
+}
+
+// Watch 监听指定前缀的键值，对比快照后回调变更事件，返回停止监听的函数；与 Redis 的语义一致，仅监听字符串类型的键。
+// 服务端开启了键空间通知（notify-keyspace-events 包含 K 及 A 或 $gx）时订阅 __keyspace@<db>__:<prefix>* 频道，
+// 收到通知后读取键的最新值，并以 RedisWatchResync 为间隔全量对比以弥补订阅断开期间丢失的通知；
+// 否则以 RedisWatchInterval 为间隔轮询，轮询间隔内多次变更的键仅回调最终的变更，且每次轮询均会列举并读取所有匹配的键，故应当避免监听数量较大的前缀。
+func (rc *RedisClient) Watch(prefix string, handler func(event Event)) (func(), error) {
+	var notices <-chan Message
+	var unsubscribe func()
+	channel := fmt.Sprintf("__keyspace@%v__:", rc.db)
+	if rc.notifying() {
+		var err error
+		if notices, unsubscribe, err = rc.Subscribe(channel + redisGlobEscape(prefix) + "*"); err != nil {
+			return nil, err
+		}
+	}
+	snapshot, err := rc.snapshot(prefix)
+	if err != nil {
+		if unsubscribe != nil {
+			unsubscribe()
+		}
+		return nil, err
+	}
+	w := newWatcher(prefix, handler)
+	rc.mutex.Lock()
+	if rc.closed {
+		rc.mutex.Unlock()
+		w.stop()
+		if unsubscribe != nil {
+			unsubscribe()
+		}
+		return nil, ErrClosed
+	}
+	rc.watchers = append(rc.watchers, w)
+	rc.mutex.Unlock()
+
+	interval := rc.interval
+	if unsubscribe != nil {
+		interval = rc.resync
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		if unsubscribe != nil {
+			defer unsubscribe()
+		}
+		for {
+			select {
+			case <-w.done:
+				return
+			case notice, ok := <-notices:
+				if !ok { // 客户端被关闭
+					w.stop()
+					return
+				}
+				rc.refresh(w, snapshot, strings.TrimPrefix(notice.Channel, channel))
+				continue
+			case <-ticker.C:
+			}
+			nsnapshot, err := rc.snapshot(prefix)
+			if err != nil {
+				if errors.Is(err, ErrClosed) {
+					w.stop()
+					return
+				}
+				continue
+			}
+			if events := diffPairs(snapshot, nsnapshot); len(events) > 0 {
+				w.push(events...)
+			}
+			snapshot = nsnapshot
+		}
+	}()
+	return func() {
+		w.stop()
+		rc.mutex.Lock()
+		defer rc.mutex.Unlock()
+		for i, tw := range rc.watchers {
+			if tw == w {
+				rc.watchers = append(rc.watchers[:i], rc.watchers[i+1:]...)
+				break
+			}
+		}
+	}, nil
+}
+
+// MGet 批量读取键的值，返回存在的键值对。
+func (rc *RedisClient) MGet(keys ...string) (map[string]string, error) {
+	rets := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return rets, nil
+	}
+	reply, err := rc.Do(redisArgs("MGET", keys)...)
+	if err != nil {
+		return nil, err
+	}
+	vals, ok := reply.([]any)
+	if !ok || len(vals) != len(keys) {
+		return nil, fmt.Errorf("unexpected reply of MGET: %v", reply)
+	}
+	for i, val := range vals {
+		if val != nil {
+			if rets[keys[i]], err = redisString(val); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rets, nil
+}
+
+// MSet 批量设置键的值，操作是原子的且不设置过期时间。
+func (rc *RedisClient) MSet(pairs map[string]string) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(pairs)*2+1)
+	args = append(args, "MSET")
+	for key, value := range pairs {
+		args = append(args, key, value)
+	}
+	_, err := rc.Do(args...)
+	return err
+}
+
+// Ping 检查与服务端的连接，连接失败后等待重连期间返回 ErrUnavailable。
+func (rc *RedisClient) Ping() error {
+	_, err := rc.Do("PING")
+	return err
+}
+
+// Close 关闭客户端、所有的监听、订阅及连接池中的空闲连接，关闭后的命令将返回 ErrClosed。
+func (rc *RedisClient) Close() error {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	if rc.closed {
+		return nil
+	}
+	rc.closed = true
+	close(rc.done)
+	for _, w := range rc.watchers {
+		w.stop()
+	}
+	rc.watchers = nil
+	if rc.pubsub != nil {
+		rc.pubsub.stop()
+	}
+	if rc.cluster != nil {
+		rc.cluster.close()
+	}
+	for {
+		select {
+		case conn := <-rc.idles:
+			rc.discard(conn)
+		default:
+			return nil
+		}
+	}
+}
+
+// Ready 返回客户端是否就绪，连接服务端失败后至重连成功之前返回 false，集群模式下所有的主节点就绪时返回 true。
+func (rc *RedisClient) Ready() bool {
+	if rc.cluster != nil {
+		return rc.cluster.ready()
+	}
+	return rc.health.Ready()
+}
+
+// Stats 返回连接池的统计信息，不包括订阅连接，集群模式下为所有节点之和。
+func (rc *RedisClient) Stats() PoolStats {
+	if rc.cluster != nil {
+		return rc.cluster.stats()
+	}
+	size, inuse := int(rc.opened.Load()), int(rc.inuse.Load())
+	return PoolStats{Size: size, InUse: inuse, Idle: max(size-inuse, 0)}
+}
+
+// with 从连接池中获取连接并执行 fn，fn 可以在同一连接上执行多条命令（如事务），执行后归还连接。
+func (rc *RedisClient) with(fn func(conn *redisConn) error) error {
+	start := time.Now()
+	conn, err := rc.get()
+	if err == nil {
+		err = fn(conn)
+		rc.put(conn, err)
+	}
+	rc.observe(start, redisBroken(err))
+	return err
+}
+
+// on 在 key 所属的节点上执行 fn，key 为空时在任意节点上执行；非集群模式时等价于 with，集群模式下跟随 MOVED 及 ASK 重定向。
+func (rc *RedisClient) on(key string, fn func(conn *redisConn) error) error {
+	if rc.cluster == nil {
+		return rc.with(fn)
+	}
+	start := time.Now()
+	err := rc.cluster.on(key, fn)
+	rc.observe(start, redisBroken(err))
+	return err
+}
+
+// isClosed 判断客户端是否已被关闭。
+func (rc *RedisClient) isClosed() bool {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+	return rc.closed
+}
+
+// compareAndExec 在同一连接上监视键，仅在键的值等于 old 时以事务执行命令，返回是否执行成功。
+func (rc *RedisClient) compareAndExec(key, old string, args ...any) (bool, error) {
+	ok := false
+	err := rc.on(key, func(conn *redisConn) error {
+		if _, err := conn.call(rc.timeout, "WATCH", key); err != nil {
+			return err
+		}
+		reply, err := conn.call(rc.timeout, "GET", key)
+		if err != nil || reply == nil || reply.(string) != old {
+			if _, uerr := conn.call(rc.timeout, "UNWATCH"); err == nil {
+				err = uerr
+			}
+			return err
+		}
+		if _, err := conn.call(rc.timeout, "MULTI"); err != nil {
+			return err
+		}
+		if _, err := conn.call(rc.timeout, args...); err != nil {
+			conn.call(rc.timeout, "DISCARD")
+			return err
+		}
+		reply, err = conn.call(rc.timeout, "EXEC")
+		ok = reply != nil
+		return err
+	})
+	return ok, err
+}
+
+// notifying 判断服务端是否开启了监听所需的键空间通知，无法读取配置（如 CONFIG 命令被禁用）时视为未开启。
+// 集群模式下键空间通知仅在键所在的节点上发布，故总是视为未开启。
+func (rc *RedisClient) notifying() bool {
+	if rc.cluster != nil {
+		return false
+	}
+	items, err := redisStrings(rc.Do("CONFIG", "GET", "notify-keyspace-events"))
+	if err != nil || len(items) < 2 {
+		return false
+	}
+	flags := items[1]
+	return strings.Contains(flags, "K") && (strings.Contains(flags, "A") || strings.Contains(flags, "$") && strings.Contains(flags, "g") && strings.Contains(flags, "x"))
+}
+
+// refresh 在收到键空间通知后读取键的最新值，与快照对比后推送变更事件并更新快照；读取失败时忽略，由全量对比弥补。
+func (rc *RedisClient) refresh(w *watcher, snapshot map[string]string, key string) {
+	old, exists := snapshot[key]
+	value, err := rc.Get(key)
+	switch {
+	case err == nil:
+		if !exists || old != value {
+			snapshot[key] = value
+			w.push(Event{Key: key, Old: old, New: value})
+		}
+	case errors.Is(err, ErrNotFound) || errors.Is(err, ErrWrongType):
+		if exists {
+			delete(snapshot, key)
+			w.push(Event{Key: key, Old: old, Deleted: true})
+		}
+	}
+}
+
+// snapshot 列举并读取指定前缀的所有键值。
+func (rc *RedisClient) snapshot(prefix string) (map[string]string, error) {
+	keys, err := rc.Keys(prefix)
+	if err != nil {
+		return nil, err
+	}
+	groups := [][]string{keys}
+	if rc.cluster != nil { // 集群模式下按槽位分组读取
+		slots := make(map[int]int)
+		groups = nil
+		for _, key := range keys {
+			slot := RedisSlot(key)
+			if i, ok := slots[slot]; ok {
+				groups[i] = append(groups[i], key)
+			} else {
+				slots[slot] = len(groups)
+				groups = append(groups, []string{key})
+			}
+		}
+	}
+	rets := make(map[string]string, len(keys))
+	for _, group := range groups {
+		for start := 0; start < len(group); start += redisScanCount {
+			pairs, err := rc.MGet(group[start:min(start+redisScanCount, len(group))]...)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range pairs {
+				rets[key] = value
+			}
+		}
+	}
+	return rets, nil
+}
+
+// get 从连接池中获取连接，连接数量达到上限时等待至超时；连接失败后等待重连期间立即返回 ErrUnavailable。
+func (rc *RedisClient) get() (*redisConn, error) {
+	rc.mutex.RLock()
+	closed := rc.closed
+	rc.mutex.RUnlock()
+	if closed {
+		return nil, ErrClosed
+	}
+	if err := rc.allow(); err != nil {
+		return nil, err
+	}
+	if rc.ping > 0 {
+		rc.checker.Do(func() { go rc.check() })
+	}
+	if rc.slots != nil {
+		timer := time.NewTimer(rc.timeout)
+		defer timer.Stop()
+		select {
+		case rc.slots <- struct{}{}:
+		case <-timer.C:
+			return nil, fmt.Errorf("wait for redis connection of %v timeout", rc.addr)
+		}
+	}
+	for now := time.Now(); ; {
+		var conn *redisConn
+		select {
+		case conn = <-rc.idles:
+		default:
+		}
+		if conn == nil {
+			break
+		}
+		if rc.expired(conn, now) {
+			rc.discard(conn)
+			continue
+		}
+		rc.inuse.Add(1)
+		return conn, nil
+	}
+	conn, err := rc.dial()
+	if err != nil {
+		rc.release()
+		return nil, rc.fail(err)
+	}
+	rc.succeed()
+	rc.opened.Add(1)
+	rc.inuse.Add(1)
+	return conn, nil
+}
+
+// put 归还连接，发生网络或协议错误的连接将被关闭。
+func (rc *RedisClient) put(conn *redisConn, err error) {
+	defer rc.release()
+	rc.inuse.Add(-1)
+	rc.recycle(conn, err)
+}
+
+// recycle 将连接放回空闲队列，发生网络或协议错误的连接将被关闭；
+// 连接被重置等非超时的错误通常意味着服务端重启或故障转移，此时同时关闭所有的空闲连接。
+func (rc *RedisClient) recycle(conn *redisConn, err error) {
+	if redisBroken(err) {
+		rc.discard(conn)
+		var nerr net.Error
+		if !errors.As(err, &nerr) || !nerr.Timeout() {
+			if rc.sentinel != nil {
+				rc.sentinel.invalidate()
+			}
+			rc.flush()
+		}
+		return
+	}
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+	if rc.closed || rc.sentinel != nil && !rc.sentinel.matches(conn.addr) { // 主节点已切换
+		rc.discard(conn)
+		return
+	}
+	conn.used = time.Now()
+	select {
+	case rc.idles <- conn:
+	default:
+		rc.discard(conn)
+	}
+}
+
+// discard 关闭连接池中的连接。
+func (rc *RedisClient) discard(conn *redisConn) {
+	rc.opened.Add(-1)
+	conn.conn.Close()
+}
+
+// flush 关闭所有的空闲连接。
+func (rc *RedisClient) flush() {
+	for {
+		select {
+		case conn := <-rc.idles:
+			rc.discard(conn)
+		default:
+			return
+		}
+	}
+}
+
+// expired 判断空闲的连接是否超过了最长空闲时间或最长存活时间。
+func (rc *RedisClient) expired(conn *redisConn, now time.Time) bool {
+	return rc.idle > 0 && now.Sub(conn.used) > rc.idle || rc.age > 0 && now.Sub(conn.created) > rc.age
+}
+
+// check 以 ping 为间隔检查连接池的健康状态，直至客户端被关闭：
+// 关闭过期的空闲连接，使用 PING 检查空闲超过检查间隔的连接，并在连接失败后到达重试时间时尝试重连以恢复就绪状态。
+func (rc *RedisClient) check() {
+	ticker := time.NewTicker(rc.ping)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rc.done:
+			return
+		case <-ticker.C:
+		}
+		now := time.Now()
+		for range len(rc.idles) {
+			var conn *redisConn
+			select {
+			case conn = <-rc.idles:
+			default:
+			}
+			if conn == nil {
+				break
+			}
+			if rc.expired(conn, now) {
+				rc.discard(conn)
+				continue
+			}
+			var err error
+			if now.Sub(conn.used) >= rc.ping {
+				_, err = conn.call(rc.timeout, "PING")
+			}
+			rc.recycle(conn, err)
+		}
+		if rc.sentinel != nil {
+			rc.follow()
+		}
+		if !rc.Ready() && rc.allow() == nil {
+			rc.Ping()
+		}
+	}
+}
+
+// release 释放连接数量的信号量。
+func (rc *RedisClient) release() {
+	if rc.slots != nil {
+		<-rc.slots
+	}
+}
+
+// dial 建立新的连接，并根据配置进行认证及选择数据库。
+// Sentinel 模式下连接至发现的主节点并校验其角色，集群模式下连接至任意节点（用于订阅连接）。
+func (rc *RedisClient) dial() (*redisConn, error) {
+	addr := rc.addr
+	if rc.sentinel != nil {
+		master, err := rc.sentinel.current(rc.timeout)
+		if err != nil {
+			return nil, err
+		}
+		addr = master
+	} else if rc.cluster != nil {
+		addr = rc.cluster.any()
+	}
+	nconn, err := net.DialTimeout("tcp", addr, rc.timeout)
+	if err != nil {
+		if rc.sentinel != nil {
+			rc.sentinel.invalidate()
+		}
+		return nil, err
+	}
+	now := time.Now()
+	conn := &redisConn{conn: nconn, reader: bufio.NewReader(nconn), writer: bufio.NewWriter(nconn), addr: addr, created: now, used: now}
+	if err := conn.auth(rc.timeout, rc.username, rc.password); err != nil {
+		nconn.Close()
+		return nil, err
+	}
+	if rc.db != 0 {
+		if _, err := conn.call(rc.timeout, "SELECT", rc.db); err != nil {
+			nconn.Close()
+			return nil, err
+		}
+	}
+	if rc.sentinel != nil {
+		if err := rc.verify(conn); err != nil {
+			nconn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// do 在连接上执行命令并读取应答。
+func (conn *redisConn) do(timeout time.Duration, args ...any) (any, error) {
+	conn.conn.SetDeadline(time.Now().Add(timeout))
+	if err := writeRedisCommand(conn.writer, args...); err != nil {
+		return nil, err
+	}
+	if err := conn.writer.Flush(); err != nil {
+		return nil, err
+	}
+	return readRedisReply(conn.reader)
+}
+
+// pipe 在连接上通过一次写入发送多条命令并依次读取应答，服务端的错误应答将作为 RedisError 类型的应答返回。
+func (conn *redisConn) pipe(timeout time.Duration, cmds [][]any) ([]any, error) {
+	conn.conn.SetDeadline(time.Now().Add(timeout))
+	for _, args := range cmds {
+		if err := writeRedisCommand(conn.writer, args...); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.writer.Flush(); err != nil {
+		return nil, err
+	}
+	replies := make([]any, len(cmds))
+	for i := range replies {
+		reply, err := readRedisReply(conn.reader)
+		if err != nil {
+			return nil, err
+		}
+		replies[i] = reply
+	}
+	return replies, nil
+}
+
+// auth 使用 AUTH 命令认证连接，password 为空时不认证，username 非空时使用 AUTH <username> <password>。
+func (conn *redisConn) auth(timeout time.Duration, username, password string) error {
+	if password == "" {
+		return nil
+	}
+	args := []any{"AUTH", password}
+	if username != "" {
+		args = []any{"AUTH", username, password}
+	}
+	_, err := conn.call(timeout, args...)
+	return err
+}
+
+// call 在连接上执行命令，并将服务端的错误应答作为错误返回，错误的包装规则与 redisReplyError 相同。
+func (conn *redisConn) call(timeout time.Duration, args ...any) (any, error) {
+	reply, err := conn.do(timeout, args...)
+	if err != nil {
+		return nil, err
+	}
+	if rerr, ok := reply.(RedisError); ok {
+		return nil, redisReplyError(rerr)
+	}
+	return reply, nil
+}
+
+// redisBroken 判断错误是否为网络或协议等导致连接不可用的错误，服务端的错误应答不属于此类，
+// 但 READONLY 表示连接的节点已被降级为从节点，同样视为连接不可用。
+func redisBroken(err error) bool {
+	var rerr RedisError
+	return err != nil && (!errors.As(err, &rerr) || strings.HasPrefix(string(rerr), "READONLY"))
+}
+
+// redisReplyError 将服务端的错误应答转换为错误，WRONGTYPE 及 CROSSSLOT 错误将分别被包装为 ErrWrongType 及 ErrCrossSlot。
+func redisReplyError(rerr RedisError) error {
+	if strings.HasPrefix(string(rerr), "WRONGTYPE") {
+		return fmt.Errorf("%w: %w", ErrWrongType, rerr)
+	}
+	if strings.HasPrefix(string(rerr), "CROSSSLOT") {
+		return fmt.Errorf("%w: %w", ErrCrossSlot, rerr)
+	}
+	return rerr
+}
+
+// writeRedisCommand 将命令编码为 RESP 的批量字符串数组。
+func writeRedisCommand(writer *bufio.Writer, args ...any) error {
+	writer.WriteString("*")
+	writer.WriteString(strconv.Itoa(len(args)))
+	writer.WriteString("\r\n")
+	for _, arg := range args {
+		var str string
+		switch v := arg.(type) {
+		case string:
+			str = v
+		case []byte:
+			str = string(v)
+		case int:
+			str = strconv.Itoa(v)
+		case int64:
+			str = strconv.FormatInt(v, 10)
+		case float64:
+			str = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			if v {
+				str = "1"
+			} else {
+				str = "0"
+			}
+		case nil:
+			str = ""
+		default:
+			str = fmt.Sprint(v)
+		}
+		writer.WriteString("$")
+		writer.WriteString(strconv.Itoa(len(str)))
+		writer.WriteString("\r\n")
+		writer.WriteString(str)
+		if _, err := writer.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readRedisReply 读取并解析 RESP 应答。
+func readRedisReply(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("invalid redis reply: %q", line)
+	}
+	body := line[1 : len(line)-2]
+	switch line[0] {
+	case '+':
+		return body, nil
+	case '-':
+		return RedisError(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis bulk length: %q", body)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis array length: %q", body)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		rets := make([]any, size)
+		for i := range rets {
+			if rets[i], err = readRedisReply(reader); err != nil {
+				return nil, err
+			}
+		}
+		return rets, nil
+	}
+	return nil, fmt.Errorf("invalid redis reply: %q", line)
+}
+
+// redisArgs 拼接命令及字符串参数。
+func redisArgs(cmd string, keys []string) []any {
+	args := make([]any, 0, len(keys)+1)
+	args = append(args, cmd)
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return args
+}
+
+// redisGlobEscape 转义 Redis 匹配模式中的特殊字符。
+func redisGlobEscape(str string) string {
+	var builder strings.Builder
+	for _, c := range str {
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(c)
+	}
+	return builder.String()
+}
+
+// redisString 将应答转换为字符串。
+func redisString(reply any) (string, error) {
+	if str, ok := reply.(string); ok {
+		return str, nil
+	}
+	return "", fmt.Errorf("unexpected reply type %T", reply)
+}
+
+// redisInt 将应答转换为整数。
+func redisInt(reply any, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	if val, ok := reply.(int64); ok {
+		return int(val), nil
+	}
+	return 0, fmt.Errorf("unexpected reply type %T", reply)
+}
@@ -0,0 +1,412 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eframework-org/GO.UTIL/XLog"
+)
+
+const (
+	// RedisClusterSlots 是 Redis 集群的槽位数量。
+	RedisClusterSlots = 16384
+
+	// RedisClusterRedirects 是集群模式下单次请求跟随 MOVED 或 ASK 重定向的最大次数。
+	RedisClusterRedirects = 5
+
+	// RedisClusterRefresh 是集群模式下两次刷新槽位映射的最小间隔，用于避免故障期间频繁地刷新。
+	RedisClusterRefresh = time.Second
+)
+
+// RedisSlot 返回键在 Redis 集群中的槽位，键包含非空的哈希标签（如 {user:1}:bag）时仅使用标签计算。
+func RedisSlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	crc := uint16(0)
+	for i := 0; i < len(key); i++ {
+		crc ^= uint16(key[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return int(crc) % RedisClusterSlots
+}
+
+// RedisHashTag 返回以 tag 为哈希标签的键名，格式为 {tag}:key，使用相同标签的键位于同一槽位，可以在集群中执行多键操作。
+func RedisHashTag(tag, key string) string {
+	return "{" + tag + "}:" + key
+}
+
+// RedisSameSlot 判断所有的键是否位于同一槽位。
+func RedisSameSlot(keys ...string) bool {
+	for _, key := range keys[min(1, len(keys)):] {
+		if RedisSlot(key) != RedisSlot(keys[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// redisCluster 是集群模式的拓扑，维护槽位至主节点的映射，每个节点使用独立的客户端及连接池。
+type redisCluster struct {
+	rc        *RedisClient
+	seeds     []string                        // 初始的节点地址
+	slots     [RedisClusterSlots]*RedisClient // 槽位所属的主节点
+	owners    map[*RedisClient]bool           // 拥有槽位的主节点
+	nodes     map[string]*RedisClient         // 节点的地址及其客户端
+	refreshed time.Time                       // 最近一次刷新的时间
+	interval  time.Duration                   // 两次刷新的最小间隔
+	mutex     sync.RWMutex
+}
+
+// newRedisCluster 创建集群的拓扑，槽位映射将在首次请求时加载。
+func newRedisCluster(rc *RedisClient, seeds []string) *redisCluster {
+	return &redisCluster{rc: rc, seeds: seeds, owners: make(map[*RedisClient]bool), nodes: make(map[string]*RedisClient), interval: RedisClusterRefresh}
+}
+
+// on 在 key 所属的主节点上执行 fn，key 为空时在任意节点上执行。
+// fn 返回 MOVED 时更新槽位映射并在新节点上重试，返回 ASK 时在目标节点上发送 ASKING 后重试，最多重定向 RedisClusterRedirects 次；
+// 发生网络错误时刷新槽位映射，以便后续的请求路由至故障转移后的主节点。
+func (c *redisCluster) on(key string, fn func(conn *redisConn) error) error {
+	node, err := c.route(key)
+	if err != nil {
+		return err
+	}
+	asking := false
+	for i := 0; ; i++ {
+		err = node.with(func(conn *redisConn) error {
+			if asking {
+				if _, err := conn.call(c.rc.timeout, "ASKING"); err != nil {
+					return err
+				}
+			}
+			return fn(conn)
+		})
+		kind, slot, addr := redisRedirect(err)
+		if kind == "" && errors.Is(err, ErrClosed) && !c.rc.isClosed() && i < RedisClusterRedirects {
+			// 节点在刷新槽位映射时被关闭，重新路由
+			if node, err = c.route(key); err != nil {
+				return err
+			}
+			asking = false
+			continue
+		}
+		if kind == "" {
+			if redisBroken(err) && !errors.Is(err, ErrClosed) {
+				c.refresh(false)
+			}
+			return err
+		}
+		if i >= RedisClusterRedirects {
+			return err
+		}
+		if node = c.node(addr); kind == "MOVED" {
+			c.mutex.Lock()
+			c.slots[slot] = node
+			c.owners[node] = true
+			c.mutex.Unlock()
+			c.refresh(false)
+		}
+		asking = kind == "ASK"
+	}
+}
+
+// route 返回 key 所属的主节点，key 为空时返回任意就绪的节点，槽位映射未加载时先加载。
+func (c *redisCluster) route(key string) (*RedisClient, error) {
+	c.mutex.RLock()
+	loaded := !c.refreshed.IsZero()
+	c.mutex.RUnlock()
+	if !loaded {
+		if err := c.refresh(true); err != nil {
+			return nil, err
+		}
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if key != "" {
+		if node := c.slots[RedisSlot(key)]; node != nil {
+			return node, nil
+		}
+		return nil, fmt.Errorf("%w: slot %v of key %v is not served", ErrUnavailable, RedisSlot(key), key)
+	}
+	var fallback *RedisClient
+	for node := range c.owners {
+		if node.Ready() {
+			return node, nil
+		}
+		fallback = node
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("%w: no node of cluster %v", ErrUnavailable, c.rc.addr)
+	}
+	return fallback, nil
+}
+
+// node 返回指定地址的节点客户端，不存在时创建。
+func (c *redisCluster) node(addr string) *RedisClient {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.nodeLocked(addr)
+}
+
+// nodeLocked 与 node 相同，调用者需持有锁。
+func (c *redisCluster) nodeLocked(addr string) *RedisClient {
+	node := c.nodes[addr]
+	if node == nil {
+		node = c.rc.clone(addr)
+		c.nodes[addr] = node
+	}
+	return node
+}
+
+// masters 返回所有拥有槽位的主节点。
+func (c *redisCluster) masters() ([]*RedisClient, error) {
+	if _, err := c.route(""); err != nil {
+		return nil, err
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	rets := make([]*RedisClient, 0, len(c.owners))
+	for node := range c.owners {
+		rets = append(rets, node)
+	}
+	sort.Slice(rets, func(i, j int) bool { return rets[i].addr < rets[j].addr })
+	return rets, nil
+}
+
+// refresh 使用 CLUSTER SLOTS 命令重新加载槽位映射，依次询问已知的节点及初始的节点，返回第一个有效的应答。
+// force 为 false 时距离上次刷新不足 RedisClusterRefresh 则跳过；不再拥有槽位的节点将被关闭。
+func (c *redisCluster) refresh(force bool) error {
+	c.mutex.Lock()
+	if !force && time.Since(c.refreshed) < c.interval {
+		c.mutex.Unlock()
+		return nil
+	}
+	c.refreshed = time.Now()
+	candidates := make([]string, 0, len(c.nodes)+len(c.seeds))
+	for addr := range c.nodes {
+		candidates = append(candidates, addr)
+	}
+	sort.Strings(candidates)
+	candidates = append(candidates, c.seeds...)
+	c.mutex.Unlock()
+
+	var errs []string
+	for _, addr := range candidates {
+		ranges, err := c.query(addr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", addr, err))
+			continue
+		}
+		c.apply(ranges)
+		return nil
+	}
+	err := fmt.Errorf("%w: load slots of cluster %v failed: %v", ErrUnavailable, c.rc.addr, strings.Join(errs, "; "))
+	if force {
+		c.mutex.Lock()
+		c.refreshed = time.Time{} // 首次加载失败时允许下次请求立即重试
+		c.mutex.Unlock()
+	}
+	XLog.Warn("XPairs.Cluster: %v", err)
+	return err
+}
+
+// redisSlotRange 是 CLUSTER SLOTS 应答中的槽位区间及其主节点的地址。
+type redisSlotRange struct {
+	start, end int
+	addr       string
+}
+
+// query 向单个节点查询槽位映射，主节点的主机名为空时使用被询问节点的主机名。
+func (c *redisCluster) query(addr string) ([]redisSlotRange, error) {
+	reply, err := c.node(addr).Do("CLUSTER", "SLOTS")
+	if err != nil {
+		return nil, err
+	}
+	items, _ := reply.([]any)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("empty slots")
+	}
+	host, _, _ := net.SplitHostPort(addr)
+	ranges := make([]redisSlotRange, 0, len(items))
+	for _, item := range items {
+		fields, _ := item.([]any)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("unexpected reply of CLUSTER SLOTS: %v", item)
+		}
+		start, _ := fields[0].(int64)
+		end, _ := fields[1].(int64)
+		master, _ := fields[2].([]any)
+		if len(master) < 2 || start < 0 || end >= RedisClusterSlots || start > end {
+			return nil, fmt.Errorf("unexpected reply of CLUSTER SLOTS: %v", item)
+		}
+		mhost, _ := master[0].(string)
+		mport, _ := master[1].(int64)
+		if mhost == "" {
+			mhost = host
+		}
+		ranges = append(ranges, redisSlotRange{start: int(start), end: int(end), addr: net.JoinHostPort(mhost, strconv.FormatInt(mport, 10))})
+	}
+	return ranges, nil
+}
+
+// apply 应用槽位映射，并关闭不再拥有槽位的节点。
+func (c *redisCluster) apply(ranges []redisSlotRange) {
+	c.mutex.Lock()
+	var slots [RedisClusterSlots]*RedisClient
+	owners := make(map[*RedisClient]bool)
+	for _, r := range ranges {
+		node := c.nodeLocked(r.addr)
+		owners[node] = true
+		for slot := r.start; slot <= r.end; slot++ {
+			slots[slot] = node
+		}
+	}
+	c.slots, c.owners = slots, owners
+	var stale []*RedisClient
+	for addr, node := range c.nodes {
+		if !owners[node] {
+			delete(c.nodes, addr)
+			stale = append(stale, node)
+		}
+	}
+	c.mutex.Unlock()
+	for _, node := range stale {
+		node.Close()
+	}
+}
+
+// any 返回任意就绪的节点地址，用于订阅连接；槽位映射未加载时返回初始的节点地址。
+func (c *redisCluster) any() string {
+	if node, err := c.route(""); err == nil {
+		return node.addr
+	}
+	return c.seeds[0]
+}
+
+// keys 在所有的主节点上列举指定前缀的键名，结果按字典序排列。
+func (c *redisCluster) keys(prefix string) ([]string, error) {
+	masters, err := c.masters()
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, node := range masters {
+		nkeys, err := node.Keys(prefix)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, nkeys...)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ready 判断所有的主节点是否就绪，槽位映射未加载时视为就绪。
+func (c *redisCluster) ready() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	for node := range c.owners {
+		if !node.Ready() {
+			return false
+		}
+	}
+	return true
+}
+
+// stats 返回所有节点的连接池统计信息之和。
+func (c *redisCluster) stats() PoolStats {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	var stats PoolStats
+	for _, node := range c.nodes {
+		nstats := node.Stats()
+		stats.Size += nstats.Size
+		stats.InUse += nstats.InUse
+		stats.Idle += nstats.Idle
+	}
+	return stats
+}
+
+// close 关闭所有的节点。
+func (c *redisCluster) close() {
+	c.mutex.Lock()
+	nodes := c.nodes
+	c.nodes = make(map[string]*RedisClient)
+	c.mutex.Unlock()
+	for _, node := range nodes {
+		node.Close()
+	}
+}
+
+// redisRedirect 解析 MOVED 或 ASK 重定向的错误应答，返回重定向的类型、槽位及目标地址，非重定向时类型为空。
+func redisRedirect(err error) (string, int, string) {
+	var rerr RedisError
+	if !errors.As(err, &rerr) {
+		return "", 0, ""
+	}
+	fields := strings.Fields(string(rerr))
+	if len(fields) != 3 || fields[0] != "MOVED" && fields[0] != "ASK" {
+		return "", 0, ""
+	}
+	slot, serr := strconv.Atoi(fields[1])
+	if serr != nil || slot < 0 || slot >= RedisClusterSlots {
+		return "", 0, ""
+	}
+	return fields[0], slot, fields[2]
+}
+
+// redisKeyless 是不包含键的命令，集群模式下在任意节点上执行。
+var redisKeyless = map[string]bool{
+	"PING": true, "ECHO": true, "INFO": true, "CONFIG": true, "PUBLISH": true, "SCAN": true, "KEYS": true,
+	"CLUSTER": true, "ROLE": true, "SCRIPT": true, "DBSIZE": true, "TIME": true, "CLIENT": true, "COMMAND": true,
+	"FLUSHDB": true, "FLUSHALL": true, "MULTI": true, "EXEC": true, "DISCARD": true, "UNWATCH": true, "RANDOMKEY": true,
+}
+
+// redisCommandKey 返回命令的第一个键名，用于集群模式下的路由，命令不包含键时返回空字符串。
+func redisCommandKey(args []any) string {
+	if len(args) < 2 {
+		return ""
+	}
+	cmd := strings.ToUpper(fmt.Sprint(args[0]))
+	switch {
+	case redisKeyless[cmd]:
+		return ""
+	case cmd == "EVAL" || cmd == "EVALSHA" || cmd == "EVAL_RO" || cmd == "EVALSHA_RO" || cmd == "FCALL":
+		if len(args) < 4 || fmt.Sprint(args[2]) == "0" {
+			return ""
+		}
+		return redisArgString(args[3])
+	case cmd == "OBJECT" || cmd == "MEMORY" || cmd == "BITOP" || cmd == "XGROUP":
+		if len(args) < 3 {
+			return ""
+		}
+		return redisArgString(args[2])
+	}
+	return redisArgString(args[1])
+}
+
+// redisArgString 将命令的参数转换为字符串。
+func redisArgString(arg any) string {
+	if bytes, ok := arg.([]byte); ok {
+		return string(bytes)
+	}
+	return fmt.Sprint(arg)
+}
@@ -0,0 +1,420 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRedisCluster 是由多个测试服务端组成的模拟集群，记录槽位所属的节点及正在迁移的槽位。
+type testRedisCluster struct {
+	servers []*testRedisServer
+	owners  [RedisClusterSlots]*testRedisServer
+	asks    map[int]*testRedisServer // 正在迁移的槽位及其目标节点
+	mutex   sync.Mutex
+}
+
+// newTestRedisCluster 启动由 n 个测试服务端组成的模拟集群，槽位平均分配至各节点。
+func newTestRedisCluster(t *testing.T, n int) *testRedisCluster {
+	cluster := &testRedisCluster{asks: make(map[int]*testRedisServer)}
+	for i := 0; i < n; i++ {
+		server := newTestRedisServer(t)
+		server.cluster = cluster
+		cluster.servers = append(cluster.servers, server)
+	}
+	for slot := range cluster.owners {
+		cluster.owners[slot] = cluster.servers[slot*n/RedisClusterSlots]
+	}
+	return cluster
+}
+
+// Addr 返回模拟集群的地址。
+func (c *testRedisCluster) Addr() string {
+	addrs := make([]string, len(c.servers))
+	for i, server := range c.servers {
+		addrs[i] = server.Addr()
+	}
+	return "redis+cluster://" + strings.Join(addrs, ",")
+}
+
+// owner 返回键所属的节点。
+func (c *testRedisCluster) owner(key string) *testRedisServer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.owners[RedisSlot(key)]
+}
+
+// locked 持有锁执行 fn，用于在测试中修改集群的拓扑。
+func (c *testRedisCluster) locked(fn func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	fn()
+}
+
+// slots 返回 CLUSTER SLOTS 的应答，相邻且属于同一节点的槽位合并为一个区间。
+func (c *testRedisCluster) slots() []any {
+	rets := []any{}
+	for start := 0; start < RedisClusterSlots; {
+		end := start
+		for end+1 < RedisClusterSlots && c.owners[end+1] == c.owners[start] {
+			end++
+		}
+		if owner := c.owners[start]; owner != nil {
+			_, port, _ := net.SplitHostPort(owner.Addr())
+			nport, _ := strconv.Atoi(port)
+			rets = append(rets, []any{start, end, []any{"127.0.0.1", nport, fmt.Sprintf("node-%v", nport)}})
+		}
+		start = end + 1
+	}
+	return rets
+}
+
+// testRedisClusterKeys 返回测试服务端支持的命令中的所有键名。
+func testRedisClusterKeys(cmd string, args []string) []string {
+	switch cmd {
+	case "PING", "CONFIG", "SCAN", "CLUSTER", "ROLE", "SENTINEL", "MULTI", "EXEC", "DISCARD", "UNWATCH", "PUBLISH", "PSUBSCRIBE", "PUNSUBSCRIBE", "SELECT":
+		return nil
+	case "MGET", "DEL", "EXISTS", "WATCH":
+		return args
+	case "RENAME":
+		return args[:min(2, len(args))]
+	case "MSET":
+		var keys []string
+		for i := 0; i < len(args); i += 2 {
+			keys = append(keys, args[i])
+		}
+		return keys
+	case "EVAL", "EVALSHA":
+		count, _ := strconv.Atoi(args[1])
+		return args[2 : 2+count]
+	}
+	return args[:min(1, len(args))]
+}
+
+// clusterExec 模拟集群的命令及重定向：CLUSTER SLOTS 返回槽位映射，多个键不在同一槽位时返回 CROSSSLOT，
+// 键不属于当前节点时返回 MOVED，槽位正在迁移且键不存在时返回 ASK，目标节点在收到 ASKING 后接受下一条命令，返回 nil 时继续执行命令。
+func (s *testRedisServer) clusterExec(session *testRedisSession, cmd string, args []string) any {
+	if s.cluster == nil {
+		return nil
+	}
+	c := s.cluster
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	switch cmd {
+	case "CLUSTER":
+		if len(args) == 1 && strings.ToUpper(args[0]) == "SLOTS" {
+			return c.slots()
+		}
+		return RedisError("ERR unsupported cluster command")
+	case "ASKING":
+		session.asking = true
+		return testRedisStatus("OK")
+	}
+	asking := session.asking
+	session.asking = false
+	keys := testRedisClusterKeys(cmd, args)
+	if len(keys) == 0 {
+		return nil
+	}
+	if !RedisSameSlot(keys...) {
+		return RedisError("CROSSSLOT Keys in request don't hash to the same slot")
+	}
+	slot := RedisSlot(keys[0])
+	if owner := c.owners[slot]; owner != s {
+		if asking && c.asks[slot] == s {
+			return nil
+		}
+		return RedisError(fmt.Sprintf("MOVED %v %v", slot, owner.Addr()))
+	}
+	if target := c.asks[slot]; target != nil {
+		for _, key := range keys {
+			if s.load(key) == nil {
+				return RedisError(fmt.Sprintf("ASK %v %v", slot, target.Addr()))
+			}
+		}
+	}
+	return nil
+}
+
+func TestRedisCluster(t *testing.T) {
+	t.Run("Slot", func(t *testing.T) {
+		tests := []struct {
+			key  string
+			slot int
+		}{
+			{"", 0},
+			{"123456789", 12739},
+			{"foo", 12182},
+			{"bar", 5061},
+			{"{foo}:bag", 12182},
+			{"user:{foo}", 12182},
+			{"{}foo", RedisSlot("{}foo")},
+			{"foo{}{bar}", RedisSlot("foo{}{bar}")},
+			{"{bar", RedisSlot("{bar")},
+		}
+		for _, test := range tests {
+			assert.Equal(t, test.slot, RedisSlot(test.key), "键的槽位应当和预期相等：%v", test.key)
+		}
+		assert.NotEqual(t, RedisSlot("{}foo"), RedisSlot("foo"), "空的哈希标签应当使用完整的键名计算。")
+		assert.NotEqual(t, RedisSlot("foo{}{bar}"), RedisSlot("bar"), "仅第一对花括号可以作为哈希标签。")
+
+		assert.Equal(t, "{user:1}:bag", RedisHashTag("user:1", "bag"), "哈希标签的键名应当和预期相等。")
+		assert.True(t, RedisSameSlot(RedisHashTag("user:1", "bag"), RedisHashTag("user:1", "mail")), "相同标签的键应当位于同一槽位。")
+		assert.True(t, RedisSameSlot(), "空的键应当视为同一槽位。")
+		assert.False(t, RedisSameSlot("foo", "bar"), "不同槽位的键应当返回 false。")
+
+		for _, test := range []struct {
+			args []any
+			key  string
+		}{
+			{[]any{"GET", "k"}, "k"},
+			{[]any{"set", []byte("k"), "v"}, "k"},
+			{[]any{"PING"}, ""},
+			{[]any{"SCAN", "0", "MATCH", "k*"}, ""},
+			{[]any{"EVAL", "src", 0}, ""},
+			{[]any{"EVALSHA", "sha", 1, "k", "arg"}, "k"},
+			{[]any{"OBJECT", "ENCODING", "k"}, "k"},
+		} {
+			assert.Equal(t, test.key, redisCommandKey(test.args), "命令的键名应当和预期相等：%v", test.args)
+		}
+		for _, test := range []struct {
+			err  error
+			kind string
+			slot int
+			addr string
+		}{
+			{RedisError("MOVED 3999 127.0.0.1:6381"), "MOVED", 3999, "127.0.0.1:6381"},
+			{RedisError("ASK 12182 127.0.0.1:6382"), "ASK", 12182, "127.0.0.1:6382"},
+			{RedisError("MOVED 99999 127.0.0.1:6381"), "", 0, ""},
+			{RedisError("ERR unknown command"), "", 0, ""},
+			{ErrClosed, "", 0, ""},
+		} {
+			kind, slot, addr := redisRedirect(test.err)
+			assert.Equal(t, test.kind, kind, "重定向的类型应当和预期相等：%v", test.err)
+			assert.Equal(t, test.slot, slot, "重定向的槽位应当和预期相等：%v", test.err)
+			assert.Equal(t, test.addr, addr, "重定向的地址应当和预期相等：%v", test.err)
+		}
+	})
+
+	t.Run("Addr", func(t *testing.T) {
+		tests := []struct {
+			addr  string
+			valid bool
+		}{
+			{"redis+cluster://127.0.0.1:7000,127.0.0.1:7001", true},
+			{"redis+cluster://:pw@127.0.0.1:7000/0?ping=10s", true},
+			{"redis+cluster://127.0.0.1:7000/1", false},
+			{"redis+cluster://127.0.0.1:7000,127.0.0.1", false},
+			{"redis://127.0.0.1:7000,127.0.0.1:7001", false},
+			{"rediss://127.0.0.1:7000", false},
+		}
+		for _, test := range tests {
+			rc, err := NewRedis(test.addr, 1, 1)
+			if !test.valid {
+				assert.Error(t, err, "无效的地址应当返回错误：%v", test.addr)
+				continue
+			}
+			assert.NoError(t, err, "有效的地址不应当返回错误：%v", test.addr)
+			assert.NotNil(t, rc.cluster, "集群的拓扑不应当为空：%v", test.addr)
+		}
+	})
+
+	t.Run("Routing", func(t *testing.T) {
+		cluster := newTestRedisCluster(t, 3)
+		rc, err := NewRedis(cluster.Addr(), 2, 4)
+		assert.NoError(t, err, "NewRedis 不应当返回错误。")
+		defer rc.Close()
+
+		var keys []string
+		for i := 0; i < 12; i++ {
+			key := fmt.Sprintf("route/%v", i)
+			keys = append(keys, key)
+			assert.NoError(t, rc.Set(key, strconv.Itoa(i), 0), "Set 不应当返回错误：%v", key)
+		}
+		stored := make(map[*testRedisServer]bool)
+		for i, key := range keys {
+			owner := cluster.owner(key)
+			stored[owner] = true
+			owner.locked(func() {
+				assert.Equal(t, strconv.Itoa(i), owner.data[key].value, "键应当写入所属的节点：%v", key)
+			})
+			value, err := rc.Get(key)
+			assert.NoError(t, err, "Get 不应当返回错误：%v", key)
+			assert.Equal(t, strconv.Itoa(i), value, "读取的值应当和预期相等：%v", key)
+		}
+		assert.Len(t, stored, 3, "键应当分布在所有的节点上。")
+
+		founds, err := rc.Keys("route/")
+		assert.NoError(t, err, "Keys 不应当返回错误。")
+		assert.ElementsMatch(t, keys, founds, "Keys 应当合并所有节点的结果。")
+		assert.NoError(t, rc.Ping(), "不包含键的命令应当在任意节点上执行。")
+
+		_, err = rc.MGet(keys...)
+		assert.ErrorIs(t, err, ErrCrossSlot, "跨槽位的多键命令应当返回 ErrCrossSlot。")
+		pipe := rc.Pipeline()
+		pipe.Get(keys[0])
+		pipe.Get(keys[1])
+		assert.ErrorIs(t, pipe.Exec(), ErrCrossSlot, "跨槽位的管道应当返回 ErrCrossSlot。")
+
+		bag, mail := RedisHashTag("user:1", "bag"), RedisHashTag("user:1", "mail")
+		pipe.Set(bag, "1", 0)
+		pipe.Set(mail, "2", 0)
+		assert.NoError(t, pipe.Exec(), "同一槽位的管道不应当返回错误。")
+		pairs, err := rc.MGet(bag, mail)
+		assert.NoError(t, err, "同一槽位的 MGet 不应当返回错误。")
+		assert.Equal(t, map[string]string{bag: "1", mail: "2"}, pairs, "MGet 的结果应当和预期相等。")
+		err = rc.Transaction(func(tx *RedisTx) error {
+			tx.IncrBy(bag, 1)
+			tx.IncrBy(mail, 1)
+			return nil
+		}, bag, mail)
+		assert.NoError(t, err, "同一槽位的事务不应当返回错误。")
+		pairs, _ = rc.MGet(bag, mail)
+		assert.Equal(t, map[string]string{bag: "2", mail: "3"}, pairs, "事务应当在键所属的节点上执行。")
+		assert.NoError(t, rc.Rename(bag, RedisHashTag("user:1", "old")), "同一槽位的 Rename 不应当返回错误。")
+
+		assert.True(t, rc.Ready(), "所有的主节点可达时应当就绪。")
+		stats := rc.Stats()
+		assert.GreaterOrEqual(t, stats.Size, 3, "连接池的统计信息应当为所有节点之和。")
+		assert.Equal(t, 0, stats.InUse, "请求完成后使用中的连接数量应当为 0。")
+	})
+
+	t.Run("Moved", func(t *testing.T) {
+		cluster := newTestRedisCluster(t, 3)
+		rc, _ := NewRedis(cluster.Addr(), 2, 4)
+		defer rc.Close()
+		rc.cluster.interval = 0
+
+		key := "moved"
+		assert.NoError(t, rc.Set(key, "v1", 0), "Set 不应当返回错误。")
+		slot, src := RedisSlot(key), cluster.owner(key)
+		dst := cluster.servers[0]
+		if dst == src {
+			dst = cluster.servers[1]
+		}
+
+		// 迁移槽位，客户端的槽位映射已过期
+		src.locked(func() { delete(src.data, key) })
+		dst.locked(func() { dst.data[key] = &testRedisEntry{value: "v1"} })
+		cluster.locked(func() { cluster.owners[slot] = dst })
+		value, err := rc.Get(key)
+		assert.NoError(t, err, "收到 MOVED 后应当在新节点上重试。")
+		assert.Equal(t, "v1", value, "读取的值应当和预期相等。")
+		rc.cluster.mutex.RLock()
+		assert.Equal(t, dst.Addr(), rc.cluster.slots[slot].addr, "收到 MOVED 后应当更新槽位映射。")
+		rc.cluster.mutex.RUnlock()
+
+		pipe := rc.Pipeline()
+		result := pipe.Get(key)
+		cluster.locked(func() { cluster.owners[slot] = src })
+		src.locked(func() { src.data[key] = &testRedisEntry{value: "v2"} })
+		assert.NoError(t, pipe.Exec(), "管道收到 MOVED 后应当在新节点上重试。")
+		value, _ = result.Text()
+		assert.Equal(t, "v2", value, "管道的结果应当来自新的节点。")
+	})
+
+	t.Run("Ask", func(t *testing.T) {
+		cluster := newTestRedisCluster(t, 3)
+		rc, _ := NewRedis(cluster.Addr(), 2, 4)
+		defer rc.Close()
+
+		key := "ask"
+		assert.NoError(t, rc.Ping(), "Ping 不应当返回错误。")
+		slot, src := RedisSlot(key), cluster.owner(key)
+		dst := cluster.servers[0]
+		if dst == src {
+			dst = cluster.servers[1]
+		}
+
+		// 槽位正在迁移，键已被迁移至目标节点
+		dst.locked(func() { dst.data[key] = &testRedisEntry{value: "v1"} })
+		cluster.locked(func() { cluster.asks[slot] = dst })
+		value, err := rc.Get(key)
+		assert.NoError(t, err, "收到 ASK 后应当在目标节点上重试。")
+		assert.Equal(t, "v1", value, "读取的值应当来自目标节点。")
+		dst.locked(func() {
+			assert.Contains(t, dst.commands, "ASKING", "在目标节点上重试之前应当发送 ASKING。")
+		})
+		rc.cluster.mutex.RLock()
+		assert.Equal(t, src.Addr(), rc.cluster.slots[slot].addr, "收到 ASK 后不应当更新槽位映射。")
+		rc.cluster.mutex.RUnlock()
+
+		cluster.locked(func() { delete(cluster.asks, slot) })
+		_, err = rc.Get(key)
+		assert.ErrorIs(t, err, ErrNotFound, "迁移中止后应当读取源节点。")
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		cluster := newTestRedisCluster(t, 3)
+		rc, _ := NewRedis(cluster.Addr(), 2, 4)
+		defer rc.Close()
+		rc.cluster.interval = 10 * time.Millisecond
+
+		key := "failover"
+		assert.NoError(t, rc.Set(key, "v1", 0), "Set 不应当返回错误。")
+		failed := cluster.owner(key)
+		backup := cluster.servers[0]
+		if backup == failed {
+			backup = cluster.servers[1]
+		}
+
+		// 主节点故障，其槽位被转移至其他节点
+		failed.down()
+		cluster.locked(func() {
+			for slot, owner := range cluster.owners {
+				if owner == failed {
+					cluster.owners[slot] = backup
+				}
+			}
+		})
+		assert.Error(t, rc.Set(key, "v2", 0), "主节点故障时 Set 应当返回错误。")
+		assert.Eventually(t, func() bool { return rc.Set(key, "v2", 0) == nil }, time.Second, 20*time.Millisecond, "刷新槽位映射后应当路由至新的主节点。")
+		backup.locked(func() { assert.Equal(t, "v2", backup.data[key].value, "写入应当发送至新的主节点。") })
+		assert.True(t, rc.Ready(), "故障的节点被移除后应当就绪。")
+		rc.cluster.mutex.RLock()
+		assert.NotContains(t, rc.cluster.nodes, failed.Addr(), "不再拥有槽位的节点应当被关闭。")
+		rc.cluster.mutex.RUnlock()
+
+		// 槽位无节点提供服务
+		cluster.locked(func() {
+			for slot, owner := range cluster.owners {
+				if owner == backup {
+					cluster.owners[slot] = nil
+				}
+			}
+		})
+		rc.cluster.refresh(true)
+		_, err := rc.Get(key)
+		assert.ErrorIs(t, err, ErrUnavailable, "槽位无节点提供服务时应当返回 ErrUnavailable。")
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		cluster := newTestRedisCluster(t, 3)
+		rc, _ := NewRedis(cluster.Addr(), 2, 4)
+		defer rc.Close()
+		rc.interval = 20 * time.Millisecond
+		rc.Set("watch/a", "1", 0)
+
+		events := make(chan Event, 16)
+		stop, err := rc.Watch("watch/", func(event Event) { events <- event })
+		assert.NoError(t, err, "Watch 不应当返回错误。")
+		defer stop()
+
+		rc.Set("watch/a", "2", 0)
+		assert.Equal(t, Event{Key: "watch/a", Old: "1", New: "2"}, <-events, "修改的事件应当和预期相等。")
+		rc.Set("watch/b", "1", 0)
+		assert.Equal(t, Event{Key: "watch/b", New: "1"}, <-events, "新建的事件应当和预期相等。")
+		rc.Delete("watch/a")
+		assert.Equal(t, Event{Key: "watch/a", Old: "2", Deleted: true}, <-events, "删除的事件应当和预期相等。")
+	})
+}
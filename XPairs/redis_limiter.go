@@ -0,0 +1,126 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	// redisTokenBucketScript 是令牌桶的脚本，令牌桶的状态存储于哈希表的 tokens（剩余的令牌数量）及 time（上次补充的毫秒时间戳）字段中。
+	// KEYS[1] 为令牌桶的键，ARGV 依次为每秒补充的令牌数量、桶的容量、取出的令牌数量及当前的毫秒时间戳；
+	// 返回是否允许、剩余的令牌数量及令牌足够的等待毫秒数（永远无法满足时为 -1）。
+	redisTokenBucketScript = NewRedisScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'time')
+local tokens = tonumber(state[1])
+local last = tonumber(state[2])
+if tokens == nil or last == nil then
+	tokens = burst
+	last = now
+end
+if now > last then
+	tokens = math.min(burst, tokens + (now - last) * rate / 1000)
+	last = now
+end
+local allowed = 0
+local retry = 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+elseif n > burst then
+	retry = -1
+else
+	retry = math.ceil((n - tokens) * 1000 / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'time', last)
+redis.call('PEXPIRE', KEYS[1], math.max(math.ceil((burst - tokens) * 1000 / rate), 1))
+return {allowed, math.floor(tokens), retry}
+`)
+
+	// redisSlidingWindowScript 是滑动窗口的脚本，窗口内的请求以毫秒时间戳为分数存储于有序集合中。
+	// KEYS[1] 为滑动窗口的键，ARGV 依次为窗口内允许的请求数量、窗口的毫秒数、请求的数量、当前的毫秒时间戳及请求的随机标识；
+	// 返回是否允许、剩余的请求数量及配额足够的等待毫秒数（永远无法满足时为 -1）。
+	redisSlidingWindowScript = NewRedisScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count + n <= limit then
+	for i = 1, n do
+		redis.call('ZADD', KEYS[1], now, ARGV[5] .. ':' .. i)
+	end
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, limit - count - n, 0}
+end
+if n > limit then
+	return {0, math.max(limit - count, 0), -1}
+end
+local index = count + n - limit - 1
+local oldest = redis.call('ZRANGE', KEYS[1], index, index, 'WITHSCORES')
+return {0, math.max(limit - count, 0), tonumber(oldest[2]) + window - now}
+`)
+
+	// redisIncrExpireScript 是增加计数并设置过期时间的脚本。
+	// KEYS[1] 为计数的键，ARGV 依次为增量及过期的毫秒数，返回增加后的值。
+	redisIncrExpireScript = NewRedisScript(`
+local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+if redis.call('PTTL', KEYS[1]) == -1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return value
+`)
+)
+
+// TakeToken 使用 Lua 脚本从键对应的令牌桶中原子地取出 n 个令牌，桶的容量为 burst，每秒补充 rate 个令牌，令牌不足时不取出。
+func (rc *RedisClient) TakeToken(key string, rate float64, burst, n int, now time.Time) (LimitResult, error) {
+	return redisLimitResult(redisTokenBucketScript.Run(rc, []string{key}, rate, burst, n, now.UnixMilli()))
+}
+
+// TakeWindow 使用 Lua 脚本在键对应的滑动窗口中原子地记录 n 次请求，窗口内最多允许 limit 次请求，超出时不记录。
+func (rc *RedisClient) TakeWindow(key string, limit int, window time.Duration, n int, now time.Time) (LimitResult, error) {
+	return redisLimitResult(redisSlidingWindowScript.Run(rc, []string{key}, limit, window.Milliseconds(), n, now.UnixMilli(), lockNonce()))
+}
+
+// IncrExpire 使用 Lua 脚本将键的整数值原子地增加 delta 并返回增加后的值，键不存在时视为 0，键未设置过期时间时将其设置为 ttl。
+func (rc *RedisClient) IncrExpire(key string, delta int64, ttl time.Duration) (int64, error) {
+	reply, err := redisIncrExpireScript.Run(rc, []string{key}, delta, max(ttl.Milliseconds(), 1))
+	if err != nil {
+		return 0, err
+	}
+	value, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected reply type %T", reply)
+	}
+	return value, nil
+}
+
+// redisLimitResult 将限流脚本返回的数组应答转换为限流的判断结果。
+func redisLimitResult(reply any, err error) (LimitResult, error) {
+	if err != nil {
+		return LimitResult{}, err
+	}
+	items, ok := reply.([]any)
+	if !ok || len(items) != 3 {
+		return LimitResult{}, fmt.Errorf("unexpected reply %v of limiter", reply)
+	}
+	values := make([]int64, len(items))
+	for i, item := range items {
+		if values[i], ok = item.(int64); !ok {
+			return LimitResult{}, fmt.Errorf("unexpected reply type %T of limiter", item)
+		}
+	}
+	result := LimitResult{Allowed: values[0] == 1, Remaining: int(values[1]), RetryAfter: time.Duration(values[2]) * time.Millisecond}
+	if values[2] < 0 {
+		result.RetryAfter = -1
+	}
+	return result, nil
+}
@@ -0,0 +1,331 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RedisTxRetry 是 Redis 事务因监视的键被并发修改而中止时的最大尝试次数。
+const RedisTxRetry = 16
+
+// errRedisPending 表示命令仍在排队，尚未被执行。
+var errRedisPending = errors.New("redis command was not executed")
+
+// RedisResult 是管道或事务中单条命令的结果，在命令被执行之后可用，未执行时返回错误。
+// 结果提供了类型化的读取函数，服务端的错误应答将作为错误返回，空值的应答在读取字符串及数值时返回 ErrNotFound。
+type RedisResult struct {
+	reply any
+	err   error
+	done  bool
+}
+
+// set 设置命令的应答或错误，服务端的错误应答将被转换为错误。
+func (r *RedisResult) set(reply any, err error) {
+	if rerr, ok := reply.(RedisError); ok {
+		reply, err = nil, redisReplyError(rerr)
+	}
+	r.reply, r.err, r.done = reply, err, true
+}
+
+// Reply 返回命令的原始应答，应答的类型与 Do 相同。
+func (r *RedisResult) Reply() (any, error) {
+	if !r.done {
+		return nil, errRedisPending
+	}
+	return r.reply, r.err
+}
+
+// Err 返回命令执行的错误。
+func (r *RedisResult) Err() error {
+	_, err := r.Reply()
+	return err
+}
+
+// Text 将应答读取为字符串，空值时返回 ErrNotFound。
+func (r *RedisResult) Text() (string, error) {
+	reply, err := r.Reply()
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", ErrNotFound
+	}
+	return redisString(reply)
+}
+
+// Int 将应答读取为整数，支持整数及数字字符串的应答，空值时返回 ErrNotFound。
+func (r *RedisResult) Int() (int64, error) {
+	reply, err := r.Reply()
+	if err != nil {
+		return 0, err
+	}
+	switch v := reply.(type) {
+	case nil:
+		return 0, ErrNotFound
+	case int64:
+		return v, nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("unexpected reply type %T", reply)
+}
+
+// Float 将应答读取为浮点数，支持整数及数字字符串的应答（如有序集合的分数），空值时返回 ErrNotFound。
+func (r *RedisResult) Float() (float64, error) {
+	reply, err := r.Reply()
+	if err != nil {
+		return 0, err
+	}
+	switch v := reply.(type) {
+	case nil:
+		return 0, ErrNotFound
+	case int64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+	return 0, fmt.Errorf("unexpected reply type %T", reply)
+}
+
+// Bool 将应答读取为布尔值，非 0 的整数及 OK 视为 true，空值视为 false。
+func (r *RedisResult) Bool() (bool, error) {
+	reply, err := r.Reply()
+	if err != nil {
+		return false, err
+	}
+	switch v := reply.(type) {
+	case nil:
+		return false, nil
+	case int64:
+		return v != 0, nil
+	case string:
+		return v == "OK", nil
+	}
+	return false, fmt.Errorf("unexpected reply type %T", reply)
+}
+
+// Strings 将数组应答读取为字符串切片，空值视为空的数组。
+func (r *RedisResult) Strings() ([]string, error) {
+	return redisStrings(r.Reply())
+}
+
+// redisQueue 是排队的命令及其结果，由管道及事务共用。
+type redisQueue struct {
+	cmds    [][]any
+	results []*RedisResult
+}
+
+// Do 排队执行任意命令，参数与 RedisClient.Do 相同，返回的结果在命令被执行后可用。
+func (q *redisQueue) Do(args ...any) *RedisResult {
+	result := &RedisResult{}
+	if len(args) == 0 {
+		result.set(nil, fmt.Errorf("empty redis command"))
+		return result
+	}
+	q.cmds = append(q.cmds, args)
+	q.results = append(q.results, result)
+	return result
+}
+
+// Get 排队读取键的值，可以使用 Text 读取结果，键不存在时返回 ErrNotFound。
+func (q *redisQueue) Get(key string) *RedisResult {
+	return q.Do("GET", key)
+}
+
+// Set 排队设置键的值，ttl 为过期时间，小于等于 0 时不过期。
+func (q *redisQueue) Set(key, value string, ttl time.Duration) *RedisResult {
+	if ttl > 0 {
+		return q.Do("SET", key, value, "PX", ttl.Milliseconds())
+	}
+	return q.Do("SET", key, value)
+}
+
+// Delete 排队删除一个或多个键，可以使用 Int 读取被删除的键数量。
+func (q *redisQueue) Delete(keys ...string) *RedisResult {
+	if len(keys) == 0 {
+		result := &RedisResult{}
+		result.set(int64(0), nil)
+		return result
+	}
+	return q.Do(redisArgs("DEL", keys)...)
+}
+
+// IncrBy 排队将键的整数值增加 delta，可以使用 Int 读取增加后的值。
+func (q *redisQueue) IncrBy(key string, delta int64) *RedisResult {
+	return q.Do("INCRBY", key, delta)
+}
+
+// Len 返回排队的命令数量。
+func (q *redisQueue) Len() int { return len(q.cmds) }
+
+// RedisPipeline 是 Redis 的命令管道，通过 Do、Get、Set、Delete 及 IncrBy 排队命令，
+// Exec 时通过一次写入发送所有的命令并依次读取应答，以减少网络往返。管道不是线程安全的，且不保证命令的原子性。
+type RedisPipeline struct {
+	redisQueue
+	rc *RedisClient
+}
+
+// Pipeline 创建命令管道。
+func (rc *RedisClient) Pipeline() *RedisPipeline {
+	return &RedisPipeline{rc: rc}
+}
+
+// Exec 发送所有排队的命令并填充其结果，执行后管道被清空并可以继续使用。
+// 返回网络错误或第一条执行失败的命令的错误，其他命令的结果仍然可用。
+func (p *RedisPipeline) Exec() error {
+	cmds, results := p.cmds, p.results
+	p.cmds, p.results = nil, nil
+	if len(cmds) == 0 {
+		return nil
+	}
+	key, err := p.rc.slotKey(cmds)
+	if err != nil {
+		return resolveRedisResults(results, nil, err)
+	}
+	var replies []any
+	err = p.rc.on(key, func(conn *redisConn) (err error) {
+		if replies, err = conn.pipe(p.rc.timeout, cmds); err == nil {
+			if rerr, ok := replies[0].(RedisError); ok {
+				if kind, _, _ := redisRedirect(rerr); kind != "" {
+					return rerr // 槽位已迁移，所有的命令均未执行，在新节点上重试
+				}
+			}
+		}
+		return err
+	})
+	return resolveRedisResults(results, replies, err)
+}
+
+// RedisTx 是 Redis 的乐观锁事务，通过 Read 在监视的连接上立即执行读取命令，
+// 通过 Do、Get、Set、Delete 及 IncrBy 排队的命令将在事务提交时以 MULTI/EXEC 原子地执行。
+type RedisTx struct {
+	redisQueue
+	conn    *redisConn
+	timeout time.Duration
+	err     error // 连接的网络错误
+}
+
+// Read 在监视的连接上立即执行命令（通常为读取命令）并返回结果，用于在排队写入命令之前读取当前的值。
+func (tx *RedisTx) Read(args ...any) *RedisResult {
+	result := &RedisResult{}
+	if tx.err != nil {
+		result.set(nil, tx.err)
+		return result
+	}
+	if len(args) == 0 {
+		result.set(nil, fmt.Errorf("empty redis command"))
+		return result
+	}
+	reply, err := tx.conn.do(tx.timeout, args...)
+	if err != nil {
+		tx.err = err
+	}
+	result.set(reply, err)
+	return result
+}
+
+// Transaction 以 WATCH 乐观锁执行事务：监视 keys 后调用 fn，fn 通过 Read 读取当前的值并排队需要执行的命令，
+// 排队的命令将以 MULTI/EXEC 原子地执行。监视的键在提交之前被其他客户端修改时事务被中止并重新调用 fn，
+// 最多尝试 RedisTxRetry 次，超出时返回 ErrConflict；fn 返回错误或未排队任何命令时放弃事务并返回 fn 的错误。
+// 提交成功时返回第一条执行失败的命令的错误，与 Redis 的语义一致，事务中的其他命令不会被回滚。
+func (rc *RedisClient) Transaction(fn func(tx *RedisTx) error, keys ...string) error {
+	for i := 0; i < RedisTxRetry; i++ {
+		var ferr error
+		finished := false
+		key := ""
+		if len(keys) > 0 {
+			key = keys[0]
+		}
+		err := rc.on(key, func(conn *redisConn) error {
+			if len(keys) > 0 {
+				if _, err := conn.call(rc.timeout, redisArgs("WATCH", keys)...); err != nil {
+					return err
+				}
+			}
+			tx := &RedisTx{conn: conn, timeout: rc.timeout}
+			ferr = fn(tx)
+			if tx.err != nil {
+				return tx.err
+			}
+			if ferr != nil || len(tx.cmds) == 0 {
+				finished = true
+				_, err := conn.call(rc.timeout, "UNWATCH")
+				return err
+			}
+			cmds := make([][]any, 0, len(tx.cmds)+2)
+			cmds = append(cmds, []any{"MULTI"})
+			cmds = append(cmds, tx.cmds...)
+			cmds = append(cmds, []any{"EXEC"})
+			replies, err := conn.pipe(rc.timeout, cmds)
+			if err != nil {
+				return resolveRedisResults(tx.results, nil, err)
+			}
+			switch exec := replies[len(replies)-1].(type) {
+			case nil: // 监视的键被修改，事务被中止
+				return nil
+			case RedisError: // 命令排队失败，如参数错误（EXECABORT）
+				finished = true
+				return resolveRedisResults(tx.results, nil, redisReplyError(exec))
+			case []any:
+				finished = true
+				if len(exec) != len(tx.results) {
+					return fmt.Errorf("unexpected reply length %v of exec", len(exec))
+				}
+				return resolveRedisResults(tx.results, exec, nil)
+			default:
+				return fmt.Errorf("unexpected reply type %T", exec)
+			}
+		})
+		if ferr != nil {
+			return ferr
+		}
+		if err != nil || finished {
+			return err
+		}
+	}
+	return ErrConflict
+}
+
+// slotKey 返回管道中用于路由的键名，集群模式下所有命令的键必须位于同一槽位，否则返回 ErrCrossSlot。
+func (rc *RedisClient) slotKey(cmds [][]any) (string, error) {
+	key := ""
+	for _, args := range cmds {
+		ckey := redisCommandKey(args)
+		if ckey == "" {
+			continue
+		}
+		if key == "" {
+			key = ckey
+		} else if rc.cluster != nil && RedisSlot(ckey) != RedisSlot(key) {
+			return "", fmt.Errorf("%w: %v and %v", ErrCrossSlot, key, ckey)
+		}
+	}
+	return key, nil
+}
+
+// resolveRedisResults 将应答依次填充至结果，err 不为空时所有的结果均为该错误。
+// 返回 err 或第一条执行失败的命令的错误。
+func resolveRedisResults(results []*RedisResult, replies []any, err error) error {
+	for i, result := range results {
+		if err != nil {
+			result.set(nil, err)
+		} else {
+			result.set(replies[i], nil)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.err != nil {
+			return result.err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisPipeline(t *testing.T) {
+	server := newTestRedisServer(t)
+	client, _ := NewRedis(server.Addr(), 2, 8)
+	defer client.Close()
+
+	t.Run("Result", func(t *testing.T) {
+		tests := []struct {
+			reply any
+			text  string
+			num   int64
+			float float64
+			ok    bool
+		}{
+			{"12", "12", 12, 12, false},
+			{int64(3), "", 3, 3, true},
+			{"OK", "OK", 0, 0, true},
+			{"1.5", "1.5", 0, 1.5, false},
+		}
+		for _, test := range tests {
+			result := &RedisResult{}
+			result.set(test.reply, nil)
+			if test.text != "" {
+				text, err := result.Text()
+				assert.NoError(t, err, "Text 不应当返回错误：%v", test.reply)
+				assert.Equal(t, test.text, text, "Text 的结果应当和预期相等：%v", test.reply)
+			}
+			if test.num != 0 {
+				num, err := result.Int()
+				assert.NoError(t, err, "Int 不应当返回错误：%v", test.reply)
+				assert.Equal(t, test.num, num, "Int 的结果应当和预期相等：%v", test.reply)
+			}
+			if test.float != 0 {
+				float, err := result.Float()
+				assert.NoError(t, err, "Float 不应当返回错误：%v", test.reply)
+				assert.Equal(t, test.float, float, "Float 的结果应当和预期相等：%v", test.reply)
+			}
+			ok, _ := result.Bool()
+			assert.Equal(t, test.ok, ok, "Bool 的结果应当和预期相等：%v", test.reply)
+		}
+
+		result := &RedisResult{}
+		assert.Error(t, result.Err(), "命令执行之前读取结果应当返回错误。")
+		result.set(nil, nil)
+		_, err := result.Text()
+		assert.ErrorIs(t, err, ErrNotFound, "空值的应答读取字符串时应当返回 ErrNotFound。")
+		_, err = result.Int()
+		assert.ErrorIs(t, err, ErrNotFound, "空值的应答读取整数时应当返回 ErrNotFound。")
+		strs, err := result.Strings()
+		assert.NoError(t, err, "空值的应答读取字符串切片时不应当返回错误。")
+		assert.Empty(t, strs, "空值的应答应当视为空的数组。")
+		result.set(RedisError("WRONGTYPE Operation against a key holding the wrong kind of value"), nil)
+		assert.ErrorIs(t, result.Err(), ErrWrongType, "WRONGTYPE 应答应当被包装为 ErrWrongType。")
+	})
+
+	t.Run("Pipeline", func(t *testing.T) {
+		server.mutex.Lock()
+		server.commands = nil
+		server.mutex.Unlock()
+
+		p := client.Pipeline()
+		set := p.Set("pipe", "v1", 0)
+		get := p.Get("pipe")
+		incr1 := p.IncrBy("pipe:counter", 2)
+		incr2 := p.IncrBy("pipe:counter", 3)
+		missing := p.Get("pipe:missing")
+		del := p.Delete("pipe", "pipe:missing")
+		assert.Equal(t, 6, p.Len(), "排队的命令数量应当为 6。")
+		assert.Error(t, get.Err(), "Exec 之前结果应当不可用。")
+
+		assert.NoError(t, p.Exec(), "Exec 不应当返回错误。")
+		assert.Equal(t, 0, p.Len(), "Exec 之后管道应当被清空。")
+		ok, _ := set.Bool()
+		assert.True(t, ok, "Set 的结果应当为 OK。")
+		value, _ := get.Text()
+		assert.Equal(t, "v1", value, "Get 应当读取到管道中先前写入的值。")
+		count, _ := incr1.Int()
+		assert.Equal(t, int64(2), count, "IncrBy 的结果应当按顺序递增。")
+		count, _ = incr2.Int()
+		assert.Equal(t, int64(5), count, "IncrBy 的结果应当按顺序递增。")
+		_, err := missing.Text()
+		assert.ErrorIs(t, err, ErrNotFound, "读取不存在的键应当返回 ErrNotFound。")
+		count, _ = del.Int()
+		assert.Equal(t, int64(1), count, "被删除的键数量应当为 1。")
+
+		server.mutex.Lock()
+		assert.Equal(t, []string{"SET", "GET", "INCRBY", "INCRBY", "GET", "DEL"}, server.commands, "服务端应当按顺序执行排队的命令。")
+		server.mutex.Unlock()
+
+		assert.NoError(t, p.Exec(), "空的管道 Exec 不应当返回错误。")
+		count, err = p.Delete().Int()
+		assert.NoError(t, err, "删除空的键列表不应当返回错误。")
+		assert.Equal(t, int64(0), count, "删除空的键列表应当返回 0。")
+		assert.Error(t, p.Do().Err(), "空命令应当返回错误。")
+
+		p.Set("pipe:str", "v", 0)
+		wrong := p.Do("HGET", "pipe:str", "f")
+		after := p.Get("pipe:str")
+		err = p.Exec()
+		assert.ErrorIs(t, err, ErrWrongType, "Exec 应当返回第一条执行失败的命令的错误。")
+		assert.ErrorIs(t, wrong.Err(), ErrWrongType, "执行失败的命令的结果应当包含错误。")
+		value, _ = after.Text()
+		assert.Equal(t, "v", value, "执行失败的命令不应当影响其他命令的结果。")
+		assert.NoError(t, client.Ping(), "命令执行失败后连接应当仍然可用。")
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		client.Set("tx", "1", 0)
+		calls := 0
+		var set *RedisResult
+		err := client.Transaction(func(tx *RedisTx) error {
+			calls++
+			value, err := tx.Read("GET", "tx").Int()
+			if err != nil {
+				return err
+			}
+			set = tx.Set("tx", strconv.FormatInt(value+1, 10), 0)
+			return nil
+		}, "tx")
+		assert.NoError(t, err, "Transaction 不应当返回错误。")
+		assert.Equal(t, 1, calls, "未发生冲突时应当只执行一次。")
+		assert.NoError(t, set.Err(), "事务中排队的命令的结果应当可用。")
+		value, _ := client.Get("tx")
+		assert.Equal(t, "2", value, "事务提交后的值应当和预期相等。")
+
+		// 在读取之后修改键，模拟其他客户端的并发修改
+		server.mutex.Lock()
+		server.hook = func(cmd string, args []string) {
+			if cmd == "GET" && args[0] == "tx" {
+				server.data["tx"] = &testRedisEntry{value: "10"}
+				server.touch("tx")
+				server.hook = nil
+			}
+		}
+		server.mutex.Unlock()
+		calls = 0
+		err = client.Transaction(func(tx *RedisTx) error {
+			calls++
+			value, _ := tx.Read("GET", "tx").Int()
+			tx.Set("tx", strconv.FormatInt(value+1, 10), 0)
+			return nil
+		}, "tx")
+		assert.NoError(t, err, "冲突后重试成功时不应当返回错误。")
+		assert.Equal(t, 2, calls, "监视的键被修改时应当重试。")
+		value, _ = client.Get("tx")
+		assert.Equal(t, "11", value, "重试时应当基于最新的值提交。")
+
+		server.mutex.Lock()
+		server.hook = func(cmd string, args []string) {
+			if cmd == "GET" && args[0] == "tx" {
+				server.touch("tx")
+			}
+		}
+		server.mutex.Unlock()
+		calls = 0
+		err = client.Transaction(func(tx *RedisTx) error {
+			calls++
+			tx.Read("GET", "tx")
+			tx.Set("tx", "conflict", 0)
+			return nil
+		}, "tx")
+		server.mutex.Lock()
+		server.hook = nil
+		server.mutex.Unlock()
+		assert.ErrorIs(t, err, ErrConflict, "重试次数达到上限时应当返回 ErrConflict。")
+		assert.Equal(t, RedisTxRetry, calls, "尝试次数应当等于 RedisTxRetry。")
+		value, _ = client.Get("tx")
+		assert.Equal(t, "11", value, "事务被中止时不应当修改值。")
+
+		abort := errors.New("abort")
+		err = client.Transaction(func(tx *RedisTx) error {
+			tx.Set("tx", "aborted", 0)
+			return abort
+		}, "tx")
+		assert.ErrorIs(t, err, abort, "fn 返回错误时应当返回该错误。")
+		value, _ = client.Get("tx")
+		assert.Equal(t, "11", value, "fn 返回错误时不应当执行排队的命令。")
+		assert.NoError(t, client.Transaction(func(tx *RedisTx) error { return nil }, "tx"), "未排队任何命令时不应当返回错误。")
+
+		err = client.Transaction(func(tx *RedisTx) error {
+			tx.Do("HSET", "tx", "f", "v")
+			tx.Set("tx:other", "v", 0)
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrWrongType, "提交成功时应当返回第一条执行失败的命令的错误。")
+		value, _ = client.Get("tx:other")
+		assert.Equal(t, "v", value, "事务中的其他命令不应当被回滚。")
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		client.Set("tx:counter", "0", 0)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := client.Transaction(func(tx *RedisTx) error {
+					value, err := tx.Read("GET", "tx:counter").Int()
+					if err != nil {
+						return err
+					}
+					tx.Set("tx:counter", strconv.FormatInt(value+1, 10), 0)
+					return nil
+				}, "tx:counter")
+				assert.NoError(t, err, "并发的事务不应当返回错误。")
+			}()
+		}
+		wg.Wait()
+		value, _ := client.Get("tx:counter")
+		assert.Equal(t, "5", value, "并发的读取-修改-写入应当不丢失更新。")
+	})
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eframework-org/GO.UTIL/XLog"
+)
+
+// redisPubSub 是 Redis 的订阅连接，所有的订阅共用一个独立于连接池的连接，并统一使用 PSUBSCRIBE 订阅模式。
+// 连接断开时以 RedisRetry 为初始间隔重连，连续失败时间隔加倍直至 RedisBackoff，重连后重新订阅所有的模式。
+type redisPubSub struct {
+	rc       *RedisClient
+	conn     *redisConn               // 当前的连接，断开期间为 nil
+	patterns map[string][]*subscriber // 模式及其订阅者
+	confirms map[string]chan struct{} // 等待服务端确认订阅的模式
+	done     chan struct{}
+	once     sync.Once
+	mutex    sync.Mutex
+}
+
+// Publish 使用 PUBLISH 命令向频道发布消息，返回接收到消息的订阅者数量。
+func (rc *RedisClient) Publish(channel, message string) (int, error) {
+	return redisInt(rc.Do("PUBLISH", channel, message))
+}
+
+// Subscribe 使用 PSUBSCRIBE 命令订阅匹配模式的频道，返回接收消息的通道及取消订阅的函数。
+// 订阅连接将在首次订阅时建立，Subscribe 最多等待超时时间以确认订阅生效，连接断开时将自动重连并重新订阅。
+func (rc *RedisClient) Subscribe(pattern string) (<-chan Message, func(), error) {
+	rc.mutex.Lock()
+	if rc.closed {
+		rc.mutex.Unlock()
+		return nil, nil, ErrClosed
+	}
+	if rc.pubsub == nil {
+		rc.pubsub = &redisPubSub{rc: rc, patterns: make(map[string][]*subscriber), confirms: make(map[string]chan struct{}), done: make(chan struct{})}
+		go rc.pubsub.run()
+	}
+	ps := rc.pubsub
+	rc.mutex.Unlock()
+	return ps.subscribe(pattern)
+}
+
+// subscribe 添加模式的订阅者，模式首次被订阅时发送 PSUBSCRIBE 并等待确认。
+func (ps *redisPubSub) subscribe(pattern string) (<-chan Message, func(), error) {
+	s := newSubscriber(pattern)
+	ps.mutex.Lock()
+	subs := ps.patterns[pattern]
+	ps.patterns[pattern] = append(subs, s)
+	confirm := ps.confirms[pattern]
+	if len(subs) == 0 {
+		confirm = make(chan struct{})
+		ps.confirms[pattern] = confirm
+		if ps.conn != nil {
+			ps.write(ps.conn, "PSUBSCRIBE", pattern)
+		}
+	}
+	ps.mutex.Unlock()
+
+	if confirm != nil {
+		timer := time.NewTimer(ps.rc.timeout)
+		defer timer.Stop()
+		select {
+		case <-confirm:
+		case <-timer.C:
+			XLog.Warn("XPairs.Subscribe: wait for confirmation of pattern %v timeout, it will be resubscribed on reconnection.", pattern)
+		case <-ps.done:
+		}
+	}
+	return s.out, func() { ps.unsubscribe(s) }, nil
+}
+
+// unsubscribe 移除订阅者，模式的最后一个订阅者被移除时发送 PUNSUBSCRIBE。
+func (ps *redisPubSub) unsubscribe(s *subscriber) {
+	s.stop()
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	subs := ps.patterns[s.pattern]
+	for i, ts := range subs {
+		if ts == s {
+			subs = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(subs) > 0 {
+		ps.patterns[s.pattern] = subs
+		return
+	}
+	delete(ps.patterns, s.pattern)
+	delete(ps.confirms, s.pattern)
+	if ps.conn != nil {
+		ps.write(ps.conn, "PUNSUBSCRIBE", s.pattern)
+	}
+}
+
+// stop 关闭订阅连接及所有的订阅者，可以重复调用。
+func (ps *redisPubSub) stop() {
+	ps.once.Do(func() {
+		close(ps.done)
+		ps.mutex.Lock()
+		defer ps.mutex.Unlock()
+		if ps.conn != nil {
+			ps.conn.conn.Close()
+		}
+		for _, subs := range ps.patterns {
+			for _, s := range subs {
+				s.stop()
+			}
+		}
+		ps.patterns = make(map[string][]*subscriber)
+	})
+}
+
+// run 建立订阅连接并读取消息，连接断开时重连并重新订阅所有的模式，直至被关闭。
+func (ps *redisPubSub) run() {
+	delay := ps.rc.retry
+	for {
+		conn, err := ps.rc.dial()
+		if err == nil {
+			delay = ps.rc.retry
+			conn.conn.SetDeadline(time.Time{})
+			ps.mutex.Lock()
+			select {
+			case <-ps.done:
+				ps.mutex.Unlock()
+				conn.conn.Close()
+				return
+			default:
+			}
+			ps.conn = conn
+			if len(ps.patterns) > 0 {
+				args := []any{"PSUBSCRIBE"}
+				for pattern := range ps.patterns {
+					args = append(args, pattern)
+				}
+				ps.write(conn, args...)
+			}
+			ps.mutex.Unlock()
+
+			err = ps.read(conn)
+			ps.mutex.Lock()
+			ps.conn = nil
+			ps.mutex.Unlock()
+			conn.conn.Close()
+		}
+		select {
+		case <-ps.done:
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, ps.rc.backoff)
+		XLog.Warn("XPairs.Subscribe: reconnect to %v: %v", ps.rc.addr, err)
+	}
+}
+
+// read 读取订阅连接上的消息并分发至匹配的订阅者，直至连接断开。
+func (ps *redisPubSub) read(conn *redisConn) error {
+	for {
+		reply, err := readRedisReply(conn.reader)
+		if err != nil {
+			return err
+		}
+		items, _ := reply.([]any)
+		if len(items) < 3 {
+			continue
+		}
+		kind, _ := items[0].(string)
+		pattern, _ := items[1].(string)
+		switch strings.ToLower(kind) {
+		case "pmessage":
+			if len(items) < 4 {
+				continue
+			}
+			channel, _ := items[2].(string)
+			payload, _ := items[3].(string)
+			ps.mutex.Lock()
+			for _, s := range ps.patterns[pattern] {
+				s.push(channel, payload)
+			}
+			ps.mutex.Unlock()
+		case "psubscribe":
+			ps.mutex.Lock()
+			if confirm := ps.confirms[pattern]; confirm != nil {
+				close(confirm)
+				delete(ps.confirms, pattern)
+			}
+			ps.mutex.Unlock()
+		}
+	}
+}
+
+// write 在订阅连接上发送命令，调用者需持有锁；发送失败时关闭连接以触发重连。
+func (ps *redisPubSub) write(conn *redisConn, args ...any) {
+	conn.conn.SetWriteDeadline(time.Now().Add(ps.rc.timeout))
+	err := writeRedisCommand(conn.writer, args...)
+	if err == nil {
+		err = conn.writer.Flush()
+	}
+	if err != nil {
+		conn.conn.Close()
+	}
+}
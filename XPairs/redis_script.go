@@ -0,0 +1,56 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// RedisScript 是 Redis 的 Lua 脚本，脚本在服务端原子地执行。
+// 执行时优先使用 EVALSHA 以减少传输，服务端未缓存脚本时（NOSCRIPT）回退至 EVAL 并缓存脚本。
+type RedisScript struct {
+	src string
+	sha string
+}
+
+// NewRedisScript 创建 Lua 脚本，src 为脚本的源码。
+func NewRedisScript(src string) *RedisScript {
+	sum := sha1.Sum([]byte(src))
+	return &RedisScript{src: src, sha: hex.EncodeToString(sum[:])}
+}
+
+// SHA 返回脚本的 SHA1 摘要。
+func (s *RedisScript) SHA() string { return s.sha }
+
+// Run 在客户端上执行脚本并返回应答，keys 为脚本访问的键（KEYS），args 为其他参数（ARGV），应答的类型与 Do 相同。
+func (s *RedisScript) Run(rc *RedisClient, keys []string, args ...any) (any, error) {
+	key := ""
+	if len(keys) > 0 {
+		key = keys[0]
+	}
+	var reply any
+	err := rc.on(key, func(conn *redisConn) (err error) {
+		reply, err = conn.call(rc.timeout, s.args("EVALSHA", s.sha, keys, args)...)
+		var rerr RedisError
+		if errors.As(err, &rerr) && strings.HasPrefix(string(rerr), "NOSCRIPT") {
+			reply, err = conn.call(rc.timeout, s.args("EVAL", s.src, keys, args)...)
+		}
+		return err
+	})
+	return reply, err
+}
+
+// args 拼接执行脚本的命令及参数。
+func (s *RedisScript) args(cmd, script string, keys []string, args []any) []any {
+	rets := make([]any, 0, len(keys)+len(args)+3)
+	rets = append(rets, cmd, script, len(keys))
+	for _, key := range keys {
+		rets = append(rets, key)
+	}
+	return append(rets, args...)
+}
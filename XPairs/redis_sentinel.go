@@ -0,0 +1,125 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eframework-org/GO.UTIL/XLog"
+)
+
+// redisSentinel 是 Sentinel 模式的配置，通过 Sentinel 发现主节点的地址并跟随故障转移。
+type redisSentinel struct {
+	addrs    []string // Sentinel 的地址
+	name     string   // 主节点的名称
+	username string   // Sentinel 的认证用户名，为空时仅使用密码认证
+	password string   // Sentinel 的认证密码
+	master   string   // 缓存的主节点地址，为空时重新发现
+	mutex    sync.Mutex
+}
+
+// current 返回缓存的主节点地址，未缓存时依次询问各 Sentinel 以发现主节点。
+func (s *redisSentinel) current(timeout time.Duration) (string, error) {
+	s.mutex.Lock()
+	master := s.master
+	s.mutex.Unlock()
+	if master != "" {
+		return master, nil
+	}
+	master, err := s.discover(timeout)
+	if err != nil {
+		return "", err
+	}
+	s.mutex.Lock()
+	s.master = master
+	s.mutex.Unlock()
+	return master, nil
+}
+
+// discover 依次询问各 Sentinel 主节点的地址，返回第一个有效的应答。
+func (s *redisSentinel) discover(timeout time.Duration) (string, error) {
+	var errs []string
+	for _, addr := range s.addrs {
+		master, err := s.query(addr, timeout)
+		if err == nil {
+			return master, nil
+		}
+		errs = append(errs, fmt.Sprintf("%v: %v", addr, err))
+	}
+	return "", fmt.Errorf("discover master %v from sentinels failed: %v", s.name, strings.Join(errs, "; "))
+}
+
+// query 使用 SENTINEL get-master-addr-by-name 命令向单个 Sentinel 询问主节点的地址。
+func (s *redisSentinel) query(addr string, timeout time.Duration) (string, error) {
+	nconn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer nconn.Close()
+	conn := &redisConn{conn: nconn, reader: bufio.NewReader(nconn), writer: bufio.NewWriter(nconn)}
+	if err := conn.auth(timeout, s.username, s.password); err != nil {
+		return "", err
+	}
+	items, err := redisStrings(conn.call(timeout, "SENTINEL", "get-master-addr-by-name", s.name))
+	if err != nil {
+		return "", err
+	}
+	if len(items) != 2 {
+		return "", fmt.Errorf("unknown master %v", s.name)
+	}
+	return net.JoinHostPort(items[0], items[1]), nil
+}
+
+// matches 判断地址是否为缓存的主节点地址。
+func (s *redisSentinel) matches(addr string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.master == addr
+}
+
+// invalidate 清除缓存的主节点地址，下次建立连接时重新发现。
+func (s *redisSentinel) invalidate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.master = ""
+}
+
+// follow 重新发现主节点的地址，与缓存的地址不一致（发生了故障转移）时更新缓存，并关闭连接至原主节点的空闲连接。
+func (rc *RedisClient) follow() {
+	master, err := rc.sentinel.discover(rc.timeout)
+	if err != nil || rc.sentinel.matches(master) {
+		return
+	}
+	rc.sentinel.mutex.Lock()
+	old := rc.sentinel.master
+	rc.sentinel.master = master
+	rc.sentinel.mutex.Unlock()
+	if old != "" {
+		XLog.Notice("XPairs.Sentinel: master %v is switched from %v to %v.", rc.sentinel.name, old, master)
+	}
+	rc.flush()
+}
+
+// verify 校验新建立的连接是否连接至主节点，Sentinel 可能在故障转移完成之前返回已被降级的原主节点。
+func (rc *RedisClient) verify(conn *redisConn) error {
+	reply, err := conn.call(rc.timeout, "ROLE")
+	if err != nil {
+		return err
+	}
+	var role string
+	if items, _ := reply.([]any); len(items) > 0 {
+		role, _ = items[0].(string)
+	}
+	if role != "master" {
+		rc.sentinel.invalidate()
+		return fmt.Errorf("%v is not the master of %v", conn.addr, rc.sentinel.name)
+	}
+	return nil
+}
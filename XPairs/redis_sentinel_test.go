@@ -0,0 +1,141 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRedisWrites 是从节点拒绝执行的写入命令。
+var testRedisWrites = map[string]bool{"SET": true, "DEL": true, "INCRBY": true, "HSET": true, "ZADD": true, "LPUSH": true, "RPUSH": true, "PEXPIRE": true}
+
+// sentinelExec 模拟 Sentinel 及主从角色的命令：SENTINEL 返回 masters 中记录的主节点地址，ROLE 返回节点的角色，
+// 从节点拒绝写入命令并返回 READONLY，返回 nil 时继续执行命令。
+func (s *testRedisServer) sentinelExec(cmd string, args []string) any {
+	switch {
+	case cmd == "SENTINEL":
+		if len(args) == 2 && strings.EqualFold(args[0], "get-master-addr-by-name") {
+			if addr, ok := s.masters[args[1]]; ok {
+				host, port, _ := strings.Cut(addr, ":")
+				return []any{host, port}
+			}
+			return testRedisNilArray{}
+		}
+		return RedisError("ERR unsupported sentinel command")
+	case cmd == "ROLE":
+		if s.role == "" {
+			return []any{"master", 0, []any{}}
+		}
+		return []any{s.role, "127.0.0.1", 0, "connected", 0}
+	case s.role == "slave" && testRedisWrites[cmd]:
+		return RedisError("READONLY You can't write against a read only replica.")
+	}
+	return nil
+}
+
+func TestRedisSentinel(t *testing.T) {
+	master := newTestRedisServer(t)
+	replica := newTestRedisServer(t)
+	replica.role = "slave"
+	sentinel := newTestRedisServer(t, "spw")
+	sentinel.masters = map[string]string{"mymaster": master.Addr()}
+
+	t.Run("Addr", func(t *testing.T) {
+		tests := []struct {
+			addr  string
+			valid bool
+		}{
+			{"redis+sentinel://127.0.0.1:26379/mymaster", true},
+			{"redis+sentinel://:pw@127.0.0.1:26379,127.0.0.1:26380/mymaster/2?sentinel_password=spw", true},
+			{"redis+sentinel://127.0.0.1:26379", false},
+			{"redis+sentinel://127.0.0.1:26379/mymaster/abc", false},
+			{"redis+sentinel://127.0.0.1:26379,127.0.0.1/mymaster", false},
+		}
+		for _, test := range tests {
+			rc, err := NewRedis(test.addr, 1, 1)
+			if !test.valid {
+				assert.Error(t, err, "无效的地址应当返回错误：%v", test.addr)
+				continue
+			}
+			assert.NoError(t, err, "有效的地址不应当返回错误：%v", test.addr)
+			assert.NotNil(t, rc.sentinel, "Sentinel 的配置不应当为空：%v", test.addr)
+			assert.Equal(t, "mymaster", rc.sentinel.name, "主节点的名称应当和预期相等：%v", test.addr)
+		}
+		rc, _ := NewRedis("redis+sentinel://:pw@127.0.0.1:26379,127.0.0.1:26380/mymaster/2?sentinel_password=spw", 1, 1)
+		assert.Equal(t, []string{"127.0.0.1:26379", "127.0.0.1:26380"}, rc.sentinel.addrs, "Sentinel 的地址应当和预期相等。")
+		assert.Equal(t, "spw", rc.sentinel.password, "Sentinel 的密码应当和预期相等。")
+		assert.Equal(t, "pw", rc.password, "节点的密码应当和预期相等。")
+		assert.Equal(t, 2, rc.db, "数据库索引应当和预期相等。")
+		rc, _ = NewRedis("redis+sentinel://user:pw@127.0.0.1:26379/mymaster?sentinel_username=suser&sentinel_password=spw", 1, 1)
+		assert.Equal(t, "suser", rc.sentinel.username, "Sentinel 的用户名应当和预期相等。")
+		assert.Equal(t, "user", rc.username, "节点的用户名应当和预期相等。")
+	})
+
+	t.Run("Auth", func(t *testing.T) {
+		usentinel := newTestRedisServer(t, "spw")
+		usentinel.username = "suser"
+		usentinel.masters = map[string]string{"mymaster": master.Addr()}
+		rc, _ := NewRedis("redis+sentinel://"+usentinel.Addr()+"/mymaster?sentinel_username=suser&sentinel_password=spw", 1, 1)
+		defer rc.Close()
+		assert.NoError(t, rc.Ping(), "指定用户名时应当使用 AUTH <username> <password> 认证 Sentinel。")
+		wrong, _ := NewRedis("redis+sentinel://"+usentinel.Addr()+"/mymaster?sentinel_password=spw", 1, 1)
+		defer wrong.Close()
+		assert.Error(t, wrong.Ping(), "Sentinel 要求用户名时仅使用密码认证应当返回错误。")
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		// 第一个 Sentinel 不可达时应当询问下一个
+		rc, err := NewRedis("redis+sentinel://127.0.0.1:1,"+sentinel.Addr()+"/mymaster?sentinel_password=spw&ping=0", 2, 4)
+		assert.NoError(t, err, "NewRedis 不应当返回错误。")
+		defer rc.Close()
+
+		assert.NoError(t, rc.Set("key", "v1", 0), "Set 不应当返回错误。")
+		master.locked(func() { assert.Equal(t, "v1", master.data["key"].value, "写入应当发送至主节点。") })
+
+		// 故障转移：原主节点被降级，Sentinel 返回新的主节点
+		master.locked(func() { master.role = "slave" })
+		replica.locked(func() { replica.role = "" })
+		sentinel.locked(func() { sentinel.masters["mymaster"] = replica.Addr() })
+		err = rc.Set("key", "v2", 0)
+		assert.ErrorContains(t, err, "READONLY", "写入被降级的节点应当返回 READONLY。")
+		assert.Equal(t, 0, rc.Stats().Size, "READONLY 后应当关闭连接至原主节点的连接。")
+		assert.NoError(t, rc.Set("key", "v2", 0), "重新发现主节点后 Set 不应当返回错误。")
+		replica.locked(func() { assert.Equal(t, "v2", replica.data["key"].value, "写入应当发送至新的主节点。") })
+
+		// Sentinel 返回的节点不是主节点时拒绝连接
+		rc.flush()
+		rc.sentinel.invalidate()
+		replica.locked(func() { replica.role = "slave" })
+		_, err = rc.Get("key")
+		assert.ErrorIs(t, err, ErrUnavailable, "连接至从节点时应当返回 ErrUnavailable。")
+		assert.ErrorContains(t, err, "is not the master", "错误信息应当包含角色校验的结果。")
+		replica.locked(func() { replica.role = "" })
+	})
+
+	t.Run("Follow", func(t *testing.T) {
+		master.locked(func() { master.role = "slave" })
+		replica.locked(func() { replica.role = "" })
+		sentinel.locked(func() { sentinel.masters["mymaster"] = replica.Addr() })
+		rc, _ := NewRedis("redis+sentinel://"+sentinel.Addr()+"/mymaster?sentinel_password=spw", 2, 4)
+		rc.ping = 20 * time.Millisecond
+		defer rc.Close()
+
+		assert.NoError(t, rc.Set("follow", "v1", 0), "Set 不应当返回错误。")
+		assert.Equal(t, 1, rc.Stats().Size, "连接数量应当和预期相等。")
+
+		// 故障转移后健康检查应当主动跟随新的主节点，而不必等待写入失败
+		master.locked(func() { master.role = "" })
+		replica.locked(func() { replica.role = "slave" })
+		sentinel.locked(func() { sentinel.masters["mymaster"] = master.Addr() })
+		assert.Eventually(t, func() bool { return rc.sentinel.matches(master.Addr()) }, time.Second, 10*time.Millisecond, "健康检查应当发现新的主节点。")
+		assert.Eventually(t, func() bool { return rc.Stats().Size == 0 }, time.Second, 10*time.Millisecond, "连接至原主节点的空闲连接应当被关闭。")
+		assert.NoError(t, rc.Set("follow", "v2", 0), "Set 不应当返回错误。")
+		master.locked(func() { assert.Equal(t, "v2", master.data["follow"].value, "写入应当发送至新的主节点。") })
+	})
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// HGet 使用 HGET 命令读取哈希表字段的值，键或字段不存在时返回 ErrNotFound。
+func (rc *RedisClient) HGet(key, field string) (string, error) {
+	reply, err := rc.Do("HGET", key, field)
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", ErrNotFound
+	}
+	return redisString(reply)
+}
+
+// HSet 使用 HSET 命令设置哈希表一个或多个字段的值，返回新增的字段数量。
+func (rc *RedisClient) HSet(key string, fields map[string]string) (int, error) {
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := make([]any, 0, len(fields)*2+2)
+	args = append(args, "HSET", key)
+	for _, name := range names {
+		args = append(args, name, fields[name])
+	}
+	return redisInt(rc.Do(args...))
+}
+
+// HIncrBy 使用 HINCRBY 命令将哈希表字段的整数值增加 delta，返回增加后的值。
+func (rc *RedisClient) HIncrBy(key, field string, delta int64) (int64, error) {
+	reply, err := rc.Do("HINCRBY", key, field, delta)
+	if err != nil {
+		return 0, err
+	}
+	if val, ok := reply.(int64); ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("unexpected reply type %T", reply)
+}
+
+// HGetAll 使用 HGETALL 命令读取哈希表的所有字段及其值，键不存在时返回空的结果。
+func (rc *RedisClient) HGetAll(key string) (map[string]string, error) {
+	items, err := redisStrings(rc.Do("HGETALL", key))
+	if err != nil {
+		return nil, err
+	}
+	rets := make(map[string]string, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		rets[items[i]] = items[i+1]
+	}
+	return rets, nil
+}
+
+// HDel 使用 HDEL 命令删除哈希表的一个或多个字段，返回被删除的字段数量。
+func (rc *RedisClient) HDel(key string, fields ...string) (int, error) {
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	return redisInt(rc.Do(redisKeyArgs("HDEL", key, fields)...))
+}
+
+// ZAdd 使用 ZADD 命令添加一个或多个成员或更新已存在成员的分数，返回新增的成员数量。
+func (rc *RedisClient) ZAdd(key string, members map[string]float64) (int, error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := make([]any, 0, len(members)*2+2)
+	args = append(args, "ZADD", key)
+	for _, name := range names {
+		args = append(args, members[name], name)
+	}
+	return redisInt(rc.Do(args...))
+}
+
+// ZIncrBy 使用 ZINCRBY 命令将成员的分数增加 delta，返回增加后的分数。
+func (rc *RedisClient) ZIncrBy(key, member string, delta float64) (float64, error) {
+	return redisFloat(rc.Do("ZINCRBY", key, delta, member))
+}
+
+// redisZIncrByInitScript 是成员不存在时以初始分数添加并增加分数的脚本。
+// KEYS[1] 为有序集合的键，ARGV 依次为成员、初始分数及增量，返回增加后的分数。
+var redisZIncrByInitScript = NewRedisScript(`
+redis.call('ZADD', KEYS[1], 'NX', ARGV[2], ARGV[1])
+return redis.call('ZINCRBY', KEYS[1], ARGV[3], ARGV[1])
+`)
+
+// ZIncrByInit 使用 Lua 脚本原子地将成员的分数增加 delta，成员不存在时以 init 为初始分数，返回增加后的分数。
+func (rc *RedisClient) ZIncrByInit(key, member string, init, delta float64) (float64, error) {
+	return redisFloat(redisZIncrByInitScript.Run(rc, []string{key}, member, init, delta))
+}
+
+// ZScore 使用 ZSCORE 命令读取成员的分数，键或成员不存在时返回 ErrNotFound。
+func (rc *RedisClient) ZScore(key, member string) (float64, error) {
+	reply, err := rc.Do("ZSCORE", key, member)
+	if err == nil && reply == nil {
+		return 0, ErrNotFound
+	}
+	return redisFloat(reply, err)
+}
+
+// ZRange 使用 ZRANGE 命令按分数升序返回索引在 [start, stop] 之间的成员及其分数。
+func (rc *RedisClient) ZRange(key string, start, stop int) ([]ZMember, error) {
+	return redisZMembers(rc.Do("ZRANGE", key, start, stop, "WITHSCORES"))
+}
+
+// ZRevRange 使用 ZREVRANGE 命令按分数降序返回索引在 [start, stop] 之间的成员及其分数。
+func (rc *RedisClient) ZRevRange(key string, start, stop int) ([]ZMember, error) {
+	return redisZMembers(rc.Do("ZREVRANGE", key, start, stop, "WITHSCORES"))
+}
+
+// ZRangeByScore 使用 ZRANGEBYSCORE 命令按分数升序返回分数在 [min, max] 之间的成员，count 小于 0 时返回所有。
+func (rc *RedisClient) ZRangeByScore(key string, min, max float64, offset, count int) ([]ZMember, error) {
+	return redisZMembers(rc.Do("ZRANGEBYSCORE", key, min, max, "WITHSCORES", "LIMIT", offset, count))
+}
+
+// ZRevRangeByScore 使用 ZREVRANGEBYSCORE 命令按分数降序返回分数在 [min, max] 之间的成员，count 小于 0 时返回所有。
+func (rc *RedisClient) ZRevRangeByScore(key string, min, max float64, offset, count int) ([]ZMember, error) {
+	return redisZMembers(rc.Do("ZREVRANGEBYSCORE", key, max, min, "WITHSCORES", "LIMIT", offset, count))
+}
+
+// ZRank 使用 ZRANK 命令返回成员按分数升序的排名，键或成员不存在时返回 ErrNotFound。
+func (rc *RedisClient) ZRank(key, member string) (int, error) {
+	return redisRank(rc.Do("ZRANK", key, member))
+}
+
+// ZRevRank 使用 ZREVRANK 命令返回成员按分数降序的排名，键或成员不存在时返回 ErrNotFound。
+func (rc *RedisClient) ZRevRank(key, member string) (int, error) {
+	return redisRank(rc.Do("ZREVRANK", key, member))
+}
+
+// ZRem 使用 ZREM 命令删除一个或多个成员，返回被删除的成员数量。
+func (rc *RedisClient) ZRem(key string, members ...string) (int, error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+	return redisInt(rc.Do(redisKeyArgs("ZREM", key, members)...))
+}
+
+// ZCard 使用 ZCARD 命令返回有序集合的成员数量，键不存在时返回 0。
+func (rc *RedisClient) ZCard(key string) (int, error) {
+	return redisInt(rc.Do("ZCARD", key))
+}
+
+// LPush 使用 LPUSH 命令将一个或多个值依次插入列表的头部，返回插入后列表的长度。
+func (rc *RedisClient) LPush(key string, values ...string) (int, error) {
+	if len(values) == 0 {
+		return rc.LLen(key)
+	}
+	return redisInt(rc.Do(redisKeyArgs("LPUSH", key, values)...))
+}
+
+// RPush 使用 RPUSH 命令将一个或多个值依次插入列表的尾部，返回插入后列表的长度。
+func (rc *RedisClient) RPush(key string, values ...string) (int, error) {
+	if len(values) == 0 {
+		return rc.LLen(key)
+	}
+	return redisInt(rc.Do(redisKeyArgs("RPUSH", key, values)...))
+}
+
+// LPop 使用 LPOP 命令弹出列表头部的值，键不存在时返回 ErrNotFound。
+func (rc *RedisClient) LPop(key string) (string, error) {
+	return redisPop(rc.Do("LPOP", key))
+}
+
+// RPop 使用 RPOP 命令弹出列表尾部的值，键不存在时返回 ErrNotFound。
+func (rc *RedisClient) RPop(key string) (string, error) {
+	return redisPop(rc.Do("RPOP", key))
+}
+
+// LRange 使用 LRANGE 命令返回列表中索引在 [start, stop] 之间的值。
+func (rc *RedisClient) LRange(key string, start, stop int) ([]string, error) {
+	return redisStrings(rc.Do("LRANGE", key, start, stop))
+}
+
+// LLen 使用 LLEN 命令返回列表的长度，键不存在时返回 0。
+func (rc *RedisClient) LLen(key string) (int, error) {
+	return redisInt(rc.Do("LLEN", key))
+}
+
+// BLPop 使用 BLPOP 命令弹出第一个非空列表头部的值，所有列表均为空时阻塞等待，超时时返回 ErrNotFound。
+// 等待期间将独占一个连接，连接的读写超时时间为 timeout 加上客户端的超时时间。
+func (rc *RedisClient) BLPop(timeout time.Duration, keys ...string) (string, string, error) {
+	if timeout <= 0 {
+		return "", "", fmt.Errorf("invalid timeout %v of blpop", timeout)
+	}
+	if len(keys) == 0 {
+		return "", "", fmt.Errorf("empty keys of blpop")
+	}
+	args := append(redisArgs("BLPOP", keys), timeout.Seconds())
+	var reply any
+	err := rc.on(keys[0], func(conn *redisConn) (err error) {
+		reply, err = conn.call(rc.timeout+timeout, args...)
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if reply == nil {
+		return "", "", ErrNotFound
+	}
+	items, err := redisStrings(reply, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if len(items) != 2 {
+		return "", "", fmt.Errorf("unexpected reply length %v of blpop", len(items))
+	}
+	return items[0], items[1], nil
+}
+
+// redisKeyArgs 拼接命令、键名及字符串参数。
+func redisKeyArgs(cmd, key string, values []string) []any {
+	args := make([]any, 0, len(values)+2)
+	args = append(args, cmd, key)
+	for _, value := range values {
+		args = append(args, value)
+	}
+	return args
+}
+
+// redisStrings 将数组应答转换为字符串切片，空值视为空的数组。
+func redisStrings(reply any, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return []string{}, nil
+	}
+	items, ok := reply.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply type %T", reply)
+	}
+	rets := make([]string, len(items))
+	for i, item := range items {
+		if rets[i], err = redisString(item); err != nil {
+			return nil, err
+		}
+	}
+	return rets, nil
+}
+
+// redisFloat 将应答转换为浮点数，Redis 以字符串的形式返回分数。
+func redisFloat(reply any, err error) (float64, error) {
+	if err != nil {
+		return 0, err
+	}
+	str, err := redisString(reply)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(str, 64)
+}
+
+// redisZMembers 将 WITHSCORES 的数组应答转换为有序集合的成员。
+func redisZMembers(reply any, err error) ([]ZMember, error) {
+	items, err := redisStrings(reply, err)
+	if err != nil {
+		return nil, err
+	}
+	rets := make([]ZMember, 0, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		score, err := strconv.ParseFloat(items[i+1], 64)
+		if err != nil {
+			return nil, err
+		}
+		rets = append(rets, ZMember{Member: items[i], Score: score})
+	}
+	return rets, nil
+}
+
+// redisRank 将排名的应答转换为整数，空值表示成员不存在。
+func redisRank(reply any, err error) (int, error) {
+	if err == nil && reply == nil {
+		return 0, ErrNotFound
+	}
+	return redisInt(reply, err)
+}
+
+// redisPop 将弹出的应答转换为字符串，空值表示列表为空。
+func redisPop(reply any, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", ErrNotFound
+	}
+	return redisString(reply)
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// IHash 是哈希表的接口，语义与 Redis 的 HGET、HSET 等命令一致。
+// 对非哈希表类型的键执行操作时返回 ErrWrongType，哈希表的所有字段被删除后键亦被删除。
+type IHash interface {
+	// HGet 读取哈希表字段的值，键或字段不存在时返回 ErrNotFound。
+	HGet(key, field string) (string, error)
+
+	// HSet 设置哈希表一个或多个字段的值，返回新增的字段数量。
+	HSet(key string, fields map[string]string) (int, error)
+
+	// HIncrBy 将哈希表字段的整数值增加 delta，字段不存在时视为 0，返回增加后的值。
+	HIncrBy(key, field string, delta int64) (int64, error)
+
+	// HGetAll 读取哈希表的所有字段及其值，键不存在时返回空的结果。
+	HGetAll(key string) (map[string]string, error)
+
+	// HDel 删除哈希表的一个或多个字段，返回被删除的字段数量。
+	HDel(key string, fields ...string) (int, error)
+}
+
+// ZMember 是有序集合的成员及其分数。
+type ZMember struct {
+	Member string  // 成员
+	Score  float64 // 分数
+}
+
+// ISortedSet 是有序集合的接口，语义与 Redis 的 ZADD、ZRANGE 等命令一致。
+// 成员按分数升序排列，分数相同时按成员的字典序排列；排名及范围的索引从 0 开始，负数表示从末尾倒数。
+// 对非有序集合类型的键执行操作时返回 ErrWrongType，有序集合的所有成员被删除后键亦被删除。
+type ISortedSet interface {
+	// ZAdd 添加一个或多个成员或更新已存在成员的分数，返回新增的成员数量。
+	ZAdd(key string, members map[string]float64) (int, error)
+
+	// ZIncrBy 将成员的分数增加 delta，成员不存在时视为 0，返回增加后的分数。
+	ZIncrBy(key, member string, delta float64) (float64, error)
+
+	// ZScore 读取成员的分数，键或成员不存在时返回 ErrNotFound。
+	ZScore(key, member string) (float64, error)
+
+	// ZRange 按分数升序返回索引在 [start, stop] 之间的成员及其分数。
+	ZRange(key string, start, stop int) ([]ZMember, error)
+
+	// ZRevRange 按分数降序返回索引在 [start, stop] 之间的成员及其分数。
+	ZRevRange(key string, start, stop int) ([]ZMember, error)
+
+	// ZRangeByScore 按分数升序返回分数在 [min, max] 之间的成员，跳过 offset 个成员后最多返回 count 个，count 小于 0 时返回所有。
+	ZRangeByScore(key string, min, max float64, offset, count int) ([]ZMember, error)
+
+	// ZRevRangeByScore 按分数降序返回分数在 [min, max] 之间的成员，跳过 offset 个成员后最多返回 count 个，count 小于 0 时返回所有。
+	ZRevRangeByScore(key string, min, max float64, offset, count int) ([]ZMember, error)
+
+	// ZRank 返回成员按分数升序的排名，键或成员不存在时返回 ErrNotFound。
+	ZRank(key, member string) (int, error)
+
+	// ZRevRank 返回成员按分数降序的排名，键或成员不存在时返回 ErrNotFound。
+	ZRevRank(key, member string) (int, error)
+
+	// ZRem 删除一个或多个成员，返回被删除的成员数量。
+	ZRem(key string, members ...string) (int, error)
+
+	// ZCard 返回有序集合的成员数量，键不存在时返回 0。
+	ZCard(key string) (int, error)
+}
+
+// IList 是列表的接口，语义与 Redis 的 LPUSH、RPOP 等命令一致。
+// 范围的索引从 0 开始，负数表示从末尾倒数；对非列表类型的键执行操作时返回 ErrWrongType，列表的所有元素被弹出后键亦被删除。
+type IList interface {
+	// LPush 将一个或多个值依次插入列表的头部，返回插入后列表的长度。
+	LPush(key string, values ...string) (int, error)
+
+	// RPush 将一个或多个值依次插入列表的尾部，返回插入后列表的长度。
+	RPush(key string, values ...string) (int, error)
+
+	// LPop 弹出列表头部的值，键不存在时返回 ErrNotFound。
+	LPop(key string) (string, error)
+
+	// RPop 弹出列表尾部的值，键不存在时返回 ErrNotFound。
+	RPop(key string) (string, error)
+
+	// LRange 返回列表中索引在 [start, stop] 之间的值。
+	LRange(key string, start, stop int) ([]string, error)
+
+	// LLen 返回列表的长度，键不存在时返回 0。
+	LLen(key string) (int, error)
+
+	// BLPop 按顺序检查多个列表并弹出第一个非空列表头部的值，所有列表均为空时阻塞等待，返回列表的键及弹出的值。
+	// timeout 为最长的等待时间，必须大于 0，超时时返回 ErrNotFound。
+	BLPop(timeout time.Duration, keys ...string) (string, string, error)
+}
+
+// Hash 返回指定别名的存储的哈希表操作。
+// 别名未注册时返回 ErrNotRegistered，存储未实现 IHash 时返回 ErrNotSupported。
+func Hash(alias string) (IHash, error) {
+	return structFor[IHash](alias, "hash")
+}
+
+// SortedSet 返回指定别名的存储的有序集合操作。
+// 别名未注册时返回 ErrNotRegistered，存储未实现 ISortedSet 时返回 ErrNotSupported。
+func SortedSet(alias string) (ISortedSet, error) {
+	return structFor[ISortedSet](alias, "sorted set")
+}
+
+// List 返回指定别名的存储的列表操作。
+// 别名未注册时返回 ErrNotRegistered，存储未实现 IList 时返回 ErrNotSupported。
+func List(alias string) (IList, error) {
+	return structFor[IList](alias, "list")
+}
+
+// structFor 返回指定别名的存储实现的数据结构接口，name 为数据结构的名称。
+func structFor[T any](alias, name string) (T, error) {
+	var zero T
+	pairs := Of(alias)
+	if pairs == nil {
+		return zero, fmt.Errorf("%w: %v", ErrNotRegistered, alias)
+	}
+	impl, ok := pairs.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %v of %v", ErrNotSupported, name, alias)
+	}
+	return impl, nil
+}
+
+// rangeIndex 将 Redis 风格的 [start, stop] 索引转换为长度为 size 的切片的 [from, to) 区间，区间为空时 from 等于 to。
+func rangeIndex(start, stop, size int) (int, int) {
+	if start < 0 {
+		start = max(size+start, 0)
+	}
+	if stop < 0 {
+		stop = size + stop
+	}
+	if stop >= size {
+		stop = size - 1
+	}
+	if start > stop {
+		return 0, 0
+	}
+	return start, stop + 1
+}
+
+// sortZMembers 将有序集合的成员按分数升序排列，分数相同时按成员的字典序排列。
+func sortZMembers(zset map[string]float64) []ZMember {
+	members := make([]ZMember, 0, len(zset))
+	for member, score := range zset {
+		members = append(members, ZMember{Member: member, Score: score})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Score != members[j].Score {
+			return members[i].Score < members[j].Score
+		}
+		return members[i].Member < members[j].Member
+	})
+	return members
+}
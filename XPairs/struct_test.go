@@ -0,0 +1,269 @@
+// Copyright (c) 2025 EFramework Organization. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package XPairs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testStruct 是实现了所有数据结构接口的存储。
+type testStruct interface {
+	IPairs
+	IHash
+	ISortedSet
+	IList
+}
+
+// testHashConformance 校验哈希表的基本行为。
+func testHashConformance(t *testing.T, s testStruct) {
+	count, err := s.HSet("hash", map[string]string{"name": "alice", "level": "1"})
+	assert.NoError(t, err, "HSet 不应当返回错误。")
+	assert.Equal(t, 2, count, "新增的字段数量应当为 2。")
+	count, _ = s.HSet("hash", map[string]string{"name": "bob", "guild": "g1"})
+	assert.Equal(t, 1, count, "更新已存在的字段不应当计入新增的数量。")
+
+	value, err := s.HGet("hash", "name")
+	assert.NoError(t, err, "HGet 不应当返回错误。")
+	assert.Equal(t, "bob", value, "读取的字段值应当和写入的相等。")
+	_, err = s.HGet("hash", "missing")
+	assert.ErrorIs(t, err, ErrNotFound, "读取不存在的字段应当返回 ErrNotFound。")
+	_, err = s.HGet("missing", "name")
+	assert.ErrorIs(t, err, ErrNotFound, "读取不存在的键应当返回 ErrNotFound。")
+
+	level, err := s.HIncrBy("hash", "level", 5)
+	assert.NoError(t, err, "HIncrBy 不应当返回错误。")
+	assert.Equal(t, int64(6), level, "递增后的值应当为 6。")
+	level, _ = s.HIncrBy("hash", "exp", -3)
+	assert.Equal(t, int64(-3), level, "不存在的字段应当视为 0。")
+	_, err = s.HIncrBy("hash", "name", 1)
+	assert.Error(t, err, "递增非整数的字段应当返回错误。")
+
+	all, err := s.HGetAll("hash")
+	assert.NoError(t, err, "HGetAll 不应当返回错误。")
+	assert.Equal(t, map[string]string{"name": "bob", "level": "6", "guild": "g1", "exp": "-3"}, all, "读取的所有字段应当和预期相等。")
+	all, _ = s.HGetAll("missing")
+	assert.Empty(t, all, "读取不存在的键应当返回空的结果。")
+
+	count, err = s.HDel("hash", "name", "missing")
+	assert.NoError(t, err, "HDel 不应当返回错误。")
+	assert.Equal(t, 1, count, "被删除的字段数量应当为 1。")
+	s.HDel("hash", "level", "guild", "exp")
+	exists, _ := s.Exists("hash")
+	assert.Equal(t, 0, exists, "所有字段被删除后键应当被删除。")
+}
+
+// testZSetConformance 校验有序集合的基本行为。
+func testZSetConformance(t *testing.T, s testStruct) {
+	count, err := s.ZAdd("zset", map[string]float64{"a": 10, "b": 20, "c": 20, "d": 5})
+	assert.NoError(t, err, "ZAdd 不应当返回错误。")
+	assert.Equal(t, 4, count, "新增的成员数量应当为 4。")
+	count, _ = s.ZAdd("zset", map[string]float64{"d": 30, "e": 1.5})
+	assert.Equal(t, 1, count, "更新已存在的成员不应当计入新增的数量。")
+
+	score, err := s.ZIncrBy("zset", "a", 2.5)
+	assert.NoError(t, err, "ZIncrBy 不应当返回错误。")
+	assert.Equal(t, 12.5, score, "递增后的分数应当为 12.5。")
+	score, _ = s.ZIncrBy("zset", "f", -1)
+	assert.Equal(t, -1.0, score, "不存在的成员应当视为 0。")
+	score, err = s.ZScore("zset", "d")
+	assert.NoError(t, err, "ZScore 不应当返回错误。")
+	assert.Equal(t, 30.0, score, "读取的分数应当和写入的相等。")
+	_, err = s.ZScore("zset", "missing")
+	assert.ErrorIs(t, err, ErrNotFound, "读取不存在的成员应当返回 ErrNotFound。")
+
+	members, err := s.ZRange("zset", 0, -1)
+	assert.NoError(t, err, "ZRange 不应当返回错误。")
+	assert.Equal(t, []ZMember{{"f", -1}, {"e", 1.5}, {"a", 12.5}, {"b", 20}, {"c", 20}, {"d", 30}}, members, "成员应当按分数升序排列，分数相同时按字典序排列。")
+	members, _ = s.ZRevRange("zset", 0, 2)
+	assert.Equal(t, []ZMember{{"d", 30}, {"c", 20}, {"b", 20}}, members, "成员应当按分数降序排列，分数相同时按字典序逆序排列。")
+	members, _ = s.ZRange("zset", -2, 100)
+	assert.Equal(t, []ZMember{{"c", 20}, {"d", 30}}, members, "负数的索引应当从末尾倒数。")
+	members, _ = s.ZRange("zset", 5, 2)
+	assert.Empty(t, members, "起始索引大于结束索引时应当返回空的结果。")
+	members, _ = s.ZRange("missing", 0, -1)
+	assert.Empty(t, members, "读取不存在的键应当返回空的结果。")
+
+	members, err = s.ZRangeByScore("zset", 1.5, 20, 0, -1)
+	assert.NoError(t, err, "ZRangeByScore 不应当返回错误。")
+	assert.Equal(t, []ZMember{{"e", 1.5}, {"a", 12.5}, {"b", 20}, {"c", 20}}, members, "应当按分数升序返回分数范围内的成员。")
+	members, _ = s.ZRangeByScore("zset", 1.5, 20, 1, 2)
+	assert.Equal(t, []ZMember{{"a", 12.5}, {"b", 20}}, members, "应当跳过 offset 个成员后最多返回 count 个。")
+	members, err = s.ZRevRangeByScore("zset", 1.5, 20, 1, 2)
+	assert.NoError(t, err, "ZRevRangeByScore 不应当返回错误。")
+	assert.Equal(t, []ZMember{{"b", 20}, {"a", 12.5}}, members, "应当按分数降序返回分数范围内的成员。")
+	members, _ = s.ZRangeByScore("missing", 0, 100, 0, -1)
+	assert.Empty(t, members, "读取不存在的键应当返回空的结果。")
+
+	rank, err := s.ZRank("zset", "b")
+	assert.NoError(t, err, "ZRank 不应当返回错误。")
+	assert.Equal(t, 3, rank, "升序的排名应当为 3。")
+	rank, _ = s.ZRevRank("zset", "b")
+	assert.Equal(t, 2, rank, "降序的排名应当为 2。")
+	_, err = s.ZRank("zset", "missing")
+	assert.ErrorIs(t, err, ErrNotFound, "不存在的成员的排名应当返回 ErrNotFound。")
+	_, err = s.ZRevRank("missing", "a")
+	assert.ErrorIs(t, err, ErrNotFound, "不存在的键的排名应当返回 ErrNotFound。")
+
+	card, err := s.ZCard("zset")
+	assert.NoError(t, err, "ZCard 不应当返回错误。")
+	assert.Equal(t, 6, card, "成员数量应当为 6。")
+	count, err = s.ZRem("zset", "a", "missing")
+	assert.NoError(t, err, "ZRem 不应当返回错误。")
+	assert.Equal(t, 1, count, "被删除的成员数量应当为 1。")
+	s.ZRem("zset", "b", "c", "d", "e", "f")
+	card, _ = s.ZCard("zset")
+	assert.Equal(t, 0, card, "所有成员被删除后成员数量应当为 0。")
+	exists, _ := s.Exists("zset")
+	assert.Equal(t, 0, exists, "所有成员被删除后键应当被删除。")
+}
+
+// testListConformance 校验列表的基本行为。
+func testListConformance(t *testing.T, s testStruct) {
+	length, err := s.LPush("list", "b", "a")
+	assert.NoError(t, err, "LPush 不应当返回错误。")
+	assert.Equal(t, 2, length, "插入后列表的长度应当为 2。")
+	length, _ = s.RPush("list", "c", "d")
+	assert.Equal(t, 4, length, "插入后列表的长度应当为 4。")
+
+	values, err := s.LRange("list", 0, -1)
+	assert.NoError(t, err, "LRange 不应当返回错误。")
+	assert.Equal(t, []string{"a", "b", "c", "d"}, values, "LPush 应当依次插入头部，RPush 应当依次插入尾部。")
+	values, _ = s.LRange("list", 1, -2)
+	assert.Equal(t, []string{"b", "c"}, values, "负数的索引应当从末尾倒数。")
+	values, _ = s.LRange("missing", 0, -1)
+	assert.Empty(t, values, "读取不存在的键应当返回空的结果。")
+
+	value, err := s.RPop("list")
+	assert.NoError(t, err, "RPop 不应当返回错误。")
+	assert.Equal(t, "d", value, "RPop 应当弹出尾部的值。")
+	value, _ = s.LPop("list")
+	assert.Equal(t, "a", value, "LPop 应当弹出头部的值。")
+	length, _ = s.LLen("list")
+	assert.Equal(t, 2, length, "弹出后列表的长度应当为 2。")
+
+	key, value, err := s.BLPop(time.Second, "missing", "list")
+	assert.NoError(t, err, "BLPop 不应当返回错误。")
+	assert.Equal(t, "list", key, "BLPop 应当返回第一个非空列表的键。")
+	assert.Equal(t, "b", value, "BLPop 应当弹出头部的值。")
+	s.LPop("list")
+	_, err = s.LPop("list")
+	assert.ErrorIs(t, err, ErrNotFound, "弹出空的列表应当返回 ErrNotFound。")
+	exists, _ := s.Exists("list")
+	assert.Equal(t, 0, exists, "所有元素被弹出后键应当被删除。")
+
+	start := time.Now()
+	_, _, err = s.BLPop(200*time.Millisecond, "list")
+	assert.ErrorIs(t, err, ErrNotFound, "BLPop 超时时应当返回 ErrNotFound。")
+	assert.True(t, time.Since(start) >= 150*time.Millisecond, "BLPop 应当阻塞至超时。")
+	_, _, err = s.BLPop(0, "list")
+	assert.Error(t, err, "BLPop 的超时时间小于等于 0 时应当返回错误。")
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		s.RPush("queue", "job")
+	}()
+	key, value, err = s.BLPop(3*time.Second, "queue")
+	assert.NoError(t, err, "BLPop 等待期间插入的值应当被弹出。")
+	assert.Equal(t, "queue", key, "BLPop 应当返回插入值的列表的键。")
+	assert.Equal(t, "job", value, "BLPop 应当弹出等待期间插入的值。")
+}
+
+// testWrongTypeConformance 校验对类型不匹配的键执行操作时返回 ErrWrongType。
+func testWrongTypeConformance(t *testing.T, s testStruct) {
+	s.Set("str", "v", 0)
+	s.HSet("typed", map[string]string{"f": "v"})
+	_, err := s.HGet("str", "f")
+	assert.ErrorIs(t, err, ErrWrongType, "对字符串执行哈希表的操作应当返回 ErrWrongType。")
+	_, err = s.ZAdd("str", map[string]float64{"m": 1})
+	assert.ErrorIs(t, err, ErrWrongType, "对字符串执行有序集合的操作应当返回 ErrWrongType。")
+	_, err = s.LPush("typed", "v")
+	assert.ErrorIs(t, err, ErrWrongType, "对哈希表执行列表的操作应当返回 ErrWrongType。")
+	_, err = s.Get("typed")
+	assert.ErrorIs(t, err, ErrWrongType, "读取哈希表的字符串值应当返回 ErrWrongType。")
+	_, _, err = s.BLPop(time.Second, "typed")
+	assert.ErrorIs(t, err, ErrWrongType, "对哈希表执行 BLPop 应当返回 ErrWrongType。")
+
+	assert.NoError(t, s.Set("typed", "v", 0), "Set 应当覆盖其他类型的键。")
+	value, _ := s.Get("typed")
+	assert.Equal(t, "v", value, "覆盖后读取的值应当和写入的相等。")
+}
+
+func TestStruct(t *testing.T) {
+	t.Run("Range", func(t *testing.T) {
+		tests := []struct {
+			start, stop, size int
+			from, to          int
+		}{
+			{0, -1, 5, 0, 5},
+			{1, 3, 5, 1, 4},
+			{-2, -1, 5, 3, 5},
+			{-10, 2, 5, 0, 3},
+			{3, 100, 5, 3, 5},
+			{4, 2, 5, 0, 0},
+			{5, 10, 5, 0, 0},
+			{0, -1, 0, 0, 0},
+		}
+		for _, test := range tests {
+			from, to := rangeIndex(test.start, test.stop, test.size)
+			assert.Equal(t, [2]int{test.from, test.to}, [2]int{from, to}, "索引的转换结果应当和预期相等：%v %v %v", test.start, test.stop, test.size)
+		}
+	})
+
+	t.Run("Memory", func(t *testing.T) {
+		mc := NewMemory()
+		testHashConformance(t, mc)
+		testZSetConformance(t, mc)
+		testListConformance(t, mc)
+		testWrongTypeConformance(t, mc)
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			mc.Close()
+		}()
+		_, _, err := mc.BLPop(3*time.Second, "list")
+		assert.ErrorIs(t, err, ErrClosed, "关闭存储后等待中的 BLPop 应当返回 ErrClosed。")
+		_, err = mc.HSet("hash", map[string]string{"f": "v"})
+		assert.ErrorIs(t, err, ErrClosed, "关闭后 HSet 应当返回 ErrClosed。")
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		server := newTestRedisServer(t)
+		rc, _ := NewRedis(server.Addr(), 2, 8)
+		defer rc.Close()
+		testHashConformance(t, rc)
+		testZSetConformance(t, rc)
+		testListConformance(t, rc)
+		testWrongTypeConformance(t, rc)
+	})
+
+	t.Run("Alias", func(t *testing.T) {
+		Register("struct_test", NewMemory())
+		defer func() {
+			Of("struct_test").Close()
+			pairsMap.Delete("struct_test")
+		}()
+
+		hash, err := Hash("struct_test")
+		assert.NoError(t, err, "Hash 不应当返回错误。")
+		hash.HSet("alias", map[string]string{"f": "v"})
+		zset, err := SortedSet("struct_test")
+		assert.NoError(t, err, "SortedSet 不应当返回错误。")
+		_, err = zset.ZAdd("alias", map[string]float64{"m": 1})
+		assert.ErrorIs(t, err, ErrWrongType, "同一存储的类型不匹配时应当返回 ErrWrongType。")
+		list, err := List("struct_test")
+		assert.NoError(t, err, "List 不应当返回错误。")
+		assert.NotNil(t, list, "List 应当返回列表操作。")
+
+		_, err = Hash("struct_missing")
+		assert.ErrorIs(t, err, ErrNotRegistered, "未注册的别名应当返回 ErrNotRegistered。")
+		Register("struct_consul", &ConsulClient{})
+		defer pairsMap.Delete("struct_consul")
+		_, err = List("struct_consul")
+		assert.ErrorIs(t, err, ErrNotSupported, "存储未实现 IList 时应当返回 ErrNotSupported。")
+	})
+}